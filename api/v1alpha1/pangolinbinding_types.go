@@ -5,17 +5,22 @@ import (
 )
 
 // PangolinBindingSpec defines the desired state of PangolinBinding
+// +kubebuilder:validation:XValidation:rule="has(self.servicePort) || has(self.servicePortName)",message="servicePort or servicePortName is required"
 type PangolinBindingSpec struct {
 	// Reference to the Kubernetes Service to expose
 	// +kubebuilder:validation:Required
 	ServiceRef ServiceReference `json:"serviceRef"`
 
 	// Reference to the organization to use
+	// An organization in another namespace can only be referenced when the
+	// operator runs with --allow-cross-namespace-org
 	// +kubebuilder:validation:Required
 	OrganizationRef LocalObjectReference `json:"organizationRef"`
 
 	// Optional: Reference to specific tunnel
 	// If not specified, will use/create default tunnel for the organization
+	// A tunnel in another namespace can only be referenced when the operator
+	// runs with --allow-cross-namespace-tunnel, and it must use organizationRef
 	TunnelRef *LocalObjectReference `json:"tunnelRef,omitempty"`
 
 	// Protocol type
@@ -26,7 +31,14 @@ type PangolinBindingSpec struct {
 	// Port on the service to expose
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=65535
-	ServicePort int32 `json:"servicePort"`
+	// +optional
+	ServicePort int32 `json:"servicePort,omitempty"`
+
+	// ServicePortName is the name of the service port to expose, resolved
+	// against the Service's ports on every reconcile. Takes precedence over
+	// servicePort.
+	// +optional
+	ServicePortName string `json:"servicePortName,omitempty"`
 
 	// HTTP-specific configuration
 	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
@@ -35,10 +47,69 @@ type PangolinBindingSpec struct {
 	ProxyConfig *ProxyConfig `json:"proxyConfig,omitempty"`
 
 	// Auto-update targets based on Service endpoints
+	// When enabled, the resource gets one target per ready Service endpoint,
+	// otherwise a single target for the Service ClusterIP
 	// +kubebuilder:default=true
 	AutoUpdateTargets *bool `json:"autoUpdateTargets,omitempty"`
+
+	// TargetMode selects the address the generated resource targets:
+	// clusterIP targets the Service ClusterIP, dns the Service DNS name
+	// <service>.<namespace>.svc.cluster.local, endpoints one target per
+	// ready Service endpoint, and loadBalancer the ingress of a LoadBalancer
+	// Service. Defaults to endpoints with autoUpdateTargets, else clusterIP;
+	// autoUpdateTargets is ignored when it is set. ExternalName Services are
+	// targeted by their external name unless clusterIP is set explicitly.
+	// +kubebuilder:validation:Enum=clusterIP;dns;endpoints;loadBalancer
+	// +optional
+	TargetMode string `json:"targetMode,omitempty"`
+
+	// StaticTargets are added to the Service-derived targets, for backends
+	// outside the cluster (e.g. an external database). They are always kept
+	// in the resource's target set.
+	// +optional
+	StaticTargets []TargetConfig `json:"staticTargets,omitempty"`
+
+	// StickySession of the generated resource. Defaults to true when the
+	// Service uses sessionAffinity: ClientIP.
+	// +optional
+	StickySession *bool `json:"stickySession,omitempty"`
+
+	// EndpointWeights weights the per-endpoint targets by a label of their
+	// pods, e.g. to send a share of the traffic to canary pods. Only used
+	// while the binding targets the Service endpoints.
+	// +optional
+	EndpointWeights *EndpointWeights `json:"endpointWeights,omitempty"`
 }
 
+// EndpointWeights assigns the targets of Service endpoints a weight by the
+// value of a label of the pod behind each endpoint.
+//
+// The weight applies to each endpoint, so the share of traffic a group of pods
+// receives also depends on how many endpoints it has.
+type EndpointWeights struct {
+	// LabelKey is the pod label whose value selects the weight of an endpoint
+	// +kubebuilder:validation:MinLength=1
+	LabelKey string `json:"labelKey"`
+
+	// Weights maps values of the label to the weight of the endpoints of
+	// those pods
+	Weights map[string]int32 `json:"weights"`
+
+	// DefaultWeight of endpoints whose pod has no weight in weights, or that
+	// have no pod. Unset leaves them without a weight.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DefaultWeight *int32 `json:"defaultWeight,omitempty"`
+}
+
+// Target modes of a PangolinBinding
+const (
+	TargetModeClusterIP    = "clusterIP"
+	TargetModeDNS          = "dns"
+	TargetModeEndpoints    = "endpoints"
+	TargetModeLoadBalancer = "loadBalancer"
+)
+
 // ServiceReference contains enough information to locate a service
 type ServiceReference struct {
 	// +kubebuilder:validation:Required
@@ -62,15 +133,35 @@ type PangolinBindingStatus struct {
 	// Service endpoints currently being targeted
 	ServiceEndpoints []string `json:"serviceEndpoints,omitempty"`
 
-	// Current status: Creating, Ready, Error, Updating
-	// +kubebuilder:validation:Enum=Creating;Ready;Error;Updating
+	// ServiceEndpointWeights are the weights of the Service endpoints by
+	// address, derived from spec.endpointWeights
+	// +optional
+	ServiceEndpointWeights map[string]int32 `json:"serviceEndpointWeights,omitempty"`
+
+	// TargetAddress is the Service address targeted by the generated resource,
+	// empty while it targets the Service endpoints
+	TargetAddress string `json:"targetAddress,omitempty"`
+
+	// Current status: Creating, Ready, Error, Updating, Waiting for a
+	// dependency, or ServiceMissing while the referenced Service does not exist
+	// +kubebuilder:validation:Enum=Creating;Ready;Error;Updating;Waiting;ServiceMissing
 	Status string `json:"status,omitempty"`
 
+	// ServiceMissingSince is when the referenced Service was first found
+	// missing, cleared once it exists again
+	// +optional
+	ServiceMissingSince *metav1.Time `json:"serviceMissingSince,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// ObservedGeneration reflects the generation most recently observed
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is when the binding was last reconciled successfully,
+	// refreshed at most every few minutes while nothing else changes
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -80,6 +171,7 @@ type PangolinBindingStatus struct {
 //+kubebuilder:printcolumn:name="Protocol",type=string,JSONPath=`.spec.protocol`
 //+kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.url`
 //+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // PangolinBinding is the Schema for the pangolinbindings API
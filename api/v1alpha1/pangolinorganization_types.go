@@ -7,7 +7,8 @@ import (
 
 // PangolinOrganizationSpec defines the desired state of PangolinOrganization
 type PangolinOrganizationSpec struct {
-	// Pangolin API configuration
+	// Pangolin API configuration: the http or https URL the API is served
+	// under, without the API base path (e.g. https://api.pangolin.example.com)
 	// +kubebuilder:validation:Required
 	APIEndpoint string `json:"apiEndpoint"`
 
@@ -15,29 +16,102 @@ type PangolinOrganizationSpec struct {
 	// +kubebuilder:validation:Required
 	APIKeyRef corev1.SecretKeySelector `json:"apiKeyRef"`
 
+	// Optional second API key, tried when the Pangolin API rejects apiKeyRef
+	// with 401. Once it is accepted it is used until it is rejected itself, so
+	// a key can be rotated by adding the new one here before revoking the old
+	// one. status.activeAPIKey shows which key is in use. Applies to every
+	// client using apiKeyRef, not to siteAPIKeyRef or resourceAPIKeyRef.
+	// +optional
+	APIKeyFallbackRef *corev1.SecretKeySelector `json:"apiKeyFallbackRef,omitempty"`
+
+	// Optional API key used by the tunnel controller for site operations.
+	// Falls back to apiKeyRef when not set.
+	// +optional
+	SiteAPIKeyRef *corev1.SecretKeySelector `json:"siteAPIKeyRef,omitempty"`
+
+	// Optional API key used by the resource controller for resource and target operations.
+	// Falls back to apiKeyRef when not set.
+	// +optional
+	ResourceAPIKeyRef *corev1.SecretKeySelector `json:"resourceAPIKeyRef,omitempty"`
+
+	// Path the Pangolin Integration API is served under, relative to
+	// apiEndpoint, e.g. /v2 or /api/v1. "/" uses apiEndpoint itself.
+	// Defaults to /v1.
+	// +kubebuilder:validation:Pattern=`^/[^?#]*$`
+	// +optional
+	APIBasePath string `json:"apiBasePath,omitempty"`
+
+	// Timeout in seconds of each request to the Pangolin API. Defaults to 30.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	APITimeoutSeconds *int32 `json:"apiTimeoutSeconds,omitempty"`
+
+	// ConfigMap key holding PEM encoded CA certificates trusted for the Pangolin
+	// API in addition to the system roots, e.g. for a self-signed control plane.
+	// The ConfigMap must be in the organization's namespace.
+	// +optional
+	CABundleRef *corev1.ConfigMapKeySelector `json:"caBundleRef,omitempty"`
+
+	// Secret whose keys and values are sent as additional headers with every
+	// request to the Pangolin API, e.g. CF-Access-Client-Id and
+	// CF-Access-Client-Secret when the API is behind Cloudflare Access. They
+	// cannot replace the Authorization, Content-Type and User-Agent headers.
+	// The Secret must be in the organization's namespace.
+	// +optional
+	APIHeadersSecretRef *corev1.LocalObjectReference `json:"apiHeadersSecretRef,omitempty"`
+
+	// Sets the RateLimitLow condition while the API reports fewer remaining
+	// requests in its rate limit headers than this. Unset disables the
+	// condition; the pangolin_api_rate_limit_* metrics are exported regardless.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RateLimitWarningThreshold *int32 `json:"rateLimitWarningThreshold,omitempty"`
+
 	// BINDING MODE: Organization ID to bind to existing org
 	// If provided, binds to existing org instead of discovering
 	OrganizationID string `json:"organizationId,omitempty"`
 
+	// DisableDiscovery requires organizationId instead of discovering the
+	// organization the API key can access, so a misconfigured organization
+	// cannot attach to the wrong one in a shared Pangolin instance
+	// +optional
+	DisableDiscovery *bool `json:"disableDiscovery,omitempty"`
+
 	// Display name (used for new orgs, updated from API for existing)
 	DisplayName string `json:"displayName,omitempty"`
 
+	// PauseChildren propagates the tunnel.pangolin.io/paused annotation on this
+	// organization to the tunnels, resources and bindings that use it
+	// +optional
+	PauseChildren *bool `json:"pauseChildren,omitempty"`
+
 	// Default configuration for tunnels in this org
 	Defaults *OrganizationDefaults `json:"defaults,omitempty"`
 }
 
 // OrganizationDefaults defines default settings for tunnels
 type OrganizationDefaults struct {
-	// Default site type for tunnels
+	// Default site type for tunnels that do not set spec.siteType
 	// +kubebuilder:validation:Enum=newt;wireguard;local
 	// +kubebuilder:default="newt"
 	SiteType string `json:"siteType,omitempty"`
 
+	// SiteNamePrefix is prepended to the tunnel name to name the sites of
+	// tunnels that do not set spec.siteName, e.g. "k8s-prod-"
+	// +optional
+	SiteNamePrefix string `json:"siteNamePrefix,omitempty"`
+
 	// Default Newt client configuration
 	NewtClient *NewtClientSpec `json:"newtClient,omitempty"`
 
 	// Default domain for HTTP resources - NEW: can be domain name or domainId
 	DefaultDomain string `json:"defaultDomain,omitempty"`
+
+	// Default target method per resource protocol (e.g., http: https), used for
+	// targets that do not set a method
+	// +kubebuilder:validation:XValidation:rule="self.all(k, self[k] in ['http', 'https', 'tcp', 'udp'])",message="target methods must be one of http, https, tcp, udp"
+	// +optional
+	TargetMethods map[string]string `json:"targetMethods,omitempty"`
 }
 
 // Domain represents a Pangolin domain
@@ -69,6 +143,18 @@ type PangolinOrganizationStatus struct {
 	// Organization ID from Pangolin API (discovered or confirmed)
 	OrganizationID string `json:"organizationId,omitempty"`
 
+	// APIEndpoint is spec.apiEndpoint as validated and normalized by the
+	// operator, the endpoint all Pangolin clients of the organization use
+	// +optional
+	APIEndpoint string `json:"apiEndpoint,omitempty"`
+
+	// ActiveAPIKey is the API key the organization authenticated with last:
+	// "Primary" for apiKeyRef or "Fallback" for apiKeyFallbackRef. Empty
+	// without apiKeyFallbackRef.
+	// +kubebuilder:validation:Enum=Primary;Fallback
+	// +optional
+	ActiveAPIKey string `json:"activeAPIKey,omitempty"`
+
 	// Organization name from API
 	OrganizationName string `json:"organizationName,omitempty"`
 
@@ -78,9 +164,21 @@ type PangolinOrganizationStatus struct {
 	// Available domains for this organization
 	Domains []Domain `json:"domains,omitempty"`
 
+	// Number of domains in domains, for the printer columns
+	// +optional
+	DomainCount int `json:"domainCount"`
+
 	// Default domain ID resolved from spec.defaults.defaultDomain
 	DefaultDomainID string `json:"defaultDomainId,omitempty"`
 
+	// Pangolin server version, empty if the server does not report it
+	// +optional
+	ServerVersion string `json:"serverVersion,omitempty"`
+
+	// Optional features supported by the Pangolin server
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+
 	// Binding mode: "Discovered" (auto-discovered) or "Bound" (explicitly bound)
 	BindingMode string `json:"bindingMode,omitempty"`
 
@@ -91,6 +189,22 @@ type PangolinOrganizationStatus struct {
 	// Conditions and timestamps
 	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is when the organization was last read from Pangolin
+	// successfully. It is refreshed at most every few minutes while the rest
+	// of the status stays the same.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// HasCapability reports whether the Pangolin server advertised the named capability.
+func (s *PangolinOrganizationStatus) HasCapability(name string) bool {
+	for _, c := range s.Capabilities {
+		if c == name {
+			return true
+		}
+	}
+	return false
 }
 
 //+kubebuilder:object:root=true
@@ -98,10 +212,12 @@ type PangolinOrganizationStatus struct {
 //+kubebuilder:resource:shortName=porg
 //+kubebuilder:printcolumn:name="Org ID",type=string,JSONPath=`.status.organizationId`
 //+kubebuilder:printcolumn:name="Org Name",type=string,JSONPath=`.status.organizationName`
-//+kubebuilder:printcolumn:name="Domains",type=integer,JSONPath=`.status.domains[*].domainId`
+//+kubebuilder:printcolumn:name="Subnet",type=string,JSONPath=`.status.subnet`
+//+kubebuilder:printcolumn:name="Domains",type=integer,JSONPath=`.status.domainCount`
 //+kubebuilder:printcolumn:name="Default Domain",type=string,JSONPath=`.status.defaultDomainId`
 //+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
 //+kubebuilder:printcolumn:name="Binding Mode",type=string,JSONPath=`.status.bindingMode`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // PangolinOrganization is the Schema for the pangolinorganizations API
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -17,8 +18,10 @@ type SiteReference struct {
 }
 
 // PangolinResourceSpec defines the desired state of PangolinResource
+// +kubebuilder:validation:XValidation:rule="(has(self.resourceId) ? 1 : 0) + (has(self.bindByName) ? 1 : 0) + (has(self.bindBySubdomain) ? 1 : 0) <= 1",message="only one of resourceId, bindByName and bindBySubdomain may be set"
 type PangolinResourceSpec struct {
-	// Reference to the tunnel this resource belongs to
+	// Reference to the tunnel this resource belongs to, which may be in
+	// another namespace
 	// +optional
 	TunnelRef LocalObjectReference `json:"tunnelRef,omitempty"`
 
@@ -32,9 +35,21 @@ type PangolinResourceSpec struct {
 	Protocol string `json:"protocol,omitempty"`
 
 	// BINDING MODE: Resource ID to bind to existing resource
-	// If specified, will bind to existing resource instead of creating new one
+	// If specified, will bind to existing resource instead of creating new one.
+	// httpConfig and proxyConfig are ignored while bound.
 	ResourceID string `json:"resourceId,omitempty"`
 
+	// BINDING MODE: Name of an existing resource to bind to, instead of its ID.
+	// Exactly one resource of the organization must have this name.
+	// +optional
+	BindByName string `json:"bindByName,omitempty"`
+
+	// BINDING MODE: Subdomain or full domain (e.g. app.example.com) of an
+	// existing HTTP resource to bind to, instead of its ID.
+	// Exactly one resource of the organization must match.
+	// +optional
+	BindBySubdomain string `json:"bindBySubdomain,omitempty"`
+
 	// HTTP-specific configuration
 	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
 
@@ -45,9 +60,62 @@ type PangolinResourceSpec struct {
 	// +optional
 	Targets []TargetConfig `json:"targets,omitempty"`
 
+	// Canary splits traffic between a stable and a canary target by percentage.
+	// The two targets are managed in addition to spec.targets.
+	// +optional
+	Canary *CanaryConfig `json:"canary,omitempty"`
+
 	// Enable/disable this resource
 	// +kubebuilder:default=true
 	Enabled *bool `json:"enabled,omitempty"`
+
+	// Schedule enables the resource only during recurring windows, e.g. work
+	// hours. Outside of all windows the resource and its targets are disabled.
+	// +optional
+	Schedule *ResourceSchedule `json:"schedule,omitempty"`
+
+	// Suspend takes the resource offline, e.g. for maintenance, by disabling
+	// its targets. The Pangolin resource, its ID and its domain are kept, and
+	// the targets are enabled again when suspend is unset or false.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// StickySession routes each client to the same target for the duration of
+	// its session. Left unchanged in Pangolin when not set.
+	// +optional
+	StickySession *bool `json:"stickySession,omitempty"`
+
+	// Tags set on the resource in Pangolin, e.g. team or environment, to group
+	// resources in the dashboard. Tags changed in Pangolin are reset to these.
+	// Left unchanged in Pangolin when not set; an empty map removes all tags.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// WaitForHealthy keeps the resource Waiting after its targets are
+	// configured until every enabled target with a health check reports healthy
+	// in Pangolin. Targets without a health check are not waited for.
+	// Defaults to false, marking the resource Ready once its targets are configured.
+	// +optional
+	WaitForHealthy *bool `json:"waitForHealthy,omitempty"`
+
+	// What happens to the Pangolin resource when this object is deleted.
+	// Defaults to Delete for created resources and Retain for bound ones.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// BindField returns the field binding the resource to an existing Pangolin
+// resource, e.g. "spec.resourceId", or "" if the resource creates its own.
+func (s *PangolinResourceSpec) BindField() string {
+	switch {
+	case s.ResourceID != "":
+		return "spec.resourceId"
+	case s.BindByName != "":
+		return "spec.bindByName"
+	case s.BindBySubdomain != "":
+		return "spec.bindBySubdomain"
+	}
+	return ""
 }
 
 // HTTPConfig defines HTTP-specific resource configuration - ENHANCED
@@ -67,6 +135,25 @@ type HTTPConfig struct {
 	// If neither domainId nor domainName is specified,
 	// will use the organization's default domain
 
+	// NoDefaultDomain disables the organization default domain fallback.
+	// When true, domainId or domainName must be set explicitly; otherwise the
+	// resource fails with reason DomainRequired on its Ready condition.
+	// +optional
+	NoDefaultDomain *bool `json:"noDefaultDomain,omitempty"`
+
+	// Port the resource is served on, for non-standard HTTPS ports.
+	// Defaults to 443 when not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// URLScheme of the URL reported in status.url, for setups serving plain
+	// http (e.g. behind a TLS-terminating proxy). Defaults to https.
+	// +kubebuilder:validation:Enum=http;https
+	// +optional
+	URLScheme string `json:"urlScheme,omitempty"`
+
 	// SSO enables SSO authentication for this resource
 	// +optional
 	SSO bool `json:"sso"`
@@ -75,6 +162,44 @@ type HTTPConfig struct {
 	// Only effective when SSO is enabled
 	// +optional
 	BlockAccess bool `json:"blockAccess"`
+
+	// AccessRules allow or deny requests by client address or path. Rules are
+	// evaluated in order, the first matching rule wins.
+	// +optional
+	AccessRules []AccessRule `json:"accessRules,omitempty"`
+
+	// Auth protects the resource with a password or PIN code, in addition to
+	// or instead of SSO
+	// +optional
+	Auth *ResourceAuth `json:"auth,omitempty"`
+}
+
+// ResourceAuth references the credentials protecting an HTTP resource. The
+// Secrets must be in the namespace of the PangolinResource.
+type ResourceAuth struct {
+	// PasswordSecretRef selects the key of a Secret holding the password
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// PincodeSecretRef selects the key of a Secret holding a 6 digit PIN code
+	// +optional
+	PincodeSecretRef *corev1.SecretKeySelector `json:"pincodeSecretRef,omitempty"`
+}
+
+// AccessRule allows or denies requests to an HTTP resource
+type AccessRule struct {
+	// Action taken for matching requests
+	// +kubebuilder:validation:Enum=allow;deny
+	Action string `json:"action"`
+
+	// Match is what value is compared to: the client address (cidr) or the
+	// request path (path)
+	// +kubebuilder:validation:Enum=cidr;path
+	Match string `json:"match"`
+
+	// Value is a CIDR (e.g. "203.0.113.0/24") or a path pattern (e.g. "/admin/*")
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
 }
 
 // ProxyConfig defines TCP/UDP proxy configuration
@@ -99,8 +224,11 @@ type TargetConfig struct {
 	// +kubebuilder:validation:Required
 	Port int32 `json:"port"`
 	// Target method/protocol
-	// +kubebuilder:validation:Enum=http;https;tcp;udp
-	// +kubebuilder:default="http"
+	// Defaults to the organization's defaults.targetMethods entry for the resource
+	// protocol, then to the protocol itself. "auto" probes the backend and uses
+	// https if it completes a TLS handshake, http otherwise.
+	// +kubebuilder:validation:Enum=http;https;tcp;udp;auto
+	// +optional
 	Method string `json:"method,omitempty"`
 	// Path to match for routing (e.g., "/api")
 	// +optional
@@ -115,6 +243,90 @@ type TargetConfig struct {
 	// +kubebuilder:default=100
 	// +optional
 	Priority int32 `json:"priority,omitempty"`
+	// Weight for load balancing, relative to the other targets of the resource
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+	// Enabled is whether the target receives traffic. A disabled target is kept
+	// in Pangolin. Targets of a disabled resource are always disabled.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Secret with a client certificate (tls.crt) and key (tls.key) presented to the
+	// backend for mutual TLS. The Secret must be in the resource's namespace.
+	// +optional
+	ClientCertSecretRef *corev1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
+	// HealthCheck makes Pangolin probe the target over HTTP and stop sending
+	// traffic to it while unhealthy. Left unchanged in Pangolin when not set.
+	// Only supported on targets of http resources.
+	// +optional
+	HealthCheck *TargetHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// TargetHealthCheck defines the HTTP health check Pangolin runs against a target
+type TargetHealthCheck struct {
+	// Enabled turns the health check on or off
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Path requested by the health check
+	// +kubebuilder:default="/"
+	// +optional
+	Path string `json:"path,omitempty"`
+	// IntervalSeconds is the time between two checks
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=30
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+	// TimeoutSeconds is how long a check waits for a response
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=5
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// ExpectedStatus is the HTTP status code of a healthy response. Any 2xx
+	// status is healthy when not set.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	ExpectedStatus *int32 `json:"expectedStatus,omitempty"`
+}
+
+// CanaryConfig defines a percentage based traffic split between two targets
+type CanaryConfig struct {
+	// Stable target receiving the remaining traffic
+	// +kubebuilder:validation:Required
+	Stable TargetConfig `json:"stable"`
+	// Canary target receiving percent of the traffic
+	// +kubebuilder:validation:Required
+	Canary TargetConfig `json:"canary"`
+	// Percentage of traffic sent to the canary target
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+}
+
+// ResourceSchedule defines when a resource is enabled
+type ResourceSchedule struct {
+	// Windows during which the resource is enabled
+	// +kubebuilder:validation:MinItems=1
+	Windows []ScheduleWindow `json:"windows"`
+
+	// TimeZone the cron expressions are evaluated in, as an IANA name
+	// (e.g. "Europe/Berlin"). Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ScheduleWindow is a recurring window opened and closed by cron expressions
+// (minute hour day-of-month month day-of-week)
+type ScheduleWindow struct {
+	// Start opens the window, e.g. "0 8 * * 1-5"
+	// +kubebuilder:validation:MinLength=1
+	Start string `json:"start"`
+
+	// End closes the window, e.g. "0 18 * * 1-5"
+	// +kubebuilder:validation:MinLength=1
+	End string `json:"end"`
 }
 
 // LocalObjectReference contains enough information to locate a resource
@@ -127,6 +339,35 @@ type LocalObjectReference struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// DeletionPolicy controls what happens to a Pangolin object when its custom resource is deleted
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the object from Pangolin
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain leaves the object in Pangolin
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan leaves the object in Pangolin and removes owner references
+	// from child objects so they are not garbage collected
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// ReconcileAction is what a reconcile did to the remote Pangolin object
+// +kubebuilder:validation:Enum=Created;Updated;Adopted;NoOp
+type ReconcileAction string
+
+const (
+	// ReconcileActionCreated means the object was created in Pangolin
+	ReconcileActionCreated ReconcileAction = "Created"
+	// ReconcileActionUpdated means the object or its children were changed in Pangolin
+	ReconcileActionUpdated ReconcileAction = "Updated"
+	// ReconcileActionAdopted means an existing Pangolin object was bound to
+	ReconcileActionAdopted ReconcileAction = "Adopted"
+	// ReconcileActionNoOp means Pangolin already matched the spec
+	ReconcileActionNoOp ReconcileAction = "NoOp"
+)
+
 // PangolinResourceStatus defines the observed state of PangolinResource
 // PangolinResourceStatus defines the observed state of PangolinResource
 type PangolinResourceStatus struct {
@@ -139,17 +380,28 @@ type PangolinResourceStatus struct {
 	// All target IDs for this resource
 	TargetIDs []string `json:"targetIds,omitempty"`
 
+	// Methods detected for targets with method auto, by target address (ip:port)
+	ProbedTargetMethods map[string]string `json:"probedTargetMethods,omitempty"`
+
+	// Health reported by Pangolin for targets with a health check, by target
+	// address (ip:port): healthy, unhealthy or unknown
+	// +optional
+	TargetHealth map[string]string `json:"targetHealth,omitempty"`
+
 	// Resolved domain ID from domain name
 	ResolvedDomainID string `json:"resolvedDomainId,omitempty"`
 
 	// Full domain where resource is accessible
 	FullDomain string `json:"fullDomain,omitempty"`
 
-	// Binding mode: "Created" or "Bound"
+	// Binding mode: "Created", "Bound" (spec.resourceId) or "Adopted" (an
+	// existing resource with the same subdomain or name was taken over)
 	BindingMode string `json:"bindingMode,omitempty"`
 
-	// Current status: Creating, Ready, Error, Deleting, Waiting
-	// +kubebuilder:validation:Enum=Creating;Ready;Error;Deleting;Waiting
+	// Current status: Creating, Ready, Suspended, Error, Deleting, Waiting,
+	// DryRun while the pangolin.io/dry-run annotation is set, or Migrating
+	// while the resource is recreated on another subdomain or domain
+	// +kubebuilder:validation:Enum=Creating;Ready;Suspended;Error;Deleting;Waiting;DryRun;Migrating
 	Status string `json:"status,omitempty"`
 
 	// Public URL for HTTP resources
@@ -164,14 +416,179 @@ type PangolinResourceStatus struct {
 	// ObservedGeneration reflects the generation most recently observed
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// LastSyncTime is when the resource and its targets were last synced with
+	// Pangolin, refreshed at most every few minutes while nothing else changes
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
 	// SSOEnabled indicates if SSO authentication is enabled for this resource
 	SSOEnabled bool `json:"ssoEnabled,omitempty"`
 
 	// BlockAccessEnabled indicates if access is blocked until authenticated
 	BlockAccessEnabled bool `json:"blockAccessEnabled,omitempty"`
 
+	// PasswordEnabled indicates if password authentication is enabled for this resource
+	PasswordEnabled bool `json:"passwordEnabled,omitempty"`
+
+	// PincodeEnabled indicates if PIN code authentication is enabled for this resource
+	PincodeEnabled bool `json:"pincodeEnabled,omitempty"`
+
+	// AuthSecretVersions names the Secrets and resource versions of the
+	// password and PIN code last applied to Pangolin, used to detect changed
+	// Secrets without keeping anything derived from the credentials
+	// +optional
+	AuthSecretVersions string `json:"authSecretVersions,omitempty"`
+
+	// ClientCertVersions maps the client certificate Secrets of the targets to
+	// the resource versions last uploaded to Pangolin, so a rotated
+	// certificate is uploaded again
+	// +optional
+	ClientCertVersions map[string]string `json:"clientCertVersions,omitempty"`
+
+	// StickySession indicates if sticky sessions were last applied to the resource
+	StickySession bool `json:"stickySession,omitempty"`
+
+	// ScheduleActive is whether spec.schedule enabled the resource when it was
+	// last applied to Pangolin
+	// +optional
+	ScheduleActive *bool `json:"scheduleActive,omitempty"`
+
+	// NextScheduleBoundary is when a window of spec.schedule next opens or closes
+	// +optional
+	NextScheduleBoundary *metav1.Time `json:"nextScheduleBoundary,omitempty"`
+
+	// AccessRuleIDs are the Pangolin IDs of the rules managed for
+	// spec.httpConfig.accessRules
+	// +optional
+	AccessRuleIDs []int `json:"accessRuleIds,omitempty"`
+
 	// TargetCount is the number of targets configured for this resource
 	TargetCount int `json:"targetCount,omitempty"`
+
+	// RecentEvents are the latest events reported by Pangolin for this resource
+	// +optional
+	RecentEvents []ResourceEvent `json:"recentEvents,omitempty"`
+
+	// AppliedSpec is what the operator last sent to Pangolin when creating the
+	// resource and its targets, after translating the spec to the API's model
+	// +optional
+	AppliedSpec *AppliedResourceSpec `json:"appliedSpec,omitempty"`
+
+	// LastAction is what the last reconcile did to the Pangolin resource and its targets
+	// +optional
+	LastAction ReconcileAction `json:"lastAction,omitempty"`
+
+	// Plan is what the operator would apply to Pangolin, reported instead of
+	// applying it while the pangolin.io/dry-run annotation is set
+	// +optional
+	Plan *ResourcePlan `json:"plan,omitempty"`
+
+	// MigratingFrom is the Pangolin resource being replaced while the resource
+	// is recreated because its subdomain or domain cannot be changed in place.
+	// It is deleted once the new resource and its targets are configured.
+	// +optional
+	MigratingFrom *ResourceMigration `json:"migratingFrom,omitempty"`
+}
+
+// ResourceMigration identifies the Pangolin resource and targets replaced by a
+// recreate
+type ResourceMigration struct {
+	// ResourceID of the Pangolin resource being replaced
+	ResourceID string `json:"resourceId"`
+
+	// TargetIDs of the targets of the replaced resource
+	// +optional
+	TargetIDs []string `json:"targetIds,omitempty"`
+}
+
+// AppliedResourceSpec records the effective settings sent to the Pangolin API.
+// HTTP resources are created with protocol "tcp" and http=true.
+type AppliedResourceSpec struct {
+	// Protocol sent to the API
+	Protocol string `json:"protocol,omitempty"`
+
+	// HTTP is whether the resource was created as an HTTP resource
+	HTTP bool `json:"http"`
+
+	// TargetMethod of the last target created
+	// +optional
+	TargetMethod string `json:"targetMethod,omitempty"`
+
+	// Subdomain sent for HTTP resources
+	// +optional
+	Subdomain string `json:"subdomain,omitempty"`
+
+	// DomainID sent for HTTP resources
+	// +optional
+	DomainID string `json:"domainId,omitempty"`
+
+	// ProxyPort sent to the API, 0 if none
+	// +optional
+	ProxyPort int32 `json:"proxyPort,omitempty"`
+}
+
+// ResourcePlan describes the Pangolin resource and targets a dry run would apply.
+type ResourcePlan struct {
+	// Action is what would be done to the Pangolin resource: Create a new one,
+	// Update the one in status.resourceId to the spec, or Bind to the resource
+	// named by spec.resourceId, spec.bindByName or spec.bindBySubdomain
+	// +kubebuilder:validation:Enum=Create;Update;Bind
+	Action string `json:"action"`
+
+	// ResourceID of the Pangolin resource that would be updated or bound
+	// +optional
+	ResourceID string `json:"resourceId,omitempty"`
+
+	// Name of the resource, or of the resource bound by name
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Protocol of the resource
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// Subdomain of HTTP resources, or of the resource bound by subdomain
+	// +optional
+	Subdomain string `json:"subdomain,omitempty"`
+
+	// DomainID the subdomain of HTTP resources would be created on
+	// +optional
+	DomainID string `json:"domainId,omitempty"`
+
+	// FullDomain HTTP resources would be served on
+	// +optional
+	FullDomain string `json:"fullDomain,omitempty"`
+
+	// ProxyPort of TCP/UDP resources
+	// +optional
+	ProxyPort int32 `json:"proxyPort,omitempty"`
+
+	// SiteID the targets would be created on
+	// +optional
+	SiteID string `json:"siteId,omitempty"`
+
+	// Tags that would be set on the resource
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Targets that would be configured, as method://ip:port
+	// +optional
+	Targets []string `json:"targets,omitempty"`
+}
+
+// ResourceEvent is an event reported by the Pangolin API for a resource
+type ResourceEvent struct {
+	// Event type (e.g., "info", "warning", "error")
+	Type string `json:"type,omitempty"`
+
+	// Short machine-readable reason
+	Reason string `json:"reason,omitempty"`
+
+	// Human-readable message
+	Message string `json:"message,omitempty"`
+
+	// Time the event was recorded by Pangolin
+	Timestamp string `json:"timestamp,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -184,6 +601,7 @@ type PangolinResourceStatus struct {
 //+kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.url`
 //+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
 //+kubebuilder:printcolumn:name="Binding Mode",type=string,JSONPath=`.status.bindingMode`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // PangolinResource is the Schema for the pangolinresources API
@@ -1,12 +1,15 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // PangolinTunnelSpec defines the desired state of PangolinTunnel
 type PangolinTunnelSpec struct {
 	// Reference to the organization
+	// An organization in another namespace can only be referenced when the
+	// operator runs with --allow-cross-namespace-org
 	// +kubebuilder:validation:Required
 	OrganizationRef LocalObjectReference `json:"organizationRef"`
 
@@ -26,6 +29,11 @@ type PangolinTunnelSpec struct {
 
 	// Custom configuration
 	Config map[string]string `json:"config,omitempty"`
+
+	// What happens to the Pangolin site when this tunnel is deleted.
+	// Defaults to Delete for created sites and Retain for bound ones.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // PangolinTunnelStatus defines the observed state of PangolinTunnel
@@ -48,16 +56,24 @@ type PangolinTunnelStatus struct {
 	NewtID        string `json:"newtId,omitempty"`
 	NewtSecretRef string `json:"newtSecretRef,omitempty"`
 
-	// Binding mode: "Created" or "Bound"
+	// Binding mode: "Created", "Bound" (spec.siteId or spec.niceId) or "Adopted"
+	// (an existing site with the same name was taken over)
 	BindingMode string `json:"bindingMode,omitempty"`
 
-	// Deployment status
+	// What the last reconcile did to the Pangolin site
+	LastAction ReconcileAction `json:"lastAction,omitempty"`
+
+	// Ready replicas of the Newt client Deployment
 	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
 
 	// Current status
 	Status             string             `json:"status,omitempty"`
 	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is when the site was last synced with Pangolin
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -68,6 +84,7 @@ type PangolinTunnelStatus struct {
 //+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
 //+kubebuilder:printcolumn:name="Binding Mode",type=string,JSONPath=`.status.bindingMode`
 //+kubebuilder:printcolumn:name="Online",type=boolean,JSONPath=`.status.online`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // PangolinTunnel is the Schema for the pangolintunnel API
@@ -88,10 +105,54 @@ type PangolinTunnelList struct {
 	Items           []PangolinTunnel `json:"items"`
 }
 
+// NewtClientSpec configures the in-cluster Newt client Deployment of a tunnel
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || !has(self.replicas) || self.minReplicas <= self.replicas",message="minReplicas must not exceed replicas"
 type NewtClientSpec struct {
-	Enabled  bool   `json:"enabled,omitempty"`
+	// Enabled deploys the Newt client as <tunnel>-newt-client
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Replicas of the Newt client Deployment. When not set, the Deployment
+	// starts with minReplicas and its replica count is left to an autoscaler.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
-	Image    string `json:"image,omitempty"`
+
+	// MinReplicas is the number of ready Newt clients required for the tunnel
+	// to be Ready. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Image of the Newt client, defaults to fosrl/newt:latest
+	Image string `json:"image,omitempty"`
+
+	// ImagePullPolicy of the Newt client container. Defaults to Always for
+	// images without a tag or with the latest tag, IfNotPresent otherwise.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets used to pull the Newt client image, e.g. from a private
+	// mirror. The Secrets must exist in the tunnel's namespace.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Resources of the Newt client container. When not set, it requests 10m CPU
+	// and 64Mi memory without limits; set to {} to request nothing.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector constrains the Newt client pods to matching nodes
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the Newt client pods, e.g. to run on tainted nodes
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity of the Newt client pods, e.g. to spread replicas across nodes
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
 }
 
 func init() {
@@ -17,10 +17,58 @@ Third‑party code bundled in this repository may be licensed under different te
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessRule) DeepCopyInto(out *AccessRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessRule.
+func (in *AccessRule) DeepCopy() *AccessRule {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedResourceSpec) DeepCopyInto(out *AppliedResourceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedResourceSpec.
+func (in *AppliedResourceSpec) DeepCopy() *AppliedResourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedResourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryConfig) DeepCopyInto(out *CanaryConfig) {
+	*out = *in
+	in.Stable.DeepCopyInto(&out.Stable)
+	in.Canary.DeepCopyInto(&out.Canary)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryConfig.
+func (in *CanaryConfig) DeepCopy() *CanaryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Domain) DeepCopyInto(out *Domain) {
 	*out = *in
@@ -36,9 +84,51 @@ func (in *Domain) DeepCopy() *Domain {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointWeights) DeepCopyInto(out *EndpointWeights) {
+	*out = *in
+	if in.Weights != nil {
+		in, out := &in.Weights, &out.Weights
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DefaultWeight != nil {
+		in, out := &in.DefaultWeight, &out.DefaultWeight
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointWeights.
+func (in *EndpointWeights) DeepCopy() *EndpointWeights {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointWeights)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPConfig) DeepCopyInto(out *HTTPConfig) {
 	*out = *in
+	if in.NoDefaultDomain != nil {
+		in, out := &in.NoDefaultDomain, &out.NoDefaultDomain
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AccessRules != nil {
+		in, out := &in.AccessRules, &out.AccessRules
+		*out = make([]AccessRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ResourceAuth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPConfig.
@@ -74,6 +164,40 @@ func (in *NewtClientSpec) DeepCopyInto(out *NewtClientSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewtClientSpec.
@@ -94,6 +218,13 @@ func (in *OrganizationDefaults) DeepCopyInto(out *OrganizationDefaults) {
 		*out = new(NewtClientSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TargetMethods != nil {
+		in, out := &in.TargetMethods, &out.TargetMethods
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationDefaults.
@@ -178,7 +309,7 @@ func (in *PangolinBindingSpec) DeepCopyInto(out *PangolinBindingSpec) {
 	if in.HTTPConfig != nil {
 		in, out := &in.HTTPConfig, &out.HTTPConfig
 		*out = new(HTTPConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ProxyConfig != nil {
 		in, out := &in.ProxyConfig, &out.ProxyConfig
@@ -190,6 +321,23 @@ func (in *PangolinBindingSpec) DeepCopyInto(out *PangolinBindingSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.StaticTargets != nil {
+		in, out := &in.StaticTargets, &out.StaticTargets
+		*out = make([]TargetConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StickySession != nil {
+		in, out := &in.StickySession, &out.StickySession
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EndpointWeights != nil {
+		in, out := &in.EndpointWeights, &out.EndpointWeights
+		*out = new(EndpointWeights)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PangolinBindingSpec.
@@ -210,6 +358,17 @@ func (in *PangolinBindingStatus) DeepCopyInto(out *PangolinBindingStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServiceEndpointWeights != nil {
+		in, out := &in.ServiceEndpointWeights, &out.ServiceEndpointWeights
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServiceMissingSince != nil {
+		in, out := &in.ServiceMissingSince, &out.ServiceMissingSince
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -217,6 +376,10 @@ func (in *PangolinBindingStatus) DeepCopyInto(out *PangolinBindingStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PangolinBindingStatus.
@@ -292,6 +455,51 @@ func (in *PangolinOrganizationList) DeepCopyObject() runtime.Object {
 func (in *PangolinOrganizationSpec) DeepCopyInto(out *PangolinOrganizationSpec) {
 	*out = *in
 	in.APIKeyRef.DeepCopyInto(&out.APIKeyRef)
+	if in.APIKeyFallbackRef != nil {
+		in, out := &in.APIKeyFallbackRef, &out.APIKeyFallbackRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SiteAPIKeyRef != nil {
+		in, out := &in.SiteAPIKeyRef, &out.SiteAPIKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceAPIKeyRef != nil {
+		in, out := &in.ResourceAPIKeyRef, &out.ResourceAPIKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APITimeoutSeconds != nil {
+		in, out := &in.APITimeoutSeconds, &out.APITimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CABundleRef != nil {
+		in, out := &in.CABundleRef, &out.CABundleRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIHeadersSecretRef != nil {
+		in, out := &in.APIHeadersSecretRef, &out.APIHeadersSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.RateLimitWarningThreshold != nil {
+		in, out := &in.RateLimitWarningThreshold, &out.RateLimitWarningThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DisableDiscovery != nil {
+		in, out := &in.DisableDiscovery, &out.DisableDiscovery
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PauseChildren != nil {
+		in, out := &in.PauseChildren, &out.PauseChildren
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Defaults != nil {
 		in, out := &in.Defaults, &out.Defaults
 		*out = new(OrganizationDefaults)
@@ -317,6 +525,11 @@ func (in *PangolinOrganizationStatus) DeepCopyInto(out *PangolinOrganizationStat
 		*out = make([]Domain, len(*in))
 		copy(*out, *in)
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -324,6 +537,10 @@ func (in *PangolinOrganizationStatus) DeepCopyInto(out *PangolinOrganizationStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PangolinOrganizationStatus.
@@ -407,7 +624,7 @@ func (in *PangolinResourceSpec) DeepCopyInto(out *PangolinResourceSpec) {
 	if in.HTTPConfig != nil {
 		in, out := &in.HTTPConfig, &out.HTTPConfig
 		*out = new(HTTPConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ProxyConfig != nil {
 		in, out := &in.ProxyConfig, &out.ProxyConfig
@@ -417,13 +634,47 @@ func (in *PangolinResourceSpec) DeepCopyInto(out *PangolinResourceSpec) {
 	if in.Targets != nil {
 		in, out := &in.Targets, &out.Targets
 		*out = make([]TargetConfig, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryConfig)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(ResourceSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StickySession != nil {
+		in, out := &in.StickySession, &out.StickySession
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.WaitForHealthy != nil {
+		in, out := &in.WaitForHealthy, &out.WaitForHealthy
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PangolinResourceSpec.
@@ -444,6 +695,20 @@ func (in *PangolinResourceStatus) DeepCopyInto(out *PangolinResourceStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ProbedTargetMethods != nil {
+		in, out := &in.ProbedTargetMethods, &out.ProbedTargetMethods
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetHealth != nil {
+		in, out := &in.TargetHealth, &out.TargetHealth
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -451,6 +716,51 @@ func (in *PangolinResourceStatus) DeepCopyInto(out *PangolinResourceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ClientCertVersions != nil {
+		in, out := &in.ClientCertVersions, &out.ClientCertVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ScheduleActive != nil {
+		in, out := &in.ScheduleActive, &out.ScheduleActive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NextScheduleBoundary != nil {
+		in, out := &in.NextScheduleBoundary, &out.NextScheduleBoundary
+		*out = (*in).DeepCopy()
+	}
+	if in.AccessRuleIDs != nil {
+		in, out := &in.AccessRuleIDs, &out.AccessRuleIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecentEvents != nil {
+		in, out := &in.RecentEvents, &out.RecentEvents
+		*out = make([]ResourceEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedSpec != nil {
+		in, out := &in.AppliedSpec, &out.AppliedSpec
+		*out = new(AppliedResourceSpec)
+		**out = **in
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(ResourcePlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MigratingFrom != nil {
+		in, out := &in.MigratingFrom, &out.MigratingFrom
+		*out = new(ResourceMigration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PangolinResourceStatus.
@@ -565,6 +875,10 @@ func (in *PangolinTunnelStatus) DeepCopyInto(out *PangolinTunnelStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PangolinTunnelStatus.
@@ -597,6 +911,128 @@ func (in *ProxyConfig) DeepCopy() *ProxyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceAuth) DeepCopyInto(out *ResourceAuth) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PincodeSecretRef != nil {
+		in, out := &in.PincodeSecretRef, &out.PincodeSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceAuth.
+func (in *ResourceAuth) DeepCopy() *ResourceAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceEvent) DeepCopyInto(out *ResourceEvent) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceEvent.
+func (in *ResourceEvent) DeepCopy() *ResourceEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceMigration) DeepCopyInto(out *ResourceMigration) {
+	*out = *in
+	if in.TargetIDs != nil {
+		in, out := &in.TargetIDs, &out.TargetIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceMigration.
+func (in *ResourceMigration) DeepCopy() *ResourceMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePlan) DeepCopyInto(out *ResourcePlan) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePlan.
+func (in *ResourcePlan) DeepCopy() *ResourcePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSchedule) DeepCopyInto(out *ResourceSchedule) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]ScheduleWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSchedule.
+func (in *ResourceSchedule) DeepCopy() *ResourceSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleWindow) DeepCopyInto(out *ScheduleWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleWindow.
+func (in *ScheduleWindow) DeepCopy() *ScheduleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceReference) DeepCopyInto(out *ServiceReference) {
 	*out = *in
@@ -635,6 +1071,26 @@ func (in *SiteReference) DeepCopy() *SiteReference {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetConfig) DeepCopyInto(out *TargetConfig) {
 	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(TargetHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetConfig.
@@ -646,3 +1102,28 @@ func (in *TargetConfig) DeepCopy() *TargetConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetHealthCheck) DeepCopyInto(out *TargetHealthCheck) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExpectedStatus != nil {
+		in, out := &in.ExpectedStatus, &out.ExpectedStatus
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetHealthCheck.
+func (in *TargetHealthCheck) DeepCopy() *TargetHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
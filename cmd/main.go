@@ -16,6 +16,11 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
+
+	// Embed the time zone database for resource schedules, the distroless base
+	// image has none
+	_ "time/tzdata"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -33,6 +38,7 @@ import (
 
 	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
 	"github.com/bovf/pangolin-operator/internal/controller"
+	webhooktunnelv1alpha1 "github.com/bovf/pangolin-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -54,6 +60,16 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var endpointDebounceWindow time.Duration
+	var pangolinClass string
+	var allowCrossNamespaceOrg bool
+	var allowCrossNamespaceTunnel bool
+	var fullSyncInterval time.Duration
+	var errorBackoffCap time.Duration
+	var errorRequeueInterval time.Duration
+	var resyncInterval time.Duration
+	var reconcileTimeout time.Duration
+	var missingServiceGracePeriod time.Duration
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -65,6 +81,31 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&endpointDebounceWindow, "endpoint-debounce-window", controller.DefaultEndpointDebounceWindow,
+		"How long Service endpoints must be stable before bindings apply endpoint changes. Set to 0 to apply immediately.")
+	flag.StringVar(&pangolinClass, "pangolin-class", "",
+		"Only reconcile objects whose "+controller.ClassLabel+" label or annotation matches this value. "+
+			"Empty manages only objects without a class.")
+	flag.BoolVar(&allowCrossNamespaceOrg, "allow-cross-namespace-org", false,
+		"Allow tunnels and bindings to reference a PangolinOrganization in another namespace.")
+	flag.BoolVar(&allowCrossNamespaceTunnel, "allow-cross-namespace-tunnel", false,
+		"Allow bindings to reference a PangolinTunnel in another namespace. Resources may always do so.")
+	flag.DurationVar(&fullSyncInterval, "full-sync-interval", 0,
+		"How often to enqueue every managed object for a full reconcile. Set to 0 to disable.")
+	flag.DurationVar(&errorBackoffCap, "error-backoff-cap", controller.DefaultErrorBackoffCap,
+		"Longest wait between retries of an object in Error. Retries back off exponentially up to this cap "+
+			"and start over when the object's spec changes.")
+	flag.DurationVar(&errorRequeueInterval, "error-requeue-interval", controller.DefaultErrorRequeueInterval,
+		"Wait before the first retry of an object in Error. Later retries double it up to --error-backoff-cap.")
+	flag.DurationVar(&reconcileTimeout, "reconcile-timeout", controller.DefaultReconcileTimeout,
+		"Longest time a single reconcile may take, including all its Pangolin API requests and their retries. "+
+			"Set to 0 to disable.")
+	flag.DurationVar(&missingServiceGracePeriod, "missing-service-grace-period", 0,
+		"How long the Service of a binding may be missing before the binding's generated resource is deleted "+
+			"from Pangolin. Set to 0 to keep the generated resource until the Service is back.")
+	flag.DurationVar(&resyncInterval, "resync-interval", 0,
+		"How often to re-reconcile each Ready object to correct drift made outside the operator. "+
+			"Set to 0 to disable.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -140,34 +181,98 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Full syncs are opt-in, reconcilers skip the extra watch when fullSync is nil
+	var fullSync *controller.FullSync
+	if fullSyncInterval > 0 {
+		fullSync = &controller.FullSync{Client: mgr.GetClient(), Interval: fullSyncInterval}
+	}
+
 	if err = (&controller.PangolinTunnelReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                 mgr.GetClient(),
+		Scheme:                 mgr.GetScheme(),
+		Recorder:               mgr.GetEventRecorderFor("pangolintunnel-controller"),
+		APIReader:              mgr.GetAPIReader(),
+		Class:                  pangolinClass,
+		AllowCrossNamespaceOrg: allowCrossNamespaceOrg,
+		ErrorBackoffCap:        errorBackoffCap,
+		ErrorRequeueInterval:   errorRequeueInterval,
+		ResyncInterval:         resyncInterval,
+		ReconcileTimeout:       reconcileTimeout,
+		FullSync:               fullSync,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PangolinTunnel")
 		os.Exit(1)
 	}
 	if err = (&controller.PangolinResourceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                 mgr.GetClient(),
+		Scheme:                 mgr.GetScheme(),
+		Recorder:               mgr.GetEventRecorderFor("pangolinresource-controller"),
+		Class:                  pangolinClass,
+		AllowCrossNamespaceOrg: allowCrossNamespaceOrg,
+		ErrorBackoffCap:        errorBackoffCap,
+		ErrorRequeueInterval:   errorRequeueInterval,
+		ResyncInterval:         resyncInterval,
+		ReconcileTimeout:       reconcileTimeout,
+		FullSync:               fullSync,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PangolinResource")
 		os.Exit(1)
 	}
 	if err = (&controller.PangolinBindingReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                    mgr.GetClient(),
+		Scheme:                    mgr.GetScheme(),
+		APIReader:                 mgr.GetAPIReader(),
+		EndpointDebounceWindow:    endpointDebounceWindow,
+		Class:                     pangolinClass,
+		AllowCrossNamespaceOrg:    allowCrossNamespaceOrg,
+		AllowCrossNamespaceTunnel: allowCrossNamespaceTunnel,
+		ErrorBackoffCap:           errorBackoffCap,
+		ErrorRequeueInterval:      errorRequeueInterval,
+		ResyncInterval:            resyncInterval,
+		ReconcileTimeout:          reconcileTimeout,
+		MissingServiceGracePeriod: missingServiceGracePeriod,
+		FullSync:                  fullSync,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PangolinBinding")
 		os.Exit(1)
 	}
 	if err = (&controller.PangolinOrganizationReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                 mgr.GetClient(),
+		Scheme:                 mgr.GetScheme(),
+		Recorder:               mgr.GetEventRecorderFor("pangolinorganization-controller"),
+		Class:                  pangolinClass,
+		AllowCrossNamespaceOrg: allowCrossNamespaceOrg,
+		ErrorBackoffCap:        errorBackoffCap,
+		ErrorRequeueInterval:   errorRequeueInterval,
+		ResyncInterval:         resyncInterval,
+		ReconcileTimeout:       reconcileTimeout,
+		FullSync:               fullSync,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PangolinOrganization")
 		os.Exit(1)
 	}
+	if fullSync != nil {
+		if err = mgr.Add(fullSync); err != nil {
+			setupLog.Error(err, "unable to add full sync")
+			os.Exit(1)
+		}
+	}
+	// Webhooks need serving certificates (see config/webhook and config/certmanager),
+	// so they are opt-in to keep deployments without cert-manager working.
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err = webhooktunnelv1alpha1.SetupPangolinTunnelWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PangolinTunnel")
+			os.Exit(1)
+		}
+		if err = webhooktunnelv1alpha1.SetupPangolinResourceWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PangolinResource")
+			os.Exit(1)
+		}
+		if err = webhooktunnelv1alpha1.SetupPangolinBindingWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PangolinBinding")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
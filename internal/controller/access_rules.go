@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+// accessRuleActions maps spec access rule actions to Pangolin rule actions
+var accessRuleActions = map[string]string{
+	"allow": "ACCEPT",
+	"deny":  "DROP",
+}
+
+// desiredAccessRules translates spec.httpConfig.accessRules to Pangolin rules,
+// prioritized in spec order. Bound resources keep their own rules.
+func desiredAccessRules(resource *tunnelv1alpha1.PangolinResource) []pangolin.ResourceRule {
+	if resource.Spec.BindField() != "" || resource.Spec.HTTPConfig == nil {
+		return nil
+	}
+	var rules []pangolin.ResourceRule
+	for i, rule := range resource.Spec.HTTPConfig.AccessRules {
+		rules = append(rules, pangolin.ResourceRule{
+			Action:   accessRuleActions[rule.Action],
+			Match:    strings.ToUpper(rule.Match),
+			Value:    rule.Value,
+			Priority: i + 1,
+			Enabled:  true,
+		})
+	}
+	return rules
+}
+
+// reconcileAccessRules makes the resource's rules in Pangolin match
+// spec.httpConfig.accessRules.
+//
+// Missing rules are created and rules the operator created earlier (see
+// status.accessRuleIds) that are no longer in the spec are deleted. Rules added
+// in Pangolin by other means are left alone.
+func (r *PangolinResourceReconciler) reconcileAccessRules(
+	ctx context.Context,
+	api *pangolin.Client,
+	resourceID string,
+	resource *tunnelv1alpha1.PangolinResource,
+) error {
+	desired := desiredAccessRules(resource)
+	if len(desired) == 0 && len(resource.Status.AccessRuleIDs) == 0 {
+		return nil
+	}
+
+	existing, err := api.ListResourceRules(ctx, resourceID)
+	if err != nil {
+		return fmt.Errorf("failed to list access rules: %w", err)
+	}
+
+	managed := make(map[int]bool, len(resource.Status.AccessRuleIDs))
+	for _, id := range resource.Status.AccessRuleIDs {
+		managed[id] = true
+	}
+
+	changed := false
+	unmanaged := 0
+	matched := make([]bool, len(desired))
+	var ruleIDs []int
+	for _, rule := range existing {
+		if i := matchAccessRule(desired, matched, rule); i >= 0 {
+			matched[i] = true
+			ruleIDs = append(ruleIDs, rule.RuleID)
+			continue
+		}
+		if !managed[rule.RuleID] {
+			unmanaged++
+			continue
+		}
+		if err := api.DeleteResourceRule(ctx, resourceID, rule.RuleID); err != nil {
+			return fmt.Errorf("failed to delete access rule %d: %w", rule.RuleID, err)
+		}
+		changed = true
+	}
+
+	for i, rule := range desired {
+		if matched[i] {
+			continue
+		}
+		created, err := api.CreateResourceRule(ctx, resourceID, rule)
+		if err != nil {
+			return fmt.Errorf("failed to create access rule %s %s %s: %w", rule.Action, rule.Match, rule.Value, err)
+		}
+		ruleIDs = append(ruleIDs, created.RuleID)
+		changed = true
+	}
+
+	// Rules are only evaluated while applyRules is set on the resource. It is
+	// turned on with the first managed rule and only turned off again when no
+	// rules at all are left.
+	var applyRules *bool
+	if len(desired) > 0 && len(resource.Status.AccessRuleIDs) == 0 {
+		applyRules = &[]bool{true}[0]
+	} else if len(desired) == 0 && unmanaged == 0 {
+		applyRules = &[]bool{false}[0]
+	}
+	if applyRules != nil {
+		if _, err := api.UpdateResource(ctx, resourceID, pangolin.ResourceUpdateSpec{ApplyRules: applyRules}); err != nil {
+			return fmt.Errorf("failed to set applyRules: %w", err)
+		}
+		changed = true
+	}
+
+	if changed {
+		escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+		log.FromContext(ctx).Info("Applied access rules", "resourceID", resourceID, "rules", len(desired))
+	}
+	sort.Ints(ruleIDs)
+	resource.Status.AccessRuleIDs = ruleIDs
+	return nil
+}
+
+// matchAccessRule returns the index of the first unmatched desired rule equal
+// to rule, or -1
+func matchAccessRule(desired []pangolin.ResourceRule, matched []bool, rule pangolin.ResourceRule) int {
+	for i, want := range desired {
+		if !matched[i] && want.Action == rule.Action && want.Match == rule.Match &&
+			want.Value == rule.Value && want.Priority == rule.Priority && rule.Enabled {
+			return i
+		}
+	}
+	return -1
+}
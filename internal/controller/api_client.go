@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// pangolinClientCache caches Pangolin API clients, so reconciles reuse the
+// client's HTTP transport and its connections instead of building a new client
+// every time.
+//
+// Clients are cached per organization and API key reference. A cached client is
+// replaced when the endpoint, the API keys, the timeout, the CA bundle or the
+// additional headers change, e.g. after the API key Secret was rotated. A nil
+// cache builds a new client on every call.
+type pangolinClientCache struct {
+	mu      sync.Mutex
+	clients map[string]cachedClient
+}
+
+// cachedClient is a client and the fingerprint of the settings it was built with
+type cachedClient struct {
+	fingerprint string
+	client      *pangolin.Client
+}
+
+// newPangolinClientCache creates an empty client cache.
+func newPangolinClientCache() *pangolinClientCache {
+	return &pangolinClientCache{clients: map[string]cachedClient{}}
+}
+
+// organizationClient returns a Pangolin API client for org authenticated with
+// the API key in keyRef, reusing the cached client while its settings are unchanged.
+// A client using spec.apiKeyRef falls back to spec.apiKeyFallbackRef, if set,
+// when the API rejects the key.
+func organizationClient(
+	ctx context.Context,
+	c client.Reader,
+	cache *pangolinClientCache,
+	org *tunnelv1alpha1.PangolinOrganization,
+	keyRef corev1.SecretKeySelector,
+) (*pangolin.Client, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: org.Namespace, Name: keyRef.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get API key secret: %w", err)
+	}
+	apiKey, ok := secret.Data[keyRef.Key]
+	if !ok {
+		return nil, fmt.Errorf("API key not found in secret")
+	}
+
+	config, err := loadClientConfig(ctx, c, org)
+	if err != nil {
+		return nil, err
+	}
+	if keyRef == org.Spec.APIKeyRef {
+		if config.fallbackKey, err = fallbackAPIKey(ctx, c, org); err != nil {
+			return nil, err
+		}
+	}
+	if cache == nil {
+		return newOrganizationClient(org, keyRef, apiKey, config)
+	}
+
+	fingerprint := clientFingerprint(org, apiKey, config)
+	owner := fmt.Sprintf("%s/%s/%s/%s", org.Namespace, org.Name, keyRef.Name, keyRef.Key)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cached, ok := cache.clients[owner]; ok && cached.fingerprint == fingerprint {
+		return cached.client, nil
+	}
+	apiClient, err := newOrganizationClient(org, keyRef, apiKey, config)
+	if err != nil {
+		return nil, err
+	}
+	cache.clients[owner] = cachedClient{fingerprint: fingerprint, client: apiClient}
+	return apiClient, nil
+}
+
+// clientFingerprint hashes the settings a client is built from, so the API key
+// and the header values themselves are not kept in the cache.
+func clientFingerprint(org *tunnelv1alpha1.PangolinOrganization, apiKey []byte, config clientConfig) string {
+	timeout := ""
+	if org.Spec.APITimeoutSeconds != nil {
+		timeout = fmt.Sprint(*org.Spec.APITimeoutSeconds)
+	}
+	parts := []string{org.Spec.APIEndpoint, org.Spec.APIBasePath, string(apiKey), string(config.fallbackKey),
+		timeout, config.caBundle}
+	names := make([]string, 0, len(config.headers))
+	for name := range config.headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, name, config.headers[name])
+	}
+
+	sum := sha256.New()
+	for _, part := range parts {
+		sum.Write([]byte(part))
+		sum.Write([]byte{0})
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// clientConfig holds the client settings of an organization that are loaded
+// from other objects
+type clientConfig struct {
+	// caBundle is the PEM encoded CA bundle, "" if none is referenced
+	caBundle string
+	// headers are the additional request headers, nil if none are referenced
+	headers map[string]string
+	// fallbackKey is the API key of spec.apiKeyFallbackRef, nil for clients
+	// not using spec.apiKeyRef or organizations without a fallback key
+	fallbackKey []byte
+}
+
+// loadClientConfig loads the CA bundle and the additional headers referenced by
+// the organization.
+func loadClientConfig(ctx context.Context, c client.Reader, org *tunnelv1alpha1.PangolinOrganization) (clientConfig, error) {
+	bundle, err := caBundle(ctx, c, org)
+	if err != nil {
+		return clientConfig{}, err
+	}
+	headers, err := apiHeaders(ctx, c, org)
+	if err != nil {
+		return clientConfig{}, err
+	}
+	return clientConfig{caBundle: bundle, headers: headers}, nil
+}
+
+// fallbackAPIKey returns the API key referenced by spec.apiKeyFallbackRef, or
+// nil if none.
+func fallbackAPIKey(ctx context.Context, c client.Reader, org *tunnelv1alpha1.PangolinOrganization) ([]byte, error) {
+	ref := org.Spec.APIKeyFallbackRef
+	if ref == nil {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: org.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get fallback API key secret: %w", err)
+	}
+	apiKey, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("fallback API key %q not found in secret %s", ref.Key, ref.Name)
+	}
+	return apiKey, nil
+}
+
+// caBundle returns the CA bundle referenced by the organization, or "" if none.
+func caBundle(ctx context.Context, c client.Reader, org *tunnelv1alpha1.PangolinOrganization) (string, error) {
+	ref := org.Spec.CABundleRef
+	if ref == nil {
+		return "", nil
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: org.Namespace, Name: ref.Name}, configMap); err != nil {
+		return "", fmt.Errorf("failed to get CA bundle ConfigMap: %w", err)
+	}
+	bundle, ok := configMap.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("CA bundle key %q not found in ConfigMap %s", ref.Key, ref.Name)
+	}
+	return bundle, nil
+}
+
+// apiHeaders returns the additional request headers referenced by the
+// organization, or nil if none.
+func apiHeaders(ctx context.Context, c client.Reader, org *tunnelv1alpha1.PangolinOrganization) (map[string]string, error) {
+	ref := org.Spec.APIHeadersSecretRef
+	if ref == nil {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: org.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get API headers secret: %w", err)
+	}
+	headers := make(map[string]string, len(secret.Data))
+	for name, value := range secret.Data {
+		headers[name] = string(value)
+	}
+	return headers, nil
+}
+
+// pangolinClientOptions returns the Pangolin client options configured on the
+// organization: the request timeout, the CA bundle trusted for the API and the
+// additional headers.
+func pangolinClientOptions(ctx context.Context, c client.Reader, org *tunnelv1alpha1.PangolinOrganization) ([]pangolin.Option, error) {
+	config, err := loadClientConfig(ctx, c, org)
+	if err != nil {
+		return nil, err
+	}
+	return clientOptions(org, config)
+}
+
+// newOrganizationClient builds a Pangolin API client from the organization spec,
+// the API key and the loaded client config. The rate limits reported to the
+// client are exported as metrics of the organization and API key secret.
+func newOrganizationClient(
+	org *tunnelv1alpha1.PangolinOrganization,
+	keyRef corev1.SecretKeySelector,
+	apiKey []byte,
+	config clientConfig,
+) (*pangolin.Client, error) {
+	opts, err := clientOptions(org, config)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, pangolin.WithRateLimitObserver(rateLimitObserver(org, keyRef.Name)))
+	if config.fallbackKey != nil {
+		opts = append(opts, pangolin.WithFallbackAPIKey(string(config.fallbackKey)))
+	}
+	endpoint, err := normalizeAPIEndpoint(org)
+	if err != nil {
+		return nil, err
+	}
+	return pangolin.NewClient(endpoint, string(apiKey), opts...), nil
+}
+
+// clientOptions returns the base path, timeout, header and TLS options for the
+// organization and its already loaded client config.
+func clientOptions(org *tunnelv1alpha1.PangolinOrganization, config clientConfig) ([]pangolin.Option, error) {
+	var opts []pangolin.Option
+	if org.Spec.APIBasePath != "" {
+		opts = append(opts, pangolin.WithBasePath(org.Spec.APIBasePath))
+	}
+	if org.Spec.APITimeoutSeconds != nil {
+		opts = append(opts, pangolin.WithTimeout(time.Duration(*org.Spec.APITimeoutSeconds)*time.Second))
+	}
+	if len(config.headers) > 0 {
+		opts = append(opts, pangolin.WithHeaders(config.headers))
+	}
+
+	if ref := org.Spec.CABundleRef; ref != nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(config.caBundle)) {
+			return nil, fmt.Errorf("no PEM certificates found in CA bundle %s/%s", ref.Name, ref.Key)
+		}
+		opts = append(opts, pangolin.WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}))
+	}
+	return opts, nil
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+// InvalidEndpointCondition is set on organizations whose spec.apiEndpoint
+// cannot be used to reach the Pangolin API, with the reason it is rejected
+const InvalidEndpointCondition = "InvalidEndpoint"
+
+// endpointError reports an unusable spec.apiEndpoint. Reason is the reason of
+// the InvalidEndpoint condition.
+type endpointError struct {
+	reason  string
+	message string
+}
+
+func (e *endpointError) Error() string {
+	return e.message
+}
+
+// normalizeAPIEndpoint validates spec.apiEndpoint of an organization and returns
+// it without trailing slashes, the form every Pangolin client is built from.
+//
+// The endpoint must be an http or https URL with a host and without query or
+// fragment. It must not end with the API base path (spec.apiBasePath, or
+// pangolin.DefaultBasePath), which the client appends to every request path.
+func normalizeAPIEndpoint(org *tunnelv1alpha1.PangolinOrganization) (string, error) {
+	endpoint := strings.TrimSpace(org.Spec.APIEndpoint)
+	u, err := url.Parse(endpoint)
+	if err != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", &endpointError{reason: "InvalidURL",
+			message: fmt.Sprintf("spec.apiEndpoint %q is not a valid URL of the Pangolin API", endpoint)}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", &endpointError{reason: "UnsupportedScheme",
+			message: fmt.Sprintf("spec.apiEndpoint %q must start with http:// or https://", endpoint)}
+	}
+	if u.Host == "" {
+		return "", &endpointError{reason: "MissingHost",
+			message: fmt.Sprintf("spec.apiEndpoint %q has no host", endpoint)}
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+	basePath := pangolin.DefaultBasePath
+	if org.Spec.APIBasePath != "" {
+		basePath = org.Spec.APIBasePath
+	}
+	if basePath = strings.Trim(basePath, "/"); basePath != "" && strings.HasSuffix(u.Path, "/"+basePath) {
+		return "", &endpointError{reason: "IncludesBasePath",
+			message: fmt.Sprintf("spec.apiEndpoint %q ends with the API base path /%s, which is added to every request; "+
+				"remove it from the endpoint", endpoint, basePath)}
+	}
+	return u.String(), nil
+}
+
+// reconcileAPIEndpoint validates spec.apiEndpoint, reporting the normalized
+// endpoint in status.apiEndpoint. An invalid endpoint sets the InvalidEndpoint
+// condition and is returned as an error.
+func reconcileAPIEndpoint(org *tunnelv1alpha1.PangolinOrganization) error {
+	endpoint, err := normalizeAPIEndpoint(org)
+	var invalid *endpointError
+	if errors.As(err, &invalid) {
+		setCondition(&org.Status.Conditions, InvalidEndpointCondition, metav1.ConditionTrue, invalid.reason,
+			invalid.message, org.Generation)
+		return err
+	}
+	meta.RemoveStatusCondition(&org.Status.Conditions, InvalidEndpointCondition)
+	org.Status.APIEndpoint = endpoint
+	return nil
+}
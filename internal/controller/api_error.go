@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+// errDomainRequired is returned for HTTP resources opting out of the
+// organization default domain without naming a domain
+var errDomainRequired = errors.New("httpConfig.noDefaultDomain is set but neither domainId nor domainName is specified")
+
+// errorReason returns the reason of the Ready condition for a failed reconcile:
+// DomainRequired for errDomainRequired, ReconcileError for anything else.
+func errorReason(err error) string {
+	if errors.Is(err, errDomainRequired) {
+		return "DomainRequired"
+	}
+	return "ReconcileError"
+}
+
+// errorMessage returns the status message for a failed reconcile.
+//
+// Pangolin API errors the user has to act on say what to check instead of just
+// relaying the response. Rate limited requests are retried like any other error,
+// waiting at least as long as the Retry-After the API asked for, see
+// errorBackoff.ObserveError. Reconciles cut short by --reconcile-timeout say so.
+func errorMessage(err error) string {
+	switch {
+	case pangolin.IsUnauthorized(err):
+		return fmt.Sprintf("Pangolin API rejected the API key, check the organization's API key secret and its permissions: %v", err)
+	case pangolin.IsRateLimited(err):
+		return fmt.Sprintf("Pangolin API rate limit exceeded, retrying with backoff: %v", err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Sprintf("Reconcile timed out waiting for the Pangolin API, retrying: %v", err)
+	}
+	return err.Error()
+}
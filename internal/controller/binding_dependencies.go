@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// Conditions reporting the dependencies of a binding, so kubectl describe shows
+// which of them keeps the binding from becoming ready
+const (
+	OrganizationReadyCondition = "OrganizationReady"
+	TunnelReadyCondition       = "TunnelReady"
+	ResourceReadyCondition     = "ResourceReady"
+)
+
+// bindingDependencies are the dependency conditions of a binding in the order
+// they are checked
+var bindingDependencies = []string{OrganizationReadyCondition, TunnelReadyCondition, ResourceReadyCondition}
+
+// setDependencyReady sets the condition of a dependency of the binding to true.
+// Suspended resources count as ready, as they are fully configured.
+func setDependencyReady(binding *tunnelv1alpha1.PangolinBinding, conditionType, kind, name, status string) {
+	setCondition(&binding.Status.Conditions, conditionType, metav1.ConditionTrue, status,
+		fmt.Sprintf("%s %s is %s", kind, name, status), binding.Generation)
+}
+
+// setDependencyNotReady sets the condition of a dependency that exists but is
+// not ready to false, with the dependency's status as reason and its own Ready
+// message. It returns the message of the binding's Ready condition, which names
+// the dependency and why it is not ready.
+func setDependencyNotReady(
+	binding *tunnelv1alpha1.PangolinBinding,
+	conditionType, kind, name, status string,
+	conditions []metav1.Condition,
+) string {
+	reason, message := status, fmt.Sprintf("%s %s is %s", kind, name, status)
+	if status == "" {
+		reason, message = "NotReconciled", fmt.Sprintf("%s %s has not been reconciled yet", kind, name)
+	}
+	if ready := meta.FindStatusCondition(conditions, "Ready"); ready != nil && ready.Message != "" {
+		message += ": " + ready.Message
+	}
+	setDependencyBlocking(binding, conditionType, reason, message)
+	return fmt.Sprintf("Waiting for %s to be ready: %s", strings.ToLower(kind), message)
+}
+
+// setDependencyFailed sets the condition of a dependency that could not be
+// resolved to false, with reason NotFound if it does not exist.
+func setDependencyFailed(binding *tunnelv1alpha1.PangolinBinding, conditionType string, err error) {
+	reason := "Error"
+	if errors.IsNotFound(err) {
+		reason = "NotFound"
+	}
+	setDependencyBlocking(binding, conditionType, reason, err.Error())
+}
+
+// setDependencyBlocking sets the condition of the dependency blocking the binding
+// to false, and the conditions of the dependencies checked after it to unknown.
+func setDependencyBlocking(binding *tunnelv1alpha1.PangolinBinding, conditionType, reason, message string) {
+	setCondition(&binding.Status.Conditions, conditionType, metav1.ConditionFalse, reason, message, binding.Generation)
+	blocked := false
+	for _, dependency := range bindingDependencies {
+		if blocked {
+			setCondition(&binding.Status.Conditions, dependency, metav1.ConditionUnknown, "Blocked",
+				fmt.Sprintf("Not checked while %s is false", conditionType), binding.Generation)
+		}
+		blocked = blocked || dependency == conditionType
+	}
+}
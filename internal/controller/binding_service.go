@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// handleMissingService reports a binding whose Service does not exist as
+// ServiceMissing, recording since when in status.serviceMissingSince.
+//
+// A Service can be missing for a moment, e.g. while it is recreated by a
+// redeploy, so the generated resource is kept at first. Only once the Service
+// has been missing for MissingServiceGracePeriod it counts as deleted on
+// purpose and the generated resource is deleted, removing it from Pangolin.
+// Without a grace period the generated resource is kept until the Service is
+// back. Either way the binding recovers on its own once the Service exists again.
+func (r *PangolinBindingReconciler) handleMissingService(
+	ctx context.Context,
+	binding *tunnelv1alpha1.PangolinBinding,
+) (ctrl.Result, error) {
+	now := time.Now()
+	if binding.Status.ServiceMissingSince == nil {
+		binding.Status.ServiceMissingSince = &metav1.Time{Time: now}
+	}
+	service := fmt.Sprintf("%s/%s", binding.Spec.ServiceRef.Namespace, binding.Spec.ServiceRef.Name)
+	log.FromContext(ctx).Info("Service of binding not found", "service", service,
+		"missingSince", binding.Status.ServiceMissingSince.Time)
+
+	if r.MissingServiceGracePeriod <= 0 {
+		return r.updateBindingStatus(ctx, binding, "ServiceMissing",
+			fmt.Sprintf("Service %s not found, the generated resource is kept until it is back", service))
+	}
+
+	remaining := binding.Status.ServiceMissingSince.Add(r.MissingServiceGracePeriod).Sub(now)
+	if remaining > 0 {
+		result, err := r.updateBindingStatus(ctx, binding, "ServiceMissing",
+			fmt.Sprintf("Service %s not found, the generated resource is deleted if it is still missing after %s",
+				service, r.MissingServiceGracePeriod))
+		if err == nil && remaining < result.RequeueAfter {
+			result.RequeueAfter = remaining
+		}
+		return result, err
+	}
+
+	gone, err := r.deleteGeneratedResource(ctx, binding)
+	if err != nil {
+		return r.updateBindingError(ctx, binding, err)
+	}
+	binding.Status.GeneratedResourceName = ""
+	binding.Status.URL = ""
+	binding.Status.ProxyEndpoint = ""
+	binding.Status.ServiceEndpoints = nil
+	binding.Status.ServiceEndpointWeights = nil
+	binding.Status.TargetAddress = ""
+	result, err := r.updateBindingStatus(ctx, binding, "ServiceMissing",
+		fmt.Sprintf("Service %s missing for longer than %s, the generated resource was deleted", service,
+			r.MissingServiceGracePeriod))
+	if err == nil && !gone {
+		result.RequeueAfter = generatedResourceDeletionPoll
+	}
+	return result, err
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// endpointWeights returns the weights of the addresses of endpoints from
+// spec.endpointWeights, or nil if the binding does not weight its endpoints.
+//
+// The pods behind the endpoints are only read for bindings with endpoint
+// weights. A relabelled pod is picked up by the next reconcile of the binding,
+// a rollout of new pods right away as it changes the endpoints.
+func (r *PangolinBindingReconciler) endpointWeights(
+	ctx context.Context,
+	binding *tunnelv1alpha1.PangolinBinding,
+	endpoints *corev1.Endpoints,
+) (map[string]int32, error) {
+	spec := binding.Spec.EndpointWeights
+	if spec == nil {
+		return nil, nil
+	}
+
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	pods := &corev1.PodList{}
+	if err := reader.List(ctx, pods, client.InNamespace(endpoints.Namespace),
+		client.HasLabels{spec.LabelKey}); err != nil {
+		return nil, fmt.Errorf("failed to list pods of endpoints: %w", err)
+	}
+	labelValues := make(map[string]string, len(pods.Items))
+	for _, pod := range pods.Items {
+		labelValues[pod.Name] = pod.Labels[spec.LabelKey]
+	}
+
+	weights := map[string]int32{}
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			if weight := endpointWeight(spec, address, labelValues); weight != nil {
+				weights[address.IP] = *weight
+			}
+		}
+	}
+	return weights, nil
+}
+
+// endpointWeight returns the weight of the pod behind address by the value of
+// its label, falling back to the default weight
+func endpointWeight(spec *tunnelv1alpha1.EndpointWeights, address corev1.EndpointAddress,
+	labelValues map[string]string) *int32 {
+	if ref := address.TargetRef; ref != nil && ref.Kind == "Pod" {
+		if value, ok := labelValues[ref.Name]; ok {
+			if weight, ok := spec.Weights[value]; ok {
+				return &weight
+			}
+		}
+	}
+	return spec.DefaultWeight
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ClassLabel partitions Pangolin objects between operator instances, like IngressClass.
+// It can be set as a label or an annotation; the label takes precedence.
+const ClassLabel = "pangolin.io/class"
+
+// objectClass returns the class an object is assigned to, or "" if unclassed.
+func objectClass(obj client.Object) string {
+	if class, ok := obj.GetLabels()[ClassLabel]; ok {
+		return class
+	}
+	return obj.GetAnnotations()[ClassLabel]
+}
+
+// matchesClass reports whether an operator instance running with class manages obj.
+// An empty class only matches unclassed objects.
+func matchesClass(obj client.Object, class string) bool {
+	return objectClass(obj) == class
+}
+
+// classLabels returns labels assigning a generated object to the same class as its owner.
+func classLabels(owner client.Object) map[string]string {
+	class := objectClass(owner)
+	if class == "" {
+		return nil
+	}
+	return map[string]string{ClassLabel: class}
+}
+
+// classPredicate filters watch events down to objects managed by this operator instance.
+func classPredicate(class string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return matchesClass(obj, class)
+	})
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// DegradedCondition is set on tunnels and resources whose Pangolin object
+// exists but reports unhealthy. Unlike Ready, which tells whether the spec was
+// applied, it reflects runtime health, so alerts can tell the two apart.
+const DegradedCondition = "Degraded"
+
+// setDegradedCondition sets the Degraded condition to reason and message, or
+// removes it if reason is empty
+func setDegradedCondition(conditions *[]metav1.Condition, generation int64, reason, message string) {
+	if reason == "" {
+		meta.RemoveStatusCondition(conditions, DegradedCondition)
+		return
+	}
+	setCondition(conditions, DegradedCondition, metav1.ConditionTrue, reason, message, generation)
+}
+
+// siteOffline reports whether the site of tunnel is offline. Local sites have
+// no tunnel to be online and are never offline.
+func siteOffline(tunnel *tunnelv1alpha1.PangolinTunnel) bool {
+	return !tunnel.Status.Online && tunnel.Status.SiteType != "local"
+}
+
+// setSiteDegradedCondition sets the Degraded condition of a tunnel while its
+// site reports offline
+func setSiteDegradedCondition(tunnel *tunnelv1alpha1.PangolinTunnel) {
+	reason, message := "", ""
+	if siteOffline(tunnel) {
+		reason, message = "SiteOffline", fmt.Sprintf("Site %s reports offline", tunnel.Status.NiceID)
+	}
+	setDegradedCondition(&tunnel.Status.Conditions, tunnel.Generation, reason, message)
+}
+
+// setResourceDegradedCondition sets the Degraded condition of a resource while
+// the site of its tunnel reports offline, or while it is enabled and not
+// suspended but all of its targets are disabled, so it cannot serve requests.
+func setResourceDegradedCondition(resource *tunnelv1alpha1.PangolinResource, tunnel *tunnelv1alpha1.PangolinTunnel,
+	targets []tunnelv1alpha1.TargetConfig) {
+	reason, message := "", ""
+	switch {
+	case tunnel != nil && siteOffline(tunnel):
+		reason = "SiteOffline"
+		message = fmt.Sprintf("Site %s of tunnel %s reports offline", tunnel.Status.NiceID, tunnel.Name)
+	case resourceEnabled(resource) && !resourceSuspended(resource) && len(targets) > 0 && !anyTargetEnabled(resource, targets):
+		reason, message = "TargetsDisabled", "All targets of the resource are disabled"
+	}
+	setDegradedCondition(&resource.Status.Conditions, resource.Generation, reason, message)
+}
+
+// anyTargetEnabled reports whether any of targets is enabled in Pangolin
+func anyTargetEnabled(resource *tunnelv1alpha1.PangolinResource, targets []tunnelv1alpha1.TargetConfig) bool {
+	for _, target := range targets {
+		if targetEnabled(resource, target) {
+			return true
+		}
+	}
+	return false
+}
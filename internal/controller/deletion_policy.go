@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// effectiveDeletionPolicy returns the deletion policy to apply to an object.
+// Without an explicit policy, objects the operator created are deleted and
+// objects it bound to are retained.
+func effectiveDeletionPolicy(policy tunnelv1alpha1.DeletionPolicy, bindingMode string) tunnelv1alpha1.DeletionPolicy {
+	if policy != "" {
+		return policy
+	}
+	if bindingMode == "Created" {
+		return tunnelv1alpha1.DeletionPolicyDelete
+	}
+	return tunnelv1alpha1.DeletionPolicyRetain
+}
+
+// removeOwnerReference drops owner references to owner from obj, so obj is not
+// garbage collected with it. obj is only updated if it referenced owner.
+func removeOwnerReference(ctx context.Context, c client.Client, obj, owner client.Object) error {
+	refs := obj.GetOwnerReferences()
+	kept := refs[:0:0]
+	for _, ref := range refs {
+		if ref.UID != owner.GetUID() {
+			kept = append(kept, ref)
+		}
+	}
+	if len(kept) == len(refs) {
+		return nil
+	}
+	obj.SetOwnerReferences(kept)
+	return c.Update(ctx, obj)
+}
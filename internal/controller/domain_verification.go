@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// DomainPendingCondition is set on organizations while a domain they reference
+// awaits verification in Pangolin, and on resources waiting for such a domain
+const DomainPendingCondition = "DomainPending"
+
+const (
+	// domainVerificationRequeueBase is how soon an organization with a pending
+	// domain is reconciled again, doubled with each verification try of the domain
+	domainVerificationRequeueBase = 30 * time.Second
+	// domainVerificationRequeueMax caps the wait between those reconciles
+	domainVerificationRequeueMax = 10 * time.Minute
+)
+
+// domainPending reports whether a domain is still being verified by Pangolin.
+// Domains whose verification failed are not pending, they need fixing.
+func domainPending(domain tunnelv1alpha1.Domain) bool {
+	return !domain.Verified && !domain.Failed
+}
+
+// pendingDomain returns the domain with domainID if it awaits verification
+func pendingDomain(org *tunnelv1alpha1.PangolinOrganization, domainID string) (tunnelv1alpha1.Domain, bool) {
+	for _, domain := range org.Status.Domains {
+		if domain.DomainID == domainID {
+			return domain, domainPending(domain)
+		}
+	}
+	return tunnelv1alpha1.Domain{}, false
+}
+
+// reconcileDomainVerification sets the DomainPending condition of an
+// organization while a domain referenced by its default domain or by the HTTP
+// resources of its tunnels awaits verification.
+//
+// It returns how soon to reconcile again to pick up the verification, backed
+// off with the number of verification tries, or 0 if no referenced domain is
+// pending. The organization is Waiting meanwhile, see organizationUsable.
+func (r *PangolinOrganizationReconciler) reconcileDomainVerification(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization) time.Duration {
+	referenced, err := r.referencedDomainIDs(ctx, org)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list the domains referenced by resources")
+	}
+
+	var pending []string
+	var requeue time.Duration
+	for _, domain := range org.Status.Domains {
+		if !referenced[domain.DomainID] || !domainPending(domain) {
+			continue
+		}
+		pending = append(pending, fmt.Sprintf("%s (%d tries)", domain.BaseDomain, domain.Tries))
+		wait := domainVerificationRequeueBase
+		for i := 0; i < domain.Tries && wait < domainVerificationRequeueMax; i++ {
+			wait *= 2
+		}
+		wait = min(wait, domainVerificationRequeueMax)
+		if requeue == 0 || wait < requeue {
+			requeue = wait
+		}
+	}
+
+	if len(pending) == 0 {
+		meta.RemoveStatusCondition(&org.Status.Conditions, DomainPendingCondition)
+		return 0
+	}
+	sort.Strings(pending)
+	setCondition(&org.Status.Conditions, DomainPendingCondition, metav1.ConditionTrue, "AwaitingVerification",
+		fmt.Sprintf("Domains awaiting verification: %s", strings.Join(pending, ", ")), org.Generation)
+	return requeue
+}
+
+// organizationUsable reports whether tunnels, resources and bindings can use
+// org. An organization only Waiting for domains to be verified is usable, as
+// tunnels and resources on verified domains are unaffected; resources on a
+// pending domain wait for it themselves, see domainPendingMessage.
+func organizationUsable(org *tunnelv1alpha1.PangolinOrganization) bool {
+	if org.Status.Status == "Ready" {
+		return true
+	}
+	return org.Status.Status == "Waiting" && meta.IsStatusConditionTrue(org.Status.Conditions, DomainPendingCondition)
+}
+
+// referencedDomainIDs returns the IDs of the organization's default domain and
+// of the domains named by spec.httpConfig of the resources of its tunnels.
+// Resources on the default domain are covered by the default.
+func (r *PangolinOrganizationReconciler) referencedDomainIDs(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization) (map[string]bool, error) {
+	referenced := map[string]bool{}
+	if org.Status.DefaultDomainID != "" {
+		referenced[org.Status.DefaultDomainID] = true
+	}
+
+	tunnels := &tunnelv1alpha1.PangolinTunnelList{}
+	if err := r.List(ctx, tunnels); err != nil {
+		return referenced, err
+	}
+	orgTunnels := map[types.NamespacedName]bool{}
+	for _, tunnel := range tunnels.Items {
+		if referencesOrganization(tunnel.Spec.OrganizationRef, tunnel.Namespace, r.AllowCrossNamespaceOrg, org) {
+			orgTunnels[types.NamespacedName{Namespace: tunnel.Namespace, Name: tunnel.Name}] = true
+		}
+	}
+	if len(orgTunnels) == 0 {
+		return referenced, nil
+	}
+
+	resources := &tunnelv1alpha1.PangolinResourceList{}
+	if err := r.List(ctx, resources); err != nil {
+		return referenced, err
+	}
+	for _, resource := range resources.Items {
+		config := resource.Spec.HTTPConfig
+		tunnelNamespace := resource.Spec.TunnelRef.Namespace
+		if tunnelNamespace == "" {
+			tunnelNamespace = resource.Namespace
+		}
+		if config == nil || !orgTunnels[types.NamespacedName{Namespace: tunnelNamespace, Name: resource.Spec.TunnelRef.Name}] {
+			continue
+		}
+		for _, domain := range org.Status.Domains {
+			if domain.DomainID == config.DomainID || (config.DomainID == "" && domain.BaseDomain == config.DomainName) {
+				referenced[domain.DomainID] = true
+			}
+		}
+	}
+	return referenced, nil
+}
+
+// domainPendingMessage returns why a resource that is not created yet waits for
+// the verification of its resolved domain, or "" if it does not. The
+// DomainPending condition of the resource is set accordingly.
+func domainPendingMessage(resource *tunnelv1alpha1.PangolinResource, org *tunnelv1alpha1.PangolinOrganization) string {
+	domain, pending := pendingDomain(org, resource.Status.ResolvedDomainID)
+	if !pending || resource.Status.ResourceID != "" || resource.Spec.BindField() != "" {
+		meta.RemoveStatusCondition(&resource.Status.Conditions, DomainPendingCondition)
+		return ""
+	}
+	message := fmt.Sprintf("Waiting for domain %s to be verified", domain.BaseDomain)
+	setCondition(&resource.Status.Conditions, DomainPendingCondition, metav1.ConditionTrue, "AwaitingVerification",
+		message, resource.Generation)
+	return message
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// DryRunAnnotation makes the operator report what it would apply to Pangolin for
+// a resource in status.plan, without calling the Pangolin API
+const DryRunAnnotation = "pangolin.io/dry-run"
+
+// isDryRun reports whether obj carries the dry-run annotation.
+func isDryRun(obj client.Object) bool {
+	dryRun, _ := strconv.ParseBool(obj.GetAnnotations()[DryRunAnnotation])
+	return dryRun
+}
+
+// reportResourcePlan computes the plan of a dry run of resource and reports it
+// in status with the DryRun status. The plan is built from the spec and the
+// organization status only, so no Pangolin API call is made.
+func (r *PangolinResourceReconciler) reportResourcePlan(
+	ctx context.Context,
+	resource *tunnelv1alpha1.PangolinResource,
+	tunnel *tunnelv1alpha1.PangolinTunnel,
+	org *tunnelv1alpha1.PangolinOrganization,
+) (ctrl.Result, error) {
+	siteID, err := r.resolveSiteForResource(ctx, resource, tunnel)
+	if err != nil {
+		return r.updateResourceError(ctx, resource, err)
+	}
+	plan, err := r.resourcePlan(ctx, resource, org, siteID)
+	if err != nil {
+		return r.updateResourceError(ctx, resource, err)
+	}
+	log.FromContext(ctx).Info("Dry run, not applying the plan", "plan", plan)
+
+	resource.Status.Plan = plan
+	return r.updateResourceStatus(ctx, resource, "DryRun", planMessage(plan))
+}
+
+// resourcePlan returns what reconciling resource would apply to Pangolin.
+//
+// Bound resources only report the resource they bind to, as their configuration
+// is not applied. Binds by name or subdomain are not resolved to an ID. Targets
+// are reported with their default method; the auto method is not resolved, as
+// that would probe the backends.
+func (r *PangolinResourceReconciler) resourcePlan(
+	ctx context.Context,
+	resource *tunnelv1alpha1.PangolinResource,
+	org *tunnelv1alpha1.PangolinOrganization,
+	siteID string,
+) (*tunnelv1alpha1.ResourcePlan, error) {
+	if resource.Spec.BindField() != "" {
+		return &tunnelv1alpha1.ResourcePlan{
+			Action:     "Bind",
+			ResourceID: resource.Spec.ResourceID,
+			Name:       resource.Spec.BindByName,
+			Subdomain:  resource.Spec.BindBySubdomain,
+		}, nil
+	}
+
+	plan := &tunnelv1alpha1.ResourcePlan{
+		Action:   "Create",
+		Name:     resource.Spec.Name,
+		Protocol: resource.Spec.Protocol,
+		SiteID:   siteID,
+		Tags:     resource.Spec.Tags,
+	}
+	if resource.Status.ResourceID != "" {
+		plan.Action = "Update"
+		plan.ResourceID = resource.Status.ResourceID
+	}
+
+	switch {
+	case resource.Spec.Protocol == "http" && resource.Spec.HTTPConfig != nil:
+		domainID, fullDomain, err := r.resolveDomainForResource(ctx, resource, org)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve domain: %w", err)
+		}
+		plan.Subdomain = resource.Spec.HTTPConfig.Subdomain
+		plan.DomainID = domainID
+		plan.FullDomain = fullDomain
+	case resource.Spec.Protocol != "http" && resource.Spec.ProxyConfig != nil:
+		plan.ProxyPort = resource.Spec.ProxyConfig.ProxyPort
+	default:
+		return nil, fmt.Errorf("invalid resource configuration")
+	}
+
+	targets, err := desiredTargetsForResource(resource)
+	if err != nil {
+		return nil, err
+	}
+	applyDefaultTargetMethods(targets, resource.Spec.Protocol, org)
+	for _, target := range targets {
+		plan.Targets = append(plan.Targets,
+			fmt.Sprintf("%s://%s", target.Method, net.JoinHostPort(target.IP, strconv.Itoa(int(target.Port)))))
+	}
+	return plan, nil
+}
+
+// planMessage summarizes a plan for the status message
+func planMessage(plan *tunnelv1alpha1.ResourcePlan) string {
+	if plan.Action == "Bind" {
+		switch {
+		case plan.Name != "":
+			return fmt.Sprintf("Dry run: would bind to the resource named %q", plan.Name)
+		case plan.Subdomain != "":
+			return fmt.Sprintf("Dry run: would bind to the resource on subdomain %q", plan.Subdomain)
+		}
+		return fmt.Sprintf("Dry run: would bind to resource %s", plan.ResourceID)
+	}
+
+	verb := "create"
+	if plan.Action == "Update" {
+		verb = "update resource " + plan.ResourceID + " to"
+	}
+	where := plan.FullDomain
+	if where == "" {
+		where = fmt.Sprintf("%s port %d", plan.Protocol, plan.ProxyPort)
+	}
+	msg := fmt.Sprintf("Dry run: would %s %q on %s", verb, plan.Name, where)
+	if len(plan.Targets) > 0 {
+		msg += " with targets " + strings.Join(plan.Targets, ", ")
+	}
+	return msg
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultEndpointDebounceWindow is the default time a binding's endpoints must be
+// stable before they are applied.
+const DefaultEndpointDebounceWindow = 5 * time.Second
+
+// endpointDebouncer coalesces Service endpoint changes per binding.
+//
+// During a rolling deploy endpoints flap rapidly. Instead of applying every
+// intermediate endpoint set, a change is only applied once the set has been
+// unchanged for the debounce window, so a deploy that churns many pods results
+// in a single update.
+type endpointDebouncer struct {
+	mu      sync.Mutex
+	window  time.Duration
+	now     func() time.Time
+	pending map[types.NamespacedName]pendingEndpoints
+}
+
+// pendingEndpoints is an endpoint set waiting for the debounce window to pass
+type pendingEndpoints struct {
+	endpoints []string
+	since     time.Time
+}
+
+// newEndpointDebouncer creates a debouncer with the given window.
+func newEndpointDebouncer(window time.Duration) *endpointDebouncer {
+	return &endpointDebouncer{
+		window:  window,
+		now:     time.Now,
+		pending: map[types.NamespacedName]pendingEndpoints{},
+	}
+}
+
+// Observe records the endpoints currently backing a binding's Service.
+//
+// Returns:
+//   - apply: true if the endpoints have been stable for the window and should be applied
+//   - wait: how long to wait before observing again when apply is false
+//
+// Endpoints equal to the applied set need no update and are applied immediately.
+func (d *endpointDebouncer) Observe(key types.NamespacedName, applied, endpoints []string) (bool, time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.window <= 0 || slices.Equal(applied, endpoints) {
+		delete(d.pending, key)
+		return true, 0
+	}
+
+	now := d.now()
+	p, ok := d.pending[key]
+	if !ok || !slices.Equal(p.endpoints, endpoints) {
+		// New change, (re)start the window
+		d.pending[key] = pendingEndpoints{endpoints: slices.Clone(endpoints), since: now}
+		return false, d.window
+	}
+
+	if elapsed := now.Sub(p.since); elapsed < d.window {
+		return false, d.window - elapsed
+	}
+
+	delete(d.pending, key)
+	return true, 0
+}
+
+// Forget drops any pending change for a binding, e.g. when it is deleted.
+func (d *endpointDebouncer) Forget(key types.NamespacedName) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.pending, key)
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+const (
+	// DefaultErrorBackoffCap is the default longest wait between retries of an object in Error.
+	DefaultErrorBackoffCap = 10 * time.Minute
+
+	// DefaultErrorRequeueInterval is the default wait before the first retry of an object in Error.
+	DefaultErrorRequeueInterval = 15 * time.Second
+
+	// waitingRequeueAfter is how often objects waiting for a dependency are retried
+	waitingRequeueAfter = time.Minute
+)
+
+// errorBackoff computes requeue intervals for objects whose reconcile failed.
+//
+// Each consecutive failure doubles the wait, up to the cap, so broken objects
+// don't keep hammering the Pangolin API. A new generation (the spec was edited)
+// starts over from the base interval, so a fix is retried promptly instead of
+// waiting out the backoff built up by the broken spec.
+//
+// When the Pangolin API rate limited the reconcile and asked for a longer wait
+// with Retry-After, that wait is used instead, still capped at the max.
+//
+// Objects that reconciled fine are requeued after the resync interval, if set,
+// so drift made outside the operator is corrected without waiting for an event.
+type errorBackoff struct {
+	mu         sync.Mutex
+	base       time.Duration
+	max        time.Duration
+	resync     time.Duration
+	failures   map[types.NamespacedName]backoffState
+	retryAfter map[types.NamespacedName]time.Duration
+}
+
+// backoffState is the failure streak of one object
+type backoffState struct {
+	generation int64
+	failures   int
+}
+
+// newErrorBackoff creates a backoff starting at base and capped at max, that
+// resyncs Ready objects every resync. A non-positive base or max uses
+// DefaultErrorRequeueInterval or DefaultErrorBackoffCap, a non-positive resync
+// disables resyncs.
+func newErrorBackoff(base, max, resync time.Duration) *errorBackoff {
+	if base <= 0 {
+		base = DefaultErrorRequeueInterval
+	}
+	if max <= 0 {
+		max = DefaultErrorBackoffCap
+	}
+	if base > max {
+		base = max
+	}
+	if resync < 0 {
+		resync = 0
+	}
+	return &errorBackoff{
+		base:       base,
+		max:        max,
+		resync:     resync,
+		failures:   map[types.NamespacedName]backoffState{},
+		retryAfter: map[types.NamespacedName]time.Duration{},
+	}
+}
+
+// RequeueAfter records the outcome of reconciling obj and returns when to reconcile it again.
+//
+//   - Ready or Suspended: the failure streak is reset, requeued after the
+//     resync interval (0, no requeue, without one)
+//   - Error: the failure is counted and the backed off interval is returned
+//   - anything else (e.g. Waiting for a dependency): a fixed interval
+//
+// A nil backoff retries errors at the fixed interval too.
+func (b *errorBackoff) RequeueAfter(obj client.Object, status string) time.Duration {
+	if status == "Ready" || status == "Suspended" {
+		b.Forget(client.ObjectKeyFromObject(obj))
+		if b == nil {
+			return 0
+		}
+		return b.resync
+	}
+	if status != "Error" || b == nil {
+		return waitingRequeueAfter
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := client.ObjectKeyFromObject(obj)
+	state := b.failures[key]
+	if state.generation != obj.GetGeneration() {
+		state = backoffState{generation: obj.GetGeneration()}
+	}
+
+	delay := b.base
+	for i := 0; i < state.failures && delay < b.max; i++ {
+		delay *= 2
+	}
+	if delay > b.max {
+		delay = b.max
+	}
+	if delay < b.max {
+		state.failures++
+	}
+	b.failures[key] = state
+
+	if retryAfter, ok := b.retryAfter[key]; ok {
+		delete(b.retryAfter, key)
+		delay = max(delay, min(retryAfter, b.max))
+	}
+	return delay
+}
+
+// ObserveError records the Retry-After of a rate limited Pangolin API error, so
+// the next RequeueAfter of obj waits at least that long. Other errors are ignored.
+func (b *errorBackoff) ObserveError(obj client.Object, err error) {
+	retryAfter := pangolin.RetryAfter(err)
+	if b == nil || retryAfter <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.retryAfter[client.ObjectKeyFromObject(obj)] = retryAfter
+}
+
+// Forget drops the failure streak of an object, e.g. when it is deleted.
+func (b *errorBackoff) Forget(key types.NamespacedName) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.failures, key)
+	delete(b.retryAfter, key)
+}
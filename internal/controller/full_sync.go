@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// FullSync is a manager Runnable that periodically enqueues every managed object
+// for a full reconcile, sweeping up drift that event-driven reconciles missed.
+//
+// Controllers opt in by watching the source returned by Source for their kind.
+type FullSync struct {
+	// Client lists the objects to enqueue
+	Client client.Reader
+
+	// Interval between full syncs
+	Interval time.Duration
+
+	kinds []fullSyncKind
+}
+
+// fullSyncKind is a kind registered for full syncs and the channel feeding its controller
+type fullSyncKind struct {
+	list   client.ObjectList
+	events chan event.GenericEvent
+}
+
+var _ manager.Runnable = &FullSync{}
+var _ manager.LeaderElectionRunnable = &FullSync{}
+
+// Source registers the kind of list for full syncs and returns the source its
+// controller must watch to receive the enqueued objects. Only objects passing
+// all predicates are enqueued.
+func (f *FullSync) Source(list client.ObjectList, predicates ...predicate.Predicate) source.Source {
+	events := make(chan event.GenericEvent)
+	f.kinds = append(f.kinds, fullSyncKind{list: list, events: events})
+	return source.Channel(events, &handler.EnqueueRequestForObject{},
+		source.WithPredicates[client.Object, reconcile.Request](predicates...))
+}
+
+// watch makes the controller built by b receive full syncs of the kind of list.
+// It is a no-op on a nil FullSync, so reconcilers can leave full syncs disabled.
+func (f *FullSync) watch(b *builder.Builder, list client.ObjectList, predicates ...predicate.Predicate) *builder.Builder {
+	if f == nil {
+		return b
+	}
+	return b.WatchesRawSource(f.Source(list, predicates...))
+}
+
+// Start runs a full sync every Interval until ctx is done.
+func (f *FullSync) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("full-sync")
+
+	ticker := time.NewTicker(f.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			logger.Info("Starting full sync")
+			if err := f.Trigger(ctx); err != nil {
+				logger.Error(err, "Full sync failed")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, only the leader
+// reconciles so only the leader needs to enqueue.
+func (f *FullSync) NeedLeaderElection() bool {
+	return true
+}
+
+// Trigger enqueues every object of the registered kinds once.
+func (f *FullSync) Trigger(ctx context.Context) error {
+	for _, kind := range f.kinds {
+		list := kind.list.DeepCopyObject().(client.ObjectList)
+		if err := f.Client.List(ctx, list); err != nil {
+			return fmt.Errorf("failed to list %T: %w", list, err)
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return fmt.Errorf("failed to extract %T: %w", list, err)
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			select {
+			case kind.events <- event.GenericEvent{Object: obj}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// NewtReadyCondition is set on tunnels with an operator managed Newt client. It
+// is false with a reason such as ImagePullBackOff or CrashLooping while too few
+// clients are ready, so a tunnel whose site stays offline can be debugged
+// without digging through its pods.
+const NewtReadyCondition = "NewtReady"
+
+// newtProgressPoll is how soon a tunnel is reconciled again while its Newt
+// client Deployment is rolling out
+const newtProgressPoll = 15 * time.Second
+
+// setNewtReadyCondition sets the NewtReady condition of a tunnel from its Newt
+// client Deployment, removing it for tunnels without a managed client.
+//
+// The pods of the Deployment are only read while too few clients are ready, to
+// report why: an image that cannot be pulled, a crash looping container or a
+// pod that cannot be scheduled. Otherwise the Deployment status tells whether
+// it is still progressing or has stalled.
+func (r *PangolinTunnelReconciler) setNewtReadyCondition(
+	ctx context.Context,
+	tunnel *tunnelv1alpha1.PangolinTunnel,
+	deployment *appsv1.Deployment,
+) error {
+	if !managesNewtClient(tunnel) || deployment == nil {
+		meta.RemoveStatusCondition(&tunnel.Status.Conditions, NewtReadyCondition)
+		return nil
+	}
+
+	ready, minReplicas := deployment.Status.ReadyReplicas, newtMinReplicas(tunnel.Spec.NewtClient)
+	if ready >= minReplicas {
+		setCondition(&tunnel.Status.Conditions, NewtReadyCondition, metav1.ConditionTrue, "Available",
+			fmt.Sprintf("%d of %d required Newt client replicas ready", ready, minReplicas), tunnel.Generation)
+		return nil
+	}
+
+	reason, message, err := r.newtPodProblem(ctx, deployment)
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		reason, message = newtDeploymentProblem(deployment, minReplicas)
+	}
+	setCondition(&tunnel.Status.Conditions, NewtReadyCondition, metav1.ConditionFalse, reason, message, tunnel.Generation)
+	return nil
+}
+
+// newtPodProblem returns the reason and message of the first problem found in
+// the pods of deployment, or "" if none of them reports one.
+func (r *PangolinTunnelReconciler) newtPodProblem(ctx context.Context, deployment *appsv1.Deployment) (string, string, error) {
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	pods := &corev1.PodList{}
+	if err := reader.List(ctx, pods, client.InNamespace(deployment.Namespace),
+		client.MatchingLabels(deployment.Spec.Selector.MatchLabels)); err != nil {
+		return "", "", fmt.Errorf("failed to list newt pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			waiting := status.State.Waiting
+			if status.Name != "newt" || waiting == nil {
+				continue
+			}
+			switch waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff":
+				return "ImagePullBackOff", fmt.Sprintf("Pod %s cannot pull image %s: %s",
+					pod.Name, status.Image, waiting.Message), nil
+			case "CrashLoopBackOff":
+				message := fmt.Sprintf("Pod %s is crash looping after %d restarts", pod.Name, status.RestartCount)
+				if terminated := status.LastTerminationState.Terminated; terminated != nil {
+					message += fmt.Sprintf(", last exit code %d (%s)", terminated.ExitCode, terminated.Reason)
+				}
+				return "CrashLooping", message, nil
+			}
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+				condition.Reason == corev1.PodReasonUnschedulable {
+				return "Unschedulable", fmt.Sprintf("Pod %s cannot be scheduled: %s", pod.Name, condition.Message), nil
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// newtDeploymentProblem returns why a Newt client Deployment whose pods report
+// no problem has too few ready replicas
+func newtDeploymentProblem(deployment *appsv1.Deployment, minReplicas int32) (string, string) {
+	for _, condition := range deployment.Status.Conditions {
+		switch {
+		case condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue:
+			// e.g. pods rejected by a resource quota
+			return "DeploymentNotAvailable", condition.Message
+		case condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded":
+			return "ProgressDeadlineExceeded", condition.Message
+		}
+	}
+	ready := deployment.Status.ReadyReplicas
+	if deployment.Status.ObservedGeneration < deployment.Generation ||
+		deployment.Status.UpdatedReplicas < deployment.Status.Replicas ||
+		deployment.Status.Replicas > ready {
+		return "Progressing", fmt.Sprintf("Newt client rollout in progress: %d of %d required replicas ready",
+			ready, minReplicas)
+	}
+	return "DeploymentNotAvailable", fmt.Sprintf("%d of %d required Newt client replicas ready", ready, minReplicas)
+}
+
+// newtProgressing reports whether the Newt client of tunnel is rolling out, so
+// the tunnel is polled more often than while waiting for a failure to be fixed
+func newtProgressing(tunnel *tunnelv1alpha1.PangolinTunnel) bool {
+	condition := meta.FindStatusCondition(tunnel.Status.Conditions, NewtReadyCondition)
+	return condition != nil && condition.Status == metav1.ConditionFalse && condition.Reason == "Progressing"
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// organizationKey resolves an organization reference made from an object in namespace.
+//
+// The organization defaults to the referencing object's namespace. Referencing an
+// organization in another namespace lets tenants use a shared organization and its
+// API key, so it is only allowed when allowCrossNamespace is set.
+func organizationKey(ref tunnelv1alpha1.LocalObjectReference, namespace string, allowCrossNamespace bool) (types.NamespacedName, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+	if ref.Namespace == "" || ref.Namespace == namespace {
+		return key, nil
+	}
+	if !allowCrossNamespace {
+		return key, fmt.Errorf("organization %s/%s is in another namespace; cross-namespace organization references are disabled (--allow-cross-namespace-org)",
+			ref.Namespace, ref.Name)
+	}
+	key.Namespace = ref.Namespace
+	return key, nil
+}
+
+// referencesOrganization reports whether ref made from namespace resolves to org.
+// References that are not allowed never match.
+func referencesOrganization(ref tunnelv1alpha1.LocalObjectReference, namespace string, allowCrossNamespace bool, org client.Object) bool {
+	key, err := organizationKey(ref, namespace, allowCrossNamespace)
+	return err == nil && key == client.ObjectKeyFromObject(org)
+}
+
+// organizationSubnetChanged passes organization updates that changed a known
+// status.subnet, so dependents can refresh routing derived from it.
+func organizationSubnetChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldOrg, ok := e.ObjectOld.(*tunnelv1alpha1.PangolinOrganization)
+			if !ok {
+				return false
+			}
+			newOrg, ok := e.ObjectNew.(*tunnelv1alpha1.PangolinOrganization)
+			if !ok {
+				return false
+			}
+			return oldOrg.Status.Subnet != "" && oldOrg.Status.Subnet != newOrg.Status.Subnet
+		},
+	}
+}
@@ -3,29 +3,88 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
 )
 
 const (
 	BindingFinalizerName = "binding.pangolin.io/finalizer"
+
+	// generatedResourceDeletionPoll is how often a deleted binding checks whether
+	// its generated resource is gone, in case the owner event is missed
+	generatedResourceDeletionPoll = 5 * time.Second
 )
 
 // PangolinBindingReconciler reconciles a PangolinBinding object
 type PangolinBindingReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// APIReader reads the pods behind weighted endpoints uncached, so pods are
+	// not cached cluster-wide for the few bindings that need them. Nil uses Client.
+	APIReader client.Reader
+
+	// EndpointDebounceWindow is how long Service endpoints must be stable before
+	// endpoint-driven updates are applied. Zero applies changes immediately.
+	EndpointDebounceWindow time.Duration
+
+	debouncer *endpointDebouncer
+
+	// Class restricts this instance to objects with a matching pangolin.io/class
+	// label or annotation. Empty manages only unclassed objects.
+	Class string
+
+	// AllowCrossNamespaceOrg allows organizationRef to point to an organization in another namespace.
+	AllowCrossNamespaceOrg bool
+
+	// AllowCrossNamespaceTunnel allows tunnelRef to point to a tunnel in another namespace.
+	AllowCrossNamespaceTunnel bool
+
+	// ErrorBackoffCap is the longest wait between retries of an object in Error.
+	// Zero uses DefaultErrorBackoffCap.
+	ErrorBackoffCap time.Duration
+
+	// ErrorRequeueInterval is the wait before the first retry of an object in
+	// Error. Zero uses DefaultErrorRequeueInterval.
+	ErrorRequeueInterval time.Duration
+
+	// ResyncInterval, when set, re-reconciles Ready objects that often to correct
+	// drift made outside the operator.
+	ResyncInterval time.Duration
+
+	// ReconcileTimeout bounds each reconcile, cancelling its Pangolin API
+	// requests when exceeded. Zero leaves reconciles unbounded.
+	ReconcileTimeout time.Duration
+
+	// MissingServiceGracePeriod is how long the Service of a binding may be
+	// missing before it counts as deleted on purpose and the generated resource
+	// is deleted. Zero keeps the generated resource until the Service is back.
+	MissingServiceGracePeriod time.Duration
+
+	backoff *errorBackoff
+
+	// FullSync, when set, periodically enqueues every object for a full reconcile.
+	FullSync *FullSync
 }
 
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolinbindings,verbs=get;list;watch;create;update;patch;delete
@@ -36,6 +95,7 @@ type PangolinBindingReconciler struct {
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolinorganizations,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=endpoints,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list
 
 // Reconcile implements the reconciliation logic for PangolinBinding.
 //
@@ -85,8 +145,19 @@ func (r *PangolinBindingReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	// Handle deletion if binding is being deleted
+	// Ignore objects managed by another operator instance
+	if !matchesClass(binding, r.Class) {
+		return ctrl.Result{}, nil
+	}
+
+	// Handle deletion if binding is being deleted. While paused the finalizer is
+	// kept, so the generated resource is only deleted once resumed. An
+	// organization that cannot be read does not pause the binding.
 	if binding.DeletionTimestamp != nil {
+		org, _ := r.getOrganizationForBinding(ctx, binding)
+		if msg := pausedMessage(binding, org); msg != "" {
+			return markPaused(ctx, r.Client, binding, &binding.Status.Conditions, msg)
+		}
 		return r.handleBindingDeletion(ctx, binding)
 	}
 
@@ -98,70 +169,112 @@ func (r *PangolinBindingReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	// Get the referenced service to extract ClusterIP and validate it exists
 	service, err := r.getServiceForBinding(ctx, binding)
+	if errors.IsNotFound(err) {
+		return r.handleMissingService(ctx, binding)
+	}
+	binding.Status.ServiceMissingSince = nil
 	if err != nil {
 		logger.Error(err, "Failed to get referenced service")
-		return r.updateBindingStatus(ctx, binding, "Error", err.Error())
+		return r.updateBindingError(ctx, binding, err)
+	}
+
+	// Resolve the exposed port early, so a wrong port name is reported right away
+	if _, err := bindingServicePort(binding, service); err != nil {
+		logger.Error(err, "Failed to resolve service port")
+		return r.updateBindingError(ctx, binding, err)
 	}
 
 	// Get the referenced organization for API credentials
 	org, err := r.getOrganizationForBinding(ctx, binding)
 	if err != nil {
 		logger.Error(err, "Failed to get referenced organization")
-		return r.updateBindingStatus(ctx, binding, "Error", err.Error())
+		setDependencyFailed(binding, OrganizationReadyCondition, err)
+		return r.updateBindingError(ctx, binding, err)
+	}
+
+	// Skip API writes while paused directly or through the organization
+	if msg := pausedMessage(binding, org); msg != "" {
+		return markPaused(ctx, r.Client, binding, &binding.Status.Conditions, msg)
 	}
 
 	// Wait for organization to be ready
-	if org.Status.Status != "Ready" {
+	if !organizationUsable(org) {
 		logger.Info("Organization not ready yet, waiting", "organization", org.Name)
-		return r.updateBindingStatus(ctx, binding, "Waiting", "Waiting for organization to be ready")
+		return r.updateBindingStatus(ctx, binding, "Waiting", setDependencyNotReady(binding,
+			OrganizationReadyCondition, "Organization", org.Name, org.Status.Status, org.Status.Conditions))
 	}
+	setDependencyReady(binding, OrganizationReadyCondition, "Organization", org.Name, org.Status.Status)
 
 	// Get or create tunnel for the binding
 	tunnel, err := r.ensureTunnelForBinding(ctx, binding, org)
 	if err != nil {
 		logger.Error(err, "Failed to ensure tunnel for binding")
-		return r.updateBindingStatus(ctx, binding, "Error", err.Error())
+		setDependencyFailed(binding, TunnelReadyCondition, err)
+		return r.updateBindingError(ctx, binding, err)
 	}
 
 	// Wait for tunnel to be ready
 	if tunnel.Status.Status != "Ready" {
 		logger.Info("Tunnel not ready yet, waiting", "tunnel", tunnel.Name)
-		return r.updateBindingStatus(ctx, binding, "Waiting", "Waiting for tunnel to be ready")
+		return r.updateBindingStatus(ctx, binding, "Waiting", setDependencyNotReady(binding,
+			TunnelReadyCondition, "Tunnel", tunnel.Name, tunnel.Status.Status, tunnel.Status.Conditions))
 	}
+	setDependencyReady(binding, TunnelReadyCondition, "Tunnel", tunnel.Name, tunnel.Status.Status)
 
 	// Create or update PangolinResource based on binding spec and service
-	resource, err := r.reconcileResourceForBinding(ctx, binding, tunnel, service)
+	resource, err := r.reconcileResourceForBinding(ctx, binding, org, tunnel, service)
 	if err != nil {
 		logger.Error(err, "Failed to reconcile resource for binding")
-		return r.updateBindingStatus(ctx, binding, "Error", err.Error())
+		setDependencyFailed(binding, ResourceReadyCondition, err)
+		return r.updateBindingError(ctx, binding, err)
 	}
 
-	// Wait for resource to be ready
-	if resource.Status.Status != "Ready" {
+	// Wait for resource to be ready. A suspended resource is configured and
+	// only has its targets disabled.
+	if resource.Status.Status != "Ready" && resource.Status.Status != "Suspended" {
 		logger.Info("Resource not ready yet, waiting", "resource", resource.Name)
-		return r.updateBindingStatus(ctx, binding, "Waiting", "Waiting for resource to be ready")
+		return r.updateBindingStatus(ctx, binding, "Waiting", setDependencyNotReady(binding,
+			ResourceReadyCondition, "Resource", resource.Name, resource.Status.Status, resource.Status.Conditions))
 	}
+	setDependencyReady(binding, ResourceReadyCondition, "Resource", resource.Name, resource.Status.Status)
 
 	// Update service endpoints if auto-update is enabled
 	// This is useful for multi-pod services where endpoints change dynamically
-	if binding.Spec.AutoUpdateTargets == nil || *binding.Spec.AutoUpdateTargets {
-		err = r.updateServiceEndpoints(ctx, binding, service)
+	var endpointWait time.Duration
+	var endpointPort int32
+	if tracksEndpoints(binding, service) {
+		endpointPort, endpointWait, err = r.updateServiceEndpoints(ctx, binding, service)
 		if err != nil {
 			logger.Error(err, "Failed to update service endpoints")
 			// Don't fail the reconciliation for endpoint update errors
 		}
 	}
 
+	// Keep the resource's targets in sync with the endpoints and static targets,
+	// unless the endpoints are unknown and the targets would fall back to the ClusterIP
+	if err == nil {
+		if err := r.reconcileBindingResourceSpec(ctx, binding, org, service, resource, endpointPort); err != nil {
+			logger.Error(err, "Failed to update generated resource")
+			return r.updateBindingError(ctx, binding, err)
+		}
+	}
+
 	// Update binding status with generated resource information
 	binding.Status.GeneratedResourceName = resource.Name
+	binding.Status.TargetAddress, _ = serviceTargetAddress(binding, service, endpointPort)
 	binding.Status.URL = resource.Status.URL
 	binding.Status.ProxyEndpoint = resource.Status.ProxyEndpoint
 
-	return r.updateBindingStatus(ctx, binding, "Ready", "Binding is ready")
+	result, err := r.updateBindingStatus(ctx, binding, "Ready", "Binding is ready")
+	if endpointWait > 0 && (result.RequeueAfter == 0 || endpointWait < result.RequeueAfter) {
+		// Come back once the pending endpoint change has settled
+		result.RequeueAfter = endpointWait
+	}
+	return result, err
 }
 
 // getServiceForBinding retrieves the Kubernetes Service referenced by the binding.
-// The Service's ClusterIP will be used as the target for the PangolinResource.
+// The Service's address will be used as the target for the PangolinResource.
 //
 // Returns an error if:
 //   - Service does not exist
@@ -181,13 +294,14 @@ func (r *PangolinBindingReconciler) getServiceForBinding(ctx context.Context, bi
 // getOrganizationForBinding retrieves the PangolinOrganization referenced by the binding.
 // The organization provides API credentials and domain configuration.
 func (r *PangolinBindingReconciler) getOrganizationForBinding(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding) (*tunnelv1alpha1.PangolinOrganization, error) {
-	org := &tunnelv1alpha1.PangolinOrganization{}
-	err := r.Get(ctx, types.NamespacedName{
-		Namespace: binding.Namespace,
-		Name:      binding.Spec.OrganizationRef.Name,
-	}, org)
+	key, err := organizationKey(binding.Spec.OrganizationRef, binding.Namespace, r.AllowCrossNamespaceOrg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get organization %s: %w", binding.Spec.OrganizationRef.Name, err)
+		return nil, err
+	}
+
+	org := &tunnelv1alpha1.PangolinOrganization{}
+	if err := r.Get(ctx, key, org); err != nil {
+		return nil, fmt.Errorf("failed to get organization %s: %w", key, err)
 	}
 	return org, nil
 }
@@ -199,16 +313,23 @@ func (r *PangolinBindingReconciler) getOrganizationForBinding(ctx context.Contex
 //   - If spec.tunnelRef is nil: Create or find default tunnel for organization (TODO)
 //
 // Currently, a tunnel reference is required. Automatic tunnel creation is a future enhancement.
+//
+// A tunnel in another namespace is only used with AllowCrossNamespaceTunnel, see
+// tunnelKey. The tunnel must use the binding's organization, otherwise the
+// generated resource would be created in another organization than the binding's.
 func (r *PangolinBindingReconciler) ensureTunnelForBinding(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding, org *tunnelv1alpha1.PangolinOrganization) (*tunnelv1alpha1.PangolinTunnel, error) {
 	if binding.Spec.TunnelRef != nil {
 		// Use specified tunnel
-		tunnel := &tunnelv1alpha1.PangolinTunnel{}
-		err := r.Get(ctx, types.NamespacedName{
-			Namespace: binding.Namespace,
-			Name:      binding.Spec.TunnelRef.Name,
-		}, tunnel)
+		key, err := tunnelKey(*binding.Spec.TunnelRef, binding.Namespace, r.AllowCrossNamespaceTunnel)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get tunnel %s: %w", binding.Spec.TunnelRef.Name, err)
+			return nil, err
+		}
+		tunnel := &tunnelv1alpha1.PangolinTunnel{}
+		if err := r.Get(ctx, key, tunnel); err != nil {
+			return nil, fmt.Errorf("failed to get tunnel %s: %w", key, err)
+		}
+		if !referencesOrganization(tunnel.Spec.OrganizationRef, tunnel.Namespace, r.AllowCrossNamespaceOrg, org) {
+			return nil, fmt.Errorf("tunnel %s does not use organization %s/%s of the binding", key, org.Namespace, org.Name)
 		}
 		return tunnel, nil
 	}
@@ -221,22 +342,28 @@ func (r *PangolinBindingReconciler) ensureTunnelForBinding(ctx context.Context,
 	return nil, fmt.Errorf("tunnelRef is required - automatic tunnel creation not yet implemented")
 }
 
+// generatedResourceName returns the name of the PangolinResource generated for a binding
+func generatedResourceName(binding *tunnelv1alpha1.PangolinBinding) string {
+	return fmt.Sprintf("%s-binding", binding.Name)
+}
+
 // reconcileResourceForBinding creates or updates the PangolinResource managed by this binding.
 //
 // Resource Creation:
 //   - Resource name: "<binding-name>-binding"
+//   - Pangolin name: "<service>-<protocol>", prefixed with the namespace on collision
 //   - Owner reference: Set to binding (ensures automatic deletion)
-//   - Target IP: Service ClusterIP
-//   - Target Port: From binding.spec.servicePort
+//   - Targets: The Service address for binding.spec.targetMode and the port from bindingServicePort,
+//     plus spec.staticTargets, see desiredBindingTargets
 //   - Protocol: From binding.spec.protocol
+//   - Target Method: From the org's defaults.targetMethods, else derived from the protocol
 //   - HTTP/Proxy config: Copied from binding spec
+//   - Sticky session: see stickySessionForBinding
 //
-// The resource is owned by the binding, so deleting the binding will automatically
-// delete the resource due to Kubernetes garbage collection.
-func (r *PangolinBindingReconciler) reconcileResourceForBinding(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding, tunnel *tunnelv1alpha1.PangolinTunnel, service *corev1.Service) (*tunnelv1alpha1.PangolinResource, error) {
+// The resource is owned by the binding and deleted with it, see handleBindingDeletion.
+func (r *PangolinBindingReconciler) reconcileResourceForBinding(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding, org *tunnelv1alpha1.PangolinOrganization, tunnel *tunnelv1alpha1.PangolinTunnel, service *corev1.Service) (*tunnelv1alpha1.PangolinResource, error) {
 
-	// Generate resource name from binding name
-	resourceName := fmt.Sprintf("%s-binding", binding.Name)
+	resourceName := generatedResourceName(binding)
 
 	// Check if resource already exists
 	resource := &tunnelv1alpha1.PangolinResource{}
@@ -250,11 +377,31 @@ func (r *PangolinBindingReconciler) reconcileResourceForBinding(ctx context.Cont
 	}
 
 	if errors.IsNotFound(err) {
+		targets, err := desiredBindingTargets(binding, org, service, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		// Pick a Pangolin name that no other binding's resource already uses
+		existing := &tunnelv1alpha1.PangolinResourceList{}
+		if err := r.List(ctx, existing); err != nil {
+			return nil, fmt.Errorf("failed to list resources: %w", err)
+		}
+		pangolinName, collidesWith := pangolinResourceNameForBinding(binding, resourceName, existing.Items)
+		if collidesWith != "" {
+			log.FromContext(ctx).Info("Pangolin resource name already in use, disambiguating",
+				"collidesWith", collidesWith, "name", pangolinName)
+			setCondition(&binding.Status.Conditions, "ResourceNameCollision", metav1.ConditionTrue, "Disambiguated",
+				fmt.Sprintf("Pangolin resource name is already used by %s, using %q", collidesWith, pangolinName), binding.Generation)
+		}
+
 		// Create new resource with owner reference to binding
 		resource = &tunnelv1alpha1.PangolinResource{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      resourceName,
 				Namespace: binding.Namespace,
+				// Keep the generated resource with the operator instance managing the binding
+				Labels: classLabels(binding),
 				OwnerReferences: []metav1.OwnerReference{
 					{
 						APIVersion: binding.APIVersion,
@@ -266,18 +413,11 @@ func (r *PangolinBindingReconciler) reconcileResourceForBinding(ctx context.Cont
 				},
 			},
 			Spec: tunnelv1alpha1.PangolinResourceSpec{
-				TunnelRef: tunnelv1alpha1.LocalObjectReference{
-					Name: tunnel.Name,
-				},
-				Name:     fmt.Sprintf("%s-%s", binding.Spec.ServiceRef.Name, binding.Spec.Protocol),
-				Protocol: binding.Spec.Protocol,
-				Targets: []tunnelv1alpha1.TargetConfig{
-					{
-						IP:     service.Spec.ClusterIP,
-						Port:   binding.Spec.ServicePort,
-						Method: "http", // TODO: Derive from protocol (http/https/tcp)
-					},
-				},
+				TunnelRef:     tunnelRefForBinding(binding, tunnel),
+				Name:          pangolinName,
+				Protocol:      binding.Spec.Protocol,
+				Targets:       targets,
+				StickySession: stickySessionForBinding(binding, service),
 			},
 		}
 
@@ -297,6 +437,37 @@ func (r *PangolinBindingReconciler) reconcileResourceForBinding(ctx context.Cont
 	return resource, nil
 }
 
+// tunnelRefForBinding returns the tunnelRef of the resource generated for a
+// binding, naming the namespace only for a tunnel in another namespace.
+func tunnelRefForBinding(binding *tunnelv1alpha1.PangolinBinding, tunnel *tunnelv1alpha1.PangolinTunnel) tunnelv1alpha1.LocalObjectReference {
+	ref := tunnelv1alpha1.LocalObjectReference{Name: tunnel.Name}
+	if tunnel.Namespace != binding.Namespace {
+		ref.Namespace = tunnel.Namespace
+	}
+	return ref
+}
+
+// pangolinResourceNameForBinding returns the Pangolin resource name for a binding.
+//
+// The name is "<service>-<protocol>". Bindings for services with the same name in
+// different namespaces would generate the same Pangolin name and fight over a single
+// resource, so if another PangolinResource already uses that name it is prefixed
+// with the binding's namespace.
+//
+// Returns the name and, on collision, the namespace/name of the conflicting resource.
+func pangolinResourceNameForBinding(binding *tunnelv1alpha1.PangolinBinding, resourceName string, existing []tunnelv1alpha1.PangolinResource) (string, string) {
+	name := fmt.Sprintf("%s-%s", binding.Spec.ServiceRef.Name, binding.Spec.Protocol)
+	for _, res := range existing {
+		if res.Namespace == binding.Namespace && res.Name == resourceName {
+			continue
+		}
+		if res.Spec.Name == name {
+			return fmt.Sprintf("%s-%s", binding.Namespace, name), fmt.Sprintf("%s/%s", res.Namespace, res.Name)
+		}
+	}
+	return name, ""
+}
+
 // updateServiceEndpoints updates the target endpoints based on service endpoints.
 //
 // For multi-pod services, this tracks all pod IPs backing the service and updates
@@ -307,10 +478,13 @@ func (r *PangolinBindingReconciler) reconcileResourceForBinding(ctx context.Cont
 //   - If spec.autoUpdateTargets is true (default): Continuously track endpoints
 //   - If spec.autoUpdateTargets is false: Skip endpoint updates
 //
-// Current Implementation:
-//   - Tracks endpoint IPs in binding status
-//   - TODO: Update PangolinResource with multiple targets for load balancing
-func (r *PangolinBindingReconciler) updateServiceEndpoints(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding, service *corev1.Service) error {
+// Debouncing:
+//   - Endpoint changes are coalesced until the set is stable for EndpointDebounceWindow
+//   - Returns how long to wait before the pending change can be applied (0 if applied)
+//
+// Returns the endpoint port backing binding.spec.servicePort (0 if unknown), used
+// as the port of the per-endpoint targets, see desiredBindingTargets.
+func (r *PangolinBindingReconciler) updateServiceEndpoints(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding, service *corev1.Service) (int32, time.Duration, error) {
 	// Get endpoints for the service
 	endpoints := &corev1.Endpoints{}
 	err := r.Get(ctx, types.NamespacedName{
@@ -318,8 +492,13 @@ func (r *PangolinBindingReconciler) updateServiceEndpoints(ctx context.Context,
 		Name:      service.Name,
 	}, endpoints)
 	if err != nil {
-		return fmt.Errorf("failed to get endpoints: %w", err)
+		return 0, 0, fmt.Errorf("failed to get endpoints: %w", err)
 	}
+	servicePort, err := bindingServicePort(binding, service)
+	if err != nil {
+		return 0, 0, err
+	}
+	port := endpointPortForService(service, servicePort, endpoints)
 
 	// Extract all endpoint addresses from all subsets
 	var endpointAddresses []string
@@ -328,14 +507,215 @@ func (r *PangolinBindingReconciler) updateServiceEndpoints(ctx context.Context,
 			endpointAddresses = append(endpointAddresses, address.IP)
 		}
 	}
+	sort.Strings(endpointAddresses)
+
+	// Hold back flapping endpoints until they settle
+	if r.debouncer != nil {
+		key := types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}
+		apply, wait := r.debouncer.Observe(key, binding.Status.ServiceEndpoints, endpointAddresses)
+		if !apply {
+			log.FromContext(ctx).Info("Endpoint change pending, waiting for endpoints to settle",
+				"endpoints", endpointAddresses, "wait", wait)
+			return port, wait, nil
+		}
+	}
+
+	weights, err := r.endpointWeights(ctx, binding, endpoints)
+	if err != nil {
+		return 0, 0, err
+	}
 
 	// Update binding status with current endpoints
 	binding.Status.ServiceEndpoints = endpointAddresses
+	binding.Status.ServiceEndpointWeights = weights
+
+	return port, 0, nil
+}
+
+// bindingServicePort returns the service port exposed by a binding: the port named
+// spec.servicePortName if set, else spec.servicePort.
+func bindingServicePort(binding *tunnelv1alpha1.PangolinBinding, service *corev1.Service) (int32, error) {
+	if binding.Spec.ServicePortName == "" {
+		return binding.Spec.ServicePort, nil
+	}
+	var available []string
+	for _, sp := range service.Spec.Ports {
+		if sp.Name == binding.Spec.ServicePortName {
+			return sp.Port, nil
+		}
+		available = append(available, fmt.Sprintf("%s (%d)", sp.Name, sp.Port))
+	}
+	return 0, fmt.Errorf("service %s/%s has no port named %q, available ports: %s",
+		service.Namespace, service.Name, binding.Spec.ServicePortName, strings.Join(available, ", "))
+}
 
-	// TODO: Update PangolinResource to create multiple targets (one per endpoint)
-	// This would enable true load balancing across all pod replicas
-	// For now, we only use the Service ClusterIP as a single target
+// endpointPortForService returns the endpoint port backing the given service port.
+// Endpoint ports carry the name of the service port they back. Returns 0 if the
+// service has no such port or no endpoint exposes it.
+func endpointPortForService(service *corev1.Service, servicePort int32, endpoints *corev1.Endpoints) int32 {
+	for _, sp := range service.Spec.Ports {
+		if sp.Port != servicePort {
+			continue
+		}
+		for _, subset := range endpoints.Subsets {
+			for _, ep := range subset.Ports {
+				if ep.Name == sp.Name {
+					return ep.Port
+				}
+			}
+		}
+	}
+	return 0
+}
 
+// bindingTargetMode returns spec.targetMode, defaulting to endpoints when
+// spec.autoUpdateTargets is set (the default) and to clusterIP otherwise
+func bindingTargetMode(binding *tunnelv1alpha1.PangolinBinding) string {
+	if binding.Spec.TargetMode != "" {
+		return binding.Spec.TargetMode
+	}
+	if binding.Spec.AutoUpdateTargets == nil || *binding.Spec.AutoUpdateTargets {
+		return tunnelv1alpha1.TargetModeEndpoints
+	}
+	return tunnelv1alpha1.TargetModeClusterIP
+}
+
+// tracksEndpoints reports whether the binding's targets follow the Service
+// endpoints. ExternalName Services have none.
+func tracksEndpoints(binding *tunnelv1alpha1.PangolinBinding, service *corev1.Service) bool {
+	return bindingTargetMode(binding) == tunnelv1alpha1.TargetModeEndpoints &&
+		service.Spec.Type != corev1.ServiceTypeExternalName
+}
+
+// serviceDNSName returns the cluster DNS name of a Service
+func serviceDNSName(service *corev1.Service) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", service.Name, service.Namespace)
+}
+
+// serviceTargetAddress returns the address of the single Service target of a
+// binding, or "" when it targets the Service endpoints on endpointPort.
+//
+// The address depends on the target mode and the Service type:
+//   - dns: the Service DNS name
+//   - loadBalancer: the first ingress IP or hostname of a LoadBalancer Service
+//   - ExternalName Services: spec.externalName, unless clusterIP is set explicitly
+//   - headless Services: the DNS name in endpoints mode while the endpoints are unknown
+//   - otherwise the ClusterIP, which NodePort and LoadBalancer Services have as well
+//
+// Combinations without an address, like clusterIP mode for a headless Service,
+// are an error.
+func serviceTargetAddress(binding *tunnelv1alpha1.PangolinBinding, service *corev1.Service, endpointPort int32) (string, error) {
+	mode := bindingTargetMode(binding)
+	name := fmt.Sprintf("%s/%s", service.Namespace, service.Name)
+	headless := service.Spec.ClusterIP == "" || service.Spec.ClusterIP == corev1.ClusterIPNone
+
+	switch {
+	case tracksEndpoints(binding, service) && endpointPort > 0 && len(binding.Status.ServiceEndpoints) > 0:
+		return "", nil
+	case mode == tunnelv1alpha1.TargetModeDNS:
+		return serviceDNSName(service), nil
+	case mode == tunnelv1alpha1.TargetModeLoadBalancer:
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			return "", fmt.Errorf("service %s is of type %s, targetMode loadBalancer requires a LoadBalancer service",
+				name, service.Spec.Type)
+		}
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				return ingress.IP, nil
+			}
+			if ingress.Hostname != "" {
+				return ingress.Hostname, nil
+			}
+		}
+		return "", fmt.Errorf("service %s has no load balancer ingress yet", name)
+	case service.Spec.Type == corev1.ServiceTypeExternalName:
+		if binding.Spec.TargetMode == tunnelv1alpha1.TargetModeClusterIP {
+			return "", fmt.Errorf("service %s is an ExternalName service and has no ClusterIP, use targetMode dns or leave it unset", name)
+		}
+		if service.Spec.ExternalName == "" {
+			return "", fmt.Errorf("ExternalName service %s has no externalName", name)
+		}
+		return service.Spec.ExternalName, nil
+	case headless && mode == tunnelv1alpha1.TargetModeEndpoints:
+		return serviceDNSName(service), nil
+	case headless:
+		return "", fmt.Errorf("service %s is headless and has no ClusterIP, use targetMode dns or endpoints", name)
+	default:
+		return service.Spec.ClusterIP, nil
+	}
+}
+
+// desiredBindingTargets returns the targets of the resource generated for a binding.
+//
+// When it tracks endpoints and they are known, there is one target per endpoint on
+// endpointPort, weighted by status.serviceEndpointWeights. Otherwise there is a
+// single target for serviceTargetAddress on the service port. spec.staticTargets
+// are always appended. Targets without a method use the organization default for
+// the binding protocol.
+func desiredBindingTargets(binding *tunnelv1alpha1.PangolinBinding, org *tunnelv1alpha1.PangolinOrganization,
+	service *corev1.Service, endpointPort int32) ([]tunnelv1alpha1.TargetConfig, error) {
+	address, err := serviceTargetAddress(binding, service, endpointPort)
+	if err != nil {
+		return nil, err
+	}
+	servicePort, err := bindingServicePort(binding, service)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []tunnelv1alpha1.TargetConfig
+	if address == "" {
+		for _, ip := range binding.Status.ServiceEndpoints {
+			target := tunnelv1alpha1.TargetConfig{IP: ip, Port: endpointPort}
+			if weight, ok := binding.Status.ServiceEndpointWeights[ip]; ok {
+				target.Weight = &weight
+			}
+			targets = append(targets, target)
+		}
+	} else {
+		targets = append(targets, tunnelv1alpha1.TargetConfig{IP: address, Port: servicePort})
+	}
+	targets = append(targets, binding.Spec.StaticTargets...)
+
+	applyDefaultTargetMethods(targets, binding.Spec.Protocol, org)
+	return targets, nil
+}
+
+// stickySessionForBinding returns the sticky session setting of the generated
+// resource: spec.stickySession if set, else whether the Service pins clients to
+// a pod with sessionAffinity: ClientIP.
+func stickySessionForBinding(binding *tunnelv1alpha1.PangolinBinding, service *corev1.Service) *bool {
+	if binding.Spec.StickySession != nil {
+		sticky := *binding.Spec.StickySession
+		return &sticky
+	}
+	sticky := service.Spec.SessionAffinity == corev1.ServiceAffinityClientIP
+	return &sticky
+}
+
+// reconcileBindingResourceSpec updates the generated resource when its targets or
+// sticky session differ from desiredBindingTargets and stickySessionForBinding.
+// The resource controller then creates missing targets and deletes the ones that
+// are no longer desired.
+func (r *PangolinBindingReconciler) reconcileBindingResourceSpec(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding,
+	org *tunnelv1alpha1.PangolinOrganization, service *corev1.Service, resource *tunnelv1alpha1.PangolinResource,
+	endpointPort int32) error {
+	targets, err := desiredBindingTargets(binding, org, service, endpointPort)
+	if err != nil {
+		return err
+	}
+	sticky := stickySessionForBinding(binding, service)
+	if equality.Semantic.DeepEqual(resource.Spec.Targets, targets) && equality.Semantic.DeepEqual(resource.Spec.StickySession, sticky) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Updating generated resource", "resource", resource.Name,
+		"targetCount", len(targets), "stickySession", *sticky)
+	resource.Spec.Targets = targets
+	resource.Spec.StickySession = sticky
+	if err := r.Update(ctx, resource); err != nil {
+		return fmt.Errorf("failed to update resource: %w", err)
+	}
 	return nil
 }
 
@@ -347,86 +727,170 @@ func (r *PangolinBindingReconciler) updateServiceEndpoints(ctx context.Context,
 //   - "Waiting": Waiting for dependencies (service, org, tunnel, resource)
 //
 // The function also updates the Ready condition with appropriate reason and message.
-// If status is not "Ready", the reconcile will be requeued after 1 minute.
+// If status is not "Ready", the reconcile is requeued: errors with backoff, see
+// errorBackoff, anything else after 1 minute.
 func (r *PangolinBindingReconciler) updateBindingStatus(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding, status, message string) (ctrl.Result, error) {
 	binding.Status.Status = status
 	binding.Status.ObservedGeneration = binding.Generation
+	binding.Status.LastSyncTime = syncTime(binding.Status.LastSyncTime, status, time.Now())
+	meta.RemoveStatusCondition(&binding.Status.Conditions, PausedCondition)
 
-	// Create Ready condition
-	conditionType := "Ready"
-	conditionStatus := metav1.ConditionTrue
-	reason := "ReconcileSuccess"
-
-	if status != "Ready" {
-		conditionStatus = metav1.ConditionFalse
-		reason = "ReconcileError"
-	}
-
-	now := metav1.NewTime(time.Now())
-	newCondition := metav1.Condition{
-		Type:               conditionType,
-		Status:             conditionStatus,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: now,
-		ObservedGeneration: binding.Generation,
-	}
-
-	// Update existing condition or append new one
-	conditionUpdated := false
-	for i, condition := range binding.Status.Conditions {
-		if condition.Type == conditionType {
-			// Preserve LastTransitionTime if status hasn't changed
-			if condition.Status != conditionStatus {
-				newCondition.LastTransitionTime = now
-			} else {
-				newCondition.LastTransitionTime = condition.LastTransitionTime
-			}
-			binding.Status.Conditions[i] = newCondition
-			conditionUpdated = true
-			break
-		}
-	}
+	setReadyCondition(&binding.Status.Conditions, binding.Generation, status, message)
 
-	if !conditionUpdated {
-		binding.Status.Conditions = append(binding.Status.Conditions, newCondition)
-	}
+	result := ctrl.Result{RequeueAfter: r.backoff.RequeueAfter(binding, status)}
+
+	ctx, cancel := statusContext(ctx)
+	defer cancel()
 
-	// Update status
-	err := r.Status().Update(ctx, binding)
-	if status != "Ready" {
-		return ctrl.Result{RequeueAfter: time.Minute}, err
+	// Skip the write when nothing changed
+	if statusUnchanged(ctx, r.Client, binding) {
+		return result, nil
 	}
 
-	return ctrl.Result{}, err
+	return result, r.Status().Update(ctx, binding)
+}
+
+// updateBindingError reports err as the Error status of binding. A rate limited
+// Pangolin API error makes the retry wait at least its Retry-After, see
+// errorBackoff.ObserveError.
+func (r *PangolinBindingReconciler) updateBindingError(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding, err error) (ctrl.Result, error) {
+	r.backoff.ObserveError(binding, err)
+	return r.updateBindingStatus(ctx, binding, "Error", errorMessage(err))
 }
 
 // handleBindingDeletion handles cleanup when a PangolinBinding is being deleted.
 //
 // Cleanup Process:
-//   - The owned PangolinResource is automatically deleted due to owner reference
-//   - No manual API cleanup is performed (resource remains in Pangolin)
+//   - The generated PangolinResource is deleted explicitly instead of being left
+//     to garbage collection, which can lose the race against the binding when
+//     the whole namespace is torn down
+//   - The binding keeps its finalizer until the resource is gone, so the
+//     resource's own finalizer has removed it from Pangolin
 //   - Finalizer is removed to allow binding deletion
-//
-// Future Enhancement:
-//   - Could optionally delete the Pangolin resource via API
-//   - Could provide a spec field to control cleanup behavior
 func (r *PangolinBindingReconciler) handleBindingDeletion(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding) (ctrl.Result, error) {
-	// The owned PangolinResource will be automatically deleted due to owner reference
-	// Kubernetes garbage collection handles this automatically
+	logger := log.FromContext(ctx)
+
+	gone, err := r.deleteGeneratedResource(ctx, binding)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !gone {
+		// The resource's deletion enqueues the binding again as its owner
+		logger.Info("Waiting for generated resource to be removed from Pangolin")
+		return ctrl.Result{RequeueAfter: generatedResourceDeletionPoll}, nil
+	}
+
+	if r.debouncer != nil {
+		r.debouncer.Forget(types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name})
+	}
+	r.backoff.Forget(client.ObjectKeyFromObject(binding))
 	controllerutil.RemoveFinalizer(binding, BindingFinalizerName)
 	return ctrl.Result{}, r.Update(ctx, binding)
 }
 
+// deleteGeneratedResource deletes the PangolinResource generated for the
+// binding, reporting whether it is gone. A resource the binding does not
+// control is left alone and counts as gone.
+func (r *PangolinBindingReconciler) deleteGeneratedResource(ctx context.Context, binding *tunnelv1alpha1.PangolinBinding) (bool, error) {
+	resource := &tunnelv1alpha1.PangolinResource{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: binding.Namespace, Name: generatedResourceName(binding)}, resource)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get generated resource: %w", err)
+	}
+	if !metav1.IsControlledBy(resource, binding) {
+		return true, nil
+	}
+	if resource.DeletionTimestamp == nil {
+		log.FromContext(ctx).Info("Deleting generated resource", "resource", resource.Name)
+		if err := r.Delete(ctx, resource); err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to delete generated resource: %w", err)
+		}
+	}
+	return false, nil
+}
+
+// bindingsForOrganization maps an organization to the bindings referencing it.
+func (r *PangolinBindingReconciler) bindingsForOrganization(ctx context.Context, org client.Object) []reconcile.Request {
+	bindings := &tunnelv1alpha1.PangolinBindingList{}
+	if err := r.List(ctx, bindings); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list bindings for organization", "organization", org.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, binding := range bindings.Items {
+		if matchesClass(&binding, r.Class) &&
+			referencesOrganization(binding.Spec.OrganizationRef, binding.Namespace, r.AllowCrossNamespaceOrg, org) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&binding)})
+		}
+	}
+	return requests
+}
+
+// bindingsForEndpoints maps the Endpoints of a Service to the bindings referencing
+// the Service, so pod changes update the targets without waiting for a resync.
+func (r *PangolinBindingReconciler) bindingsForEndpoints(ctx context.Context, endpoints client.Object) []reconcile.Request {
+	bindings := &tunnelv1alpha1.PangolinBindingList{}
+	if err := r.List(ctx, bindings); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list bindings for endpoints",
+			"endpoints", client.ObjectKeyFromObject(endpoints))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, binding := range bindings.Items {
+		if matchesClass(&binding, r.Class) &&
+			binding.Spec.ServiceRef.Namespace == endpoints.GetNamespace() &&
+			binding.Spec.ServiceRef.Name == endpoints.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&binding)})
+		}
+	}
+	return requests
+}
+
+// endpointSubsetsChanged filters out Endpoints updates that leave the addresses
+// and ports unchanged, e.g. annotation updates of leader election endpoints.
+func endpointSubsetsChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldEndpoints, ok := e.ObjectOld.(*corev1.Endpoints)
+			if !ok {
+				return false
+			}
+			newEndpoints, ok := e.ObjectNew.(*corev1.Endpoints)
+			if !ok {
+				return false
+			}
+			return !equality.Semantic.DeepEqual(oldEndpoints.Subsets, newEndpoints.Subsets)
+		},
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 //
 // Controller Configuration:
 //   - Watches PangolinBinding resources for changes
 //   - Owns PangolinResource (will reconcile when owned resource changes)
+//   - Watches Organizations for subnet changes and enqueues the bindings referencing them
+//   - Watches Endpoints and enqueues the bindings of their Service when the
+//     addresses or ports change, or when they are created or deleted along
+//     with the Service
 //   - Does not watch Services or Tunnels directly (manual triggers required)
 func (r *PangolinBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&tunnelv1alpha1.PangolinBinding{}).
+	r.backoff = newErrorBackoff(r.ErrorRequeueInterval, r.ErrorBackoffCap, r.ResyncInterval)
+	r.debouncer = newEndpointDebouncer(r.EndpointDebounceWindow)
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&tunnelv1alpha1.PangolinBinding{}, builder.WithPredicates(classPredicate(r.Class))).
 		Owns(&tunnelv1alpha1.PangolinResource{}).
-		Complete(r)
+		Watches(&tunnelv1alpha1.PangolinOrganization{},
+			handler.EnqueueRequestsFromMapFunc(r.bindingsForOrganization),
+			builder.WithPredicates(organizationSubnetChanged())).
+		Watches(&corev1.Endpoints{},
+			handler.EnqueueRequestsFromMapFunc(r.bindingsForEndpoints),
+			builder.WithPredicates(endpointSubsetsChanged()))
+	return r.FullSync.watch(b, &tunnelv1alpha1.PangolinBindingList{}, classPredicate(r.Class)).Complete(withReconcileTimeout(r, r.ReconcileTimeout))
 }
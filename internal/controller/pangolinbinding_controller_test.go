@@ -14,11 +14,18 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -77,4 +84,705 @@ var _ = Describe("PangolinBinding Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When service endpoints churn rapidly", func() {
+		It("should coalesce the changes into a single update", func() {
+			now := time.Now()
+			debouncer := newEndpointDebouncer(5 * time.Second)
+			debouncer.now = func() time.Time { return now }
+			key := types.NamespacedName{Name: "churn", Namespace: "default"}
+
+			applied := []string{"10.0.0.1"}
+			updates := 0
+			observe := func(endpoints []string) {
+				if ok, _ := debouncer.Observe(key, applied, endpoints); ok && !slices.Equal(applied, endpoints) {
+					applied = endpoints
+					updates++
+				}
+			}
+
+			By("rolling through 20 pods within the window")
+			for i := 0; i < 20; i++ {
+				observe([]string{fmt.Sprintf("10.0.1.%d", i), fmt.Sprintf("10.0.1.%d", i+1)})
+				now = now.Add(500 * time.Millisecond)
+			}
+			Expect(updates).To(Equal(0))
+
+			By("letting the final endpoint set settle")
+			final := []string{"10.0.1.19", "10.0.1.20"}
+			now = now.Add(5 * time.Second)
+			observe(final)
+			Expect(updates).To(Equal(1))
+			Expect(applied).To(Equal(final))
+
+			By("not updating again while endpoints are unchanged")
+			observe(final)
+			Expect(updates).To(Equal(1))
+		})
+	})
+
+	Context("When two bindings generate the same Pangolin resource name", func() {
+		newBinding := func(namespace string) *tunnelv1alpha1.PangolinBinding {
+			return &tunnelv1alpha1.PangolinBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+				Spec: tunnelv1alpha1.PangolinBindingSpec{
+					ServiceRef: tunnelv1alpha1.ServiceReference{Name: "web", Namespace: namespace},
+					Protocol:   "http",
+				},
+			}
+		}
+
+		It("should disambiguate the second binding by namespace", func() {
+			first := newBinding("team-a")
+			name, collidesWith := pangolinResourceNameForBinding(first, "web-binding", nil)
+			Expect(name).To(Equal("web-http"))
+			Expect(collidesWith).To(BeEmpty())
+
+			existing := []tunnelv1alpha1.PangolinResource{{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-binding", Namespace: "team-a"},
+				Spec:       tunnelv1alpha1.PangolinResourceSpec{Name: name},
+			}}
+
+			By("keeping the name for the binding that owns the resource")
+			name, collidesWith = pangolinResourceNameForBinding(first, "web-binding", existing)
+			Expect(name).To(Equal("web-http"))
+			Expect(collidesWith).To(BeEmpty())
+
+			By("prefixing the colliding binding's namespace")
+			name, collidesWith = pangolinResourceNameForBinding(newBinding("team-b"), "web-binding", existing)
+			Expect(name).To(Equal("team-b-web-http"))
+			Expect(collidesWith).To(Equal("team-a/web-binding"))
+		})
+	})
+
+	Context("When the organization sets default target methods", func() {
+		org := &tunnelv1alpha1.PangolinOrganization{
+			Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+				Defaults: &tunnelv1alpha1.OrganizationDefaults{
+					TargetMethods: map[string]string{"http": "https"},
+				},
+			},
+		}
+
+		It("should use the org default method for the generated resource", func() {
+			ctx := context.Background()
+			binding := &tunnelv1alpha1.PangolinBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "method-default", Namespace: "default", UID: "binding-uid"},
+				Spec: tunnelv1alpha1.PangolinBindingSpec{
+					ServiceRef:  tunnelv1alpha1.ServiceReference{Name: "web"},
+					ServicePort: 8443,
+					Protocol:    "http",
+				},
+			}
+			binding.APIVersion = tunnelv1alpha1.GroupVersion.String()
+			binding.Kind = "PangolinBinding"
+			tunnel := &tunnelv1alpha1.PangolinTunnel{ObjectMeta: metav1.ObjectMeta{Name: "tunnel"}}
+			service := &corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "10.0.0.10"}}
+
+			controllerReconciler := &PangolinBindingReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			resource, err := controllerReconciler.reconcileResourceForBinding(ctx, binding, org, tunnel, service)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = k8sClient.Delete(ctx, resource) }()
+
+			Expect(resource.Spec.Targets).To(HaveLen(1))
+			Expect(resource.Spec.Targets[0].Method).To(Equal("https"))
+		})
+
+		It("should fall back to the protocol without an org default", func() {
+			targets := []tunnelv1alpha1.TargetConfig{{IP: "10.0.0.1", Port: 80}, {IP: "10.0.0.2", Port: 80, Method: "http"}}
+			applyDefaultTargetMethods(targets, "tcp", org)
+			Expect(targets[0].Method).To(Equal("tcp"))
+			Expect(targets[1].Method).To(Equal("http"))
+			Expect(targetMethodForProtocol("http", nil)).To(Equal("http"))
+		})
+	})
+
+	Context("When a binding combines Service endpoints and static targets", func() {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: "10.96.0.10",
+				Ports:     []corev1.ServicePort{{Name: "postgres", Port: 5432}},
+			},
+		}
+		newBinding := func() *tunnelv1alpha1.PangolinBinding {
+			return &tunnelv1alpha1.PangolinBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinBindingSpec{
+					Protocol:      "tcp",
+					ServicePort:   5432,
+					StaticTargets: []tunnelv1alpha1.TargetConfig{{IP: "192.168.1.50", Port: 5432}},
+				},
+				Status: tunnelv1alpha1.PangolinBindingStatus{ServiceEndpoints: []string{"10.0.1.1", "10.0.1.2"}},
+			}
+		}
+
+		It("should resolve the endpoint port from the service port name", func() {
+			endpoints := &corev1.Endpoints{Subsets: []corev1.EndpointSubset{{
+				Ports: []corev1.EndpointPort{{Name: "metrics", Port: 9187}, {Name: "postgres", Port: 15432}},
+			}}}
+			Expect(endpointPortForService(service, 5432, endpoints)).To(Equal(int32(15432)))
+			Expect(endpointPortForService(service, 80, endpoints)).To(BeZero())
+		})
+
+		It("should target every endpoint and the static target", func() {
+			targets, err := desiredBindingTargets(newBinding(), nil, service, 15432)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets).To(Equal([]tunnelv1alpha1.TargetConfig{
+				{IP: "10.0.1.1", Port: 15432, Method: "tcp"},
+				{IP: "10.0.1.2", Port: 15432, Method: "tcp"},
+				{IP: "192.168.1.50", Port: 5432, Method: "tcp"},
+			}))
+
+			By("falling back to the ClusterIP without endpoints")
+			binding := newBinding()
+			binding.Status.ServiceEndpoints = nil
+			targets, err = desiredBindingTargets(binding, nil, service, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets).To(Equal([]tunnelv1alpha1.TargetConfig{
+				{IP: "10.96.0.10", Port: 5432, Method: "tcp"},
+				{IP: "192.168.1.50", Port: 5432, Method: "tcp"},
+			}))
+		})
+
+		It("should target the Service DNS name in dns mode", func() {
+			binding := newBinding()
+			binding.Spec.TargetMode = tunnelv1alpha1.TargetModeDNS
+			targets, err := desiredBindingTargets(binding, nil, service, 15432)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets).To(Equal([]tunnelv1alpha1.TargetConfig{
+				{IP: "db.default.svc.cluster.local", Port: 5432, Method: "tcp"},
+				{IP: "192.168.1.50", Port: 5432, Method: "tcp"},
+			}))
+		})
+
+		It("should not register a headless Service's ClusterIP", func() {
+			headless := service.DeepCopy()
+			headless.Spec.ClusterIP = corev1.ClusterIPNone
+
+			binding := newBinding()
+			binding.Spec.TargetMode = tunnelv1alpha1.TargetModeClusterIP
+			_, err := desiredBindingTargets(binding, nil, headless, 15432)
+			Expect(err).To(MatchError(ContainSubstring("headless")))
+
+			By("falling back to the DNS name in endpoints mode without endpoints")
+			binding.Spec.TargetMode = tunnelv1alpha1.TargetModeEndpoints
+			binding.Status.ServiceEndpoints = nil
+			targets, err := desiredBindingTargets(binding, nil, headless, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets[0].IP).To(Equal("db.default.svc.cluster.local"))
+		})
+
+		It("should target the external name of an ExternalName Service", func() {
+			external := service.DeepCopy()
+			external.Spec.Type = corev1.ServiceTypeExternalName
+			external.Spec.ClusterIP = ""
+			external.Spec.ExternalName = "db.example.com"
+
+			targets, err := desiredBindingTargets(newBinding(), nil, external, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets[0]).To(Equal(tunnelv1alpha1.TargetConfig{IP: "db.example.com", Port: 5432, Method: "tcp"}))
+			Expect(tracksEndpoints(newBinding(), external)).To(BeFalse())
+
+			binding := newBinding()
+			binding.Spec.TargetMode = tunnelv1alpha1.TargetModeClusterIP
+			_, err = desiredBindingTargets(binding, nil, external, 0)
+			Expect(err).To(MatchError(ContainSubstring("ExternalName")))
+		})
+
+		It("should target the load balancer ingress in loadBalancer mode", func() {
+			binding := newBinding()
+			binding.Spec.TargetMode = tunnelv1alpha1.TargetModeLoadBalancer
+			_, err := serviceTargetAddress(binding, service, 0)
+			Expect(err).To(MatchError(ContainSubstring("requires a LoadBalancer service")))
+
+			lb := service.DeepCopy()
+			lb.Spec.Type = corev1.ServiceTypeLoadBalancer
+			_, err = serviceTargetAddress(binding, lb, 0)
+			Expect(err).To(MatchError(ContainSubstring("no load balancer ingress")))
+
+			lb.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}}
+			Expect(serviceTargetAddress(binding, lb, 0)).To(Equal("lb.example.com"))
+		})
+
+		It("should update the generated resource with all three targets", func() {
+			ctx := context.Background()
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-binding", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol: "tcp",
+					Targets:  []tunnelv1alpha1.TargetConfig{{IP: "10.96.0.10", Port: 5432, Method: "tcp"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &PangolinBindingReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			Expect(controllerReconciler.reconcileBindingResourceSpec(ctx, newBinding(), nil, service, resource, 15432)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+			ips := []string{}
+			for _, t := range resource.Spec.Targets {
+				ips = append(ips, t.IP)
+			}
+			Expect(ips).To(ConsistOf("10.0.1.1", "10.0.1.2", "192.168.1.50"))
+		})
+	})
+
+	Context("When the Service uses session affinity", func() {
+		It("should make the generated resource sticky for ClientIP affinity", func() {
+			ctx := context.Background()
+			binding := &tunnelv1alpha1.PangolinBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "sticky", Namespace: "default", UID: "sticky-uid"},
+				Spec: tunnelv1alpha1.PangolinBindingSpec{
+					ServiceRef:  tunnelv1alpha1.ServiceReference{Name: "web", Namespace: "default"},
+					ServicePort: 80,
+					Protocol:    "http",
+				},
+			}
+			binding.APIVersion = tunnelv1alpha1.GroupVersion.String()
+			binding.Kind = "PangolinBinding"
+			service := &corev1.Service{Spec: corev1.ServiceSpec{
+				ClusterIP:       "10.96.0.20",
+				SessionAffinity: corev1.ServiceAffinityClientIP,
+			}}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{ObjectMeta: metav1.ObjectMeta{Name: "tunnel"}}
+
+			controllerReconciler := &PangolinBindingReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			resource, err := controllerReconciler.reconcileResourceForBinding(ctx, binding, nil, tunnel, service)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			Expect(resource.Spec.StickySession).To(HaveValue(BeTrue()))
+		})
+
+		It("should let the binding override the Service affinity", func() {
+			sticky := false
+			binding := &tunnelv1alpha1.PangolinBinding{Spec: tunnelv1alpha1.PangolinBindingSpec{StickySession: &sticky}}
+			service := &corev1.Service{Spec: corev1.ServiceSpec{SessionAffinity: corev1.ServiceAffinityClientIP}}
+			Expect(stickySessionForBinding(binding, service)).To(HaveValue(BeFalse()))
+
+			binding.Spec.StickySession = nil
+			service.Spec.SessionAffinity = corev1.ServiceAffinityNone
+			Expect(stickySessionForBinding(binding, service)).To(HaveValue(BeFalse()))
+		})
+	})
+
+	Context("When the endpoints of a bound Service change", func() {
+		ctx := context.Background()
+
+		It("should enqueue the bindings of that Service", func() {
+			for name, service := range map[string]string{"web-binding": "web", "db-binding": "db"} {
+				binding := &tunnelv1alpha1.PangolinBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec: tunnelv1alpha1.PangolinBindingSpec{
+						ServiceRef:      tunnelv1alpha1.ServiceReference{Name: service, Namespace: "apps"},
+						OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+						Protocol:        "tcp",
+						ServicePort:     80,
+						ProxyConfig:     &tunnelv1alpha1.ProxyConfig{ProxyPort: 8080},
+					},
+				}
+				Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, binding)
+			}
+
+			controllerReconciler := &PangolinBindingReconciler{Client: k8sClient}
+			endpoints := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"}}
+			Expect(controllerReconciler.bindingsForEndpoints(ctx, endpoints)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "web-binding", Namespace: "default"}}))
+
+			endpoints.Namespace = "default"
+			Expect(controllerReconciler.bindingsForEndpoints(ctx, endpoints)).To(BeEmpty())
+		})
+
+		It("should only pass updates that change the addresses or ports", func() {
+			subsets := func(ips ...string) []corev1.EndpointSubset {
+				subset := corev1.EndpointSubset{Ports: []corev1.EndpointPort{{Port: 8080}}}
+				for _, ip := range ips {
+					subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+				}
+				return []corev1.EndpointSubset{subset}
+			}
+			oldEndpoints := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Subsets: subsets("10.0.0.1")}
+
+			annotated := oldEndpoints.DeepCopy()
+			annotated.Annotations = map[string]string{"endpoints.kubernetes.io/last-change-trigger-time": "now"}
+			Expect(endpointSubsetsChanged().Update(event.UpdateEvent{ObjectOld: oldEndpoints, ObjectNew: annotated})).To(BeFalse())
+
+			scaled := oldEndpoints.DeepCopy()
+			scaled.Subsets = subsets("10.0.0.1", "10.0.0.2")
+			Expect(endpointSubsetsChanged().Update(event.UpdateEvent{ObjectOld: oldEndpoints, ObjectNew: scaled})).To(BeTrue())
+		})
+	})
+
+	Context("When a binding is deleted", func() {
+		ctx := context.Background()
+
+		It("should wait for the generated resource to be deleted before releasing the binding", func() {
+			binding := &tunnelv1alpha1.PangolinBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "deleted",
+					Namespace:  "default",
+					Finalizers: []string{BindingFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinBindingSpec{
+					ServiceRef:      tunnelv1alpha1.ServiceReference{Name: "web"},
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+					Protocol:        "http",
+					ServicePort:     80,
+				},
+			}
+			Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+
+			controller := true
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       generatedResourceName(binding),
+					Namespace:  "default",
+					Finalizers: []string{ResourceFinalizerName},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: tunnelv1alpha1.GroupVersion.String(),
+						Kind:       "PangolinBinding",
+						Name:       binding.Name,
+						UID:        binding.UID,
+						Controller: &controller,
+					}},
+				},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef: tunnelv1alpha1.LocalObjectReference{Name: "tunnel"},
+					Name:      "deleted",
+					Protocol:  "http",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, binding)).To(Succeed())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(binding), binding)).To(Succeed())
+
+			controllerReconciler := &PangolinBindingReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			result, err := controllerReconciler.handleBindingDeletion(ctx, binding)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+			Expect(resource.DeletionTimestamp).NotTo(BeNil())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(binding), binding)).To(Succeed())
+			Expect(binding.Finalizers).To(ContainElement(BindingFinalizerName))
+
+			By("releasing the binding once the resource is gone")
+			resource.Finalizers = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			result, err = controllerReconciler.handleBindingDeletion(ctx, binding)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(binding), binding))).To(BeTrue())
+		})
+	})
+
+	Context("When a binding names its service port", func() {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: "10.96.0.30",
+				Ports:     []corev1.ServicePort{{Name: "http", Port: 8080}, {Name: "metrics", Port: 9090}},
+			},
+		}
+
+		It("should resolve the name, preferring it over the port number", func() {
+			binding := &tunnelv1alpha1.PangolinBinding{Spec: tunnelv1alpha1.PangolinBindingSpec{
+				Protocol:        "http",
+				ServicePort:     80,
+				ServicePortName: "http",
+				TargetMode:      tunnelv1alpha1.TargetModeClusterIP,
+			}}
+			Expect(bindingServicePort(binding, service)).To(Equal(int32(8080)))
+
+			targets, err := desiredBindingTargets(binding, nil, service, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets).To(Equal([]tunnelv1alpha1.TargetConfig{{IP: "10.96.0.30", Port: 8080, Method: "http"}}))
+
+			binding.Spec.ServicePortName = ""
+			Expect(bindingServicePort(binding, service)).To(Equal(int32(80)))
+		})
+
+		It("should name the available ports when the name is unknown", func() {
+			binding := &tunnelv1alpha1.PangolinBinding{Spec: tunnelv1alpha1.PangolinBindingSpec{ServicePortName: "https"}}
+			_, err := bindingServicePort(binding, service)
+			Expect(err).To(MatchError(ContainSubstring(`no port named "https", available ports: http (8080), metrics (9090)`)))
+		})
+	})
+
+	Context("When referencing a tunnel in another namespace", func() {
+		ctx := context.Background()
+		var org *tunnelv1alpha1.PangolinOrganization
+
+		BeforeEach(func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "pangolin-system"}}
+			if err := k8sClient.Create(ctx, ns); err != nil && !errors.IsAlreadyExists(err) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			org = &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "central-org", Namespace: "pangolin-system"},
+				Spec:       tunnelv1alpha1.PangolinOrganizationSpec{APIEndpoint: "http://pangolin"},
+			}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "central-tunnel", Namespace: "pangolin-system"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "central-org"},
+				},
+			}
+			for _, obj := range []client.Object{org, tunnel} {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, obj)
+			}
+		})
+
+		bindingWithTunnel := func(name string) *tunnelv1alpha1.PangolinBinding {
+			return &tunnelv1alpha1.PangolinBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-binding", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinBindingSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "central-org", Namespace: "pangolin-system"},
+					TunnelRef:       &tunnelv1alpha1.LocalObjectReference{Name: name, Namespace: "pangolin-system"},
+				},
+			}
+		}
+
+		It("should reject the tunnel of a binding by default", func() {
+			controllerReconciler := &PangolinBindingReconciler{Client: k8sClient, AllowCrossNamespaceOrg: true}
+			_, err := controllerReconciler.ensureTunnelForBinding(ctx, bindingWithTunnel("central-tunnel"), org)
+			Expect(err).To(MatchError(ContainSubstring("allow-cross-namespace-tunnel")))
+
+			By("keeping cross-namespace tunnel references of resources working")
+			resourceReconciler := &PangolinResourceReconciler{Client: k8sClient}
+			tunnel, err := resourceReconciler.getTunnelForResource(ctx, &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-resource", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef: tunnelv1alpha1.LocalObjectReference{Name: "central-tunnel", Namespace: "pangolin-system"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tunnel.Namespace).To(Equal("pangolin-system"))
+		})
+
+		It("should use the tunnel when allowed and reference it from the generated resource", func() {
+			controllerReconciler := &PangolinBindingReconciler{
+				Client:                    k8sClient,
+				AllowCrossNamespaceOrg:    true,
+				AllowCrossNamespaceTunnel: true,
+			}
+			binding := bindingWithTunnel("central-tunnel")
+			tunnel, err := controllerReconciler.ensureTunnelForBinding(ctx, binding, org)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tunnel.Namespace).To(Equal("pangolin-system"))
+			Expect(tunnelRefForBinding(binding, tunnel)).To(Equal(
+				tunnelv1alpha1.LocalObjectReference{Name: "central-tunnel", Namespace: "pangolin-system"}))
+
+			resourceReconciler := &PangolinResourceReconciler{Client: k8sClient}
+			resolved, err := resourceReconciler.getTunnelForResource(ctx, &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "central-tunnel-binding", Namespace: "default"},
+				Spec:       tunnelv1alpha1.PangolinResourceSpec{TunnelRef: tunnelRefForBinding(binding, tunnel)},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Namespace).To(Equal("pangolin-system"))
+		})
+
+		It("should reject a tunnel of another organization", func() {
+			other := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-tunnel", Namespace: "pangolin-system"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "other-org"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, other)
+
+			controllerReconciler := &PangolinBindingReconciler{
+				Client:                    k8sClient,
+				AllowCrossNamespaceOrg:    true,
+				AllowCrossNamespaceTunnel: true,
+			}
+			_, err := controllerReconciler.ensureTunnelForBinding(ctx, bindingWithTunnel("other-tunnel"), org)
+			Expect(err).To(MatchError("tunnel pangolin-system/other-tunnel does not use organization pangolin-system/central-org of the binding"))
+		})
+	})
+
+	Context("When a dependency of a binding is not ready", func() {
+		It("should report each dependency in its own condition", func() {
+			binding := &tunnelv1alpha1.PangolinBinding{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+			setDependencyReady(binding, OrganizationReadyCondition, "Organization", "acme", "Ready")
+			message := setDependencyNotReady(binding, TunnelReadyCondition, "Tunnel", "edge", "Error",
+				[]metav1.Condition{{Type: "Ready", Status: metav1.ConditionFalse, Message: "site not found"}})
+			Expect(message).To(Equal("Waiting for tunnel to be ready: Tunnel edge is Error: site not found"))
+
+			organization := meta.FindStatusCondition(binding.Status.Conditions, OrganizationReadyCondition)
+			Expect(organization.Status).To(Equal(metav1.ConditionTrue))
+			tunnel := meta.FindStatusCondition(binding.Status.Conditions, TunnelReadyCondition)
+			Expect(tunnel.Status).To(Equal(metav1.ConditionFalse))
+			Expect(tunnel.Reason).To(Equal("Error"))
+			Expect(tunnel.ObservedGeneration).To(Equal(int64(2)))
+			resource := meta.FindStatusCondition(binding.Status.Conditions, ResourceReadyCondition)
+			Expect(resource.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(resource.Reason).To(Equal("Blocked"))
+
+			By("reporting a dependency that has not been reconciled yet")
+			message = setDependencyNotReady(binding, ResourceReadyCondition, "Resource", "web", "", nil)
+			Expect(message).To(Equal("Waiting for resource to be ready: Resource web has not been reconciled yet"))
+			Expect(meta.FindStatusCondition(binding.Status.Conditions, ResourceReadyCondition).Reason).
+				To(Equal("NotReconciled"))
+
+			By("reporting a missing dependency as not found")
+			setDependencyFailed(binding, OrganizationReadyCondition, fmt.Errorf("failed to get organization default/acme: %w",
+				errors.NewNotFound(tunnelv1alpha1.GroupVersion.WithResource("pangolinorganizations").GroupResource(), "acme")))
+			organization = meta.FindStatusCondition(binding.Status.Conditions, OrganizationReadyCondition)
+			Expect(organization.Status).To(Equal(metav1.ConditionFalse))
+			Expect(organization.Reason).To(Equal("NotFound"))
+			Expect(meta.FindStatusCondition(binding.Status.Conditions, TunnelReadyCondition).Status).
+				To(Equal(metav1.ConditionUnknown))
+		})
+	})
+
+	Context("When the Service of a binding is missing", func() {
+		ctx := context.Background()
+
+		It("should keep the generated resource for the grace period and delete it afterwards", func() {
+			binding := &tunnelv1alpha1.PangolinBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "orphaned",
+					Namespace:  "default",
+					Finalizers: []string{BindingFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinBindingSpec{
+					ServiceRef:      tunnelv1alpha1.ServiceReference{Name: "gone", Namespace: "default"},
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+					Protocol:        "http",
+					ServicePort:     80,
+				},
+			}
+			Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+			DeferCleanup(func() {
+				binding.Finalizers = nil
+				Expect(client.IgnoreNotFound(k8sClient.Update(ctx, binding))).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, binding))).To(Succeed())
+			})
+
+			controller := true
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      generatedResourceName(binding),
+					Namespace: "default",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: tunnelv1alpha1.GroupVersion.String(),
+						Kind:       "PangolinBinding",
+						Name:       binding.Name,
+						UID:        binding.UID,
+						Controller: &controller,
+					}},
+				},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef: tunnelv1alpha1.LocalObjectReference{Name: "tunnel"},
+					Name:      "orphaned",
+					Protocol:  "http",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+			})
+
+			request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(binding)}
+			reconciler := &PangolinBindingReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, request.NamespacedName, binding)).To(Succeed())
+			Expect(binding.Status.Status).To(Equal("ServiceMissing"))
+			Expect(binding.Status.ServiceMissingSince).NotTo(BeNil())
+			Expect(meta.FindStatusCondition(binding.Status.Conditions, "Ready").Reason).To(Equal("ServiceMissing"))
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+
+			By("keeping the resource within the grace period")
+			reconciler.MissingServiceGracePeriod = time.Hour
+			result, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", waitingRequeueAfter))
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+
+			By("deleting the resource once the Service stays missing")
+			Expect(k8sClient.Get(ctx, request.NamespacedName, binding)).To(Succeed())
+			binding.Status.ServiceMissingSince = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+			binding.Status.GeneratedResourceName = resource.Name
+			Expect(k8sClient.Status().Update(ctx, binding)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, request.NamespacedName, binding)).To(Succeed())
+			Expect(binding.Status.Status).To(Equal("ServiceMissing"))
+			Expect(binding.Status.GeneratedResourceName).To(BeEmpty())
+		})
+	})
+
+	Context("When a binding weights its endpoints by a pod label", func() {
+		ctx := context.Background()
+
+		It("should weight each endpoint target by the label of its pod", func() {
+			for name, track := range map[string]string{"web-stable": "stable", "web-canary": "canary", "web-other": "other"} {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"track": track}},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx"}}},
+				}
+				Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod))).To(Succeed())
+				})
+			}
+			podAddress := func(ip, pod string) corev1.EndpointAddress {
+				return corev1.EndpointAddress{IP: ip, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: pod}}
+			}
+			endpoints := &corev1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Subsets: []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{
+					podAddress("10.0.1.1", "web-stable"), podAddress("10.0.1.2", "web-canary"),
+					podAddress("10.0.1.3", "web-other"), {IP: "10.0.1.4"},
+				}}},
+			}
+			binding := &tunnelv1alpha1.PangolinBinding{
+				Spec: tunnelv1alpha1.PangolinBindingSpec{
+					Protocol:    "http",
+					ServicePort: 80,
+					EndpointWeights: &tunnelv1alpha1.EndpointWeights{
+						LabelKey: "track",
+						Weights:  map[string]int32{"stable": 90, "canary": 10},
+					},
+				},
+			}
+
+			reconciler := &PangolinBindingReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			weights, err := reconciler.endpointWeights(ctx, binding, endpoints)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(weights).To(Equal(map[string]int32{"10.0.1.1": 90, "10.0.1.2": 10}))
+
+			By("falling back to the default weight")
+			defaultWeight := int32(0)
+			binding.Spec.EndpointWeights.DefaultWeight = &defaultWeight
+			weights, err = reconciler.endpointWeights(ctx, binding, endpoints)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(weights).To(Equal(map[string]int32{"10.0.1.1": 90, "10.0.1.2": 10, "10.0.1.3": 0, "10.0.1.4": 0}))
+
+			By("weighting the generated targets")
+			binding.Status.ServiceEndpoints = []string{"10.0.1.1", "10.0.1.2"}
+			binding.Status.ServiceEndpointWeights = weights
+			service := &corev1.Service{Spec: corev1.ServiceSpec{
+				ClusterIP: "10.96.0.20",
+				Ports:     []corev1.ServicePort{{Port: 80}},
+			}}
+			targets, err := desiredBindingTargets(binding, nil, service, 8080)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets).To(HaveLen(2))
+			Expect(*targets[0].Weight).To(Equal(int32(90)))
+			Expect(*targets[1].Weight).To(Equal(int32(10)))
+		})
+	})
 })
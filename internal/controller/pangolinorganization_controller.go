@@ -3,17 +3,23 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
 	"github.com/bovf/pangolin-operator/pkg/pangolin"
@@ -26,13 +32,45 @@ const (
 // PangolinOrganizationReconciler reconciles a PangolinOrganization object
 type PangolinOrganizationReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// Class restricts this instance to objects with a matching pangolin.io/class
+	// label or annotation. Empty manages only unclassed objects.
+	Class string
+
+	// AllowCrossNamespaceOrg allows organizationRef to point to an organization in another namespace.
+	AllowCrossNamespaceOrg bool
+
+	// ErrorBackoffCap is the longest wait between retries of an object in Error.
+	// Zero uses DefaultErrorBackoffCap.
+	ErrorBackoffCap time.Duration
+
+	// ErrorRequeueInterval is the wait before the first retry of an object in
+	// Error. Zero uses DefaultErrorRequeueInterval.
+	ErrorRequeueInterval time.Duration
+
+	// ResyncInterval, when set, re-reconciles Ready objects that often to correct
+	// drift made outside the operator.
+	ResyncInterval time.Duration
+
+	// ReconcileTimeout bounds each reconcile, cancelling its Pangolin API
+	// requests when exceeded. Zero leaves reconciles unbounded.
+	ReconcileTimeout time.Duration
+
+	backoff *errorBackoff
+	clients *pangolinClientCache
+
+	// FullSync, when set, periodically enqueues every object for a full reconcile.
+	FullSync *FullSync
 }
 
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolinorganizations,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolinorganizations/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolinorganizations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolintunnels;pangolinresources,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile implements the reconciliation logic for PangolinOrganization.
 //
@@ -44,11 +82,16 @@ type PangolinOrganizationReconciler struct {
 //  1. Fetch PangolinOrganization from Kubernetes
 //  2. Handle deletion if organization is being deleted
 //  3. Add finalizer if not present
-//  4. Create Pangolin API client using credentials from secret
-//  5. Reconcile organization (bind to existing or discover first available)
-//  6. Discover and cache all available domains
-//  7. Resolve default domain from spec or use first verified domain
-//  8. Update status with organization info, domains, and default domain
+//  4. Validate and normalize spec.apiEndpoint, see normalizeAPIEndpoint
+//  5. Create Pangolin API client using credentials from secret
+//  6. Reconcile organization (bind to existing or discover first available)
+//  7. Discover and cache all available domains
+//  8. Resolve default domain from spec or use first verified domain
+//  9. Flag a rate limit below spec.rateLimitWarningThreshold, see reconcileRateLimit
+//  10. Report the API key in use, see reconcileActiveAPIKey
+//  11. Flag referenced domains awaiting verification, see reconcileDomainVerification
+//  12. Update status with organization info, domains, and default domain,
+//     Waiting while referenced domains await verification
 //
 // Organization Modes:
 //
@@ -87,6 +130,11 @@ func (r *PangolinOrganizationReconciler) Reconcile(ctx context.Context, req ctrl
 		return ctrl.Result{}, err
 	}
 
+	// Ignore objects managed by another operator instance
+	if !matchesClass(org, r.Class) {
+		return ctrl.Result{}, nil
+	}
+
 	// Handle deletion if organization is being deleted
 	if org.DeletionTimestamp != nil {
 		return r.handleOrganizationDeletion(ctx, org)
@@ -98,28 +146,64 @@ func (r *PangolinOrganizationReconciler) Reconcile(ctx context.Context, req ctrl
 		return ctrl.Result{}, r.Update(ctx, org)
 	}
 
+	// Skip API calls while paused
+	if msg := pausedMessage(org, nil); msg != "" {
+		return markPaused(ctx, r.Client, org, &org.Status.Conditions, msg)
+	}
+
+	// Reject a malformed endpoint before any API call is made against it
+	if err := reconcileAPIEndpoint(org); err != nil {
+		logger.Error(err, "Invalid API endpoint")
+		return r.updateOrganizationError(ctx, org, err)
+	}
+
 	// Create Pangolin API client using credentials from secret
 	apiClient, err := r.createPangolinClient(ctx, org)
 	if err != nil {
 		logger.Error(err, "Failed to create Pangolin API client")
-		return r.updateOrganizationStatus(ctx, org, "Error", err.Error())
+		return r.updateOrganizationError(ctx, org, err)
 	}
 
 	// Reconcile organization (bind to existing or discover)
+	previousSubnet := org.Status.Subnet
 	err = r.reconcileOrganization(ctx, org, apiClient)
 	if err != nil {
 		logger.Error(err, "Failed to reconcile organization")
-		return r.updateOrganizationStatus(ctx, org, "Error", err.Error())
+		return r.updateOrganizationError(ctx, org, err)
+	}
+	if previousSubnet != "" && org.Status.Subnet != previousSubnet {
+		r.recordSubnetChange(ctx, org, previousSubnet)
 	}
 
+	// Record server version and capabilities for version-dependent features
+	r.reconcileServerInfo(ctx, org, apiClient)
+
 	// Discover and cache all available domains
 	err = r.reconcileDomains(ctx, org, apiClient)
 	if err != nil {
 		logger.Error(err, "Failed to reconcile domains")
-		return r.updateOrganizationStatus(ctx, org, "Error", err.Error())
+		return r.updateOrganizationError(ctx, org, err)
 	}
 
-	return r.updateOrganizationStatus(ctx, org, "Ready", "Organization is ready")
+	// Warn before the API key runs out of requests
+	reconcileRateLimit(org, apiClient)
+
+	// Report which API key was accepted
+	r.reconcileActiveAPIKey(ctx, org, apiClient)
+
+	// Come back for referenced domains until Pangolin has verified them
+	verificationWait := r.reconcileDomainVerification(ctx, org)
+
+	status, message := "Ready", "Organization is ready"
+	if verificationWait > 0 {
+		status = "Waiting"
+		message = meta.FindStatusCondition(org.Status.Conditions, DomainPendingCondition).Message
+	}
+	result, err := r.updateOrganizationStatus(ctx, org, status, message)
+	if verificationWait > 0 {
+		result.RequeueAfter = verificationWait
+	}
+	return result, err
 }
 
 // createPangolinClient creates a Pangolin API client from the organization spec.
@@ -140,26 +224,11 @@ func (r *PangolinOrganizationReconciler) Reconcile(ctx context.Context, req ctrl
 //   - Secret exists in the same namespace as the organization
 //   - Secret contains the specified key
 //   - API key is not empty
+//
+// The client is reused across reconciles until its settings change, see
+// pangolinClientCache.
 func (r *PangolinOrganizationReconciler) createPangolinClient(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization) (*pangolin.Client, error) {
-	// Get API key from secret
-	secret := &corev1.Secret{}
-	err := r.Get(ctx, types.NamespacedName{
-		Namespace: org.Namespace,
-		Name:      org.Spec.APIKeyRef.Name,
-	}, secret)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key secret: %w", err)
-	}
-
-	apiKey, ok := secret.Data[org.Spec.APIKeyRef.Key]
-	if !ok {
-		return nil, fmt.Errorf("API key not found in secret")
-	}
-
-	logger := log.FromContext(ctx)
-	logger.Info("createPangolinClient", "apiKey", string(apiKey))
-
-	return pangolin.NewClient(org.Spec.APIEndpoint, string(apiKey)), nil
+	return organizationClient(ctx, r.Client, r.clients, org, org.Spec.APIKeyRef)
 }
 
 // reconcileOrganization handles organization binding or discovery.
@@ -167,41 +236,31 @@ func (r *PangolinOrganizationReconciler) createPangolinClient(ctx context.Contex
 // Two Modes:
 //
 // 1. Binding Mode (spec.organizationId specified):
-//   - Lists all accessible organizations from API
-//   - Finds organization matching the specified ID
+//   - Fetches the organization with the specified ID, see getOrganization
 //   - Returns error if organization not found or not accessible
 //   - Sets status.bindingMode = "Bound"
 //
 // 2. Discovery Mode (spec.organizationId empty):
+//   - Is an error if spec.disableDiscovery is set
 //   - Lists all accessible organizations from API
-//   - Uses the first organization found
-//   - Useful when API key has access to exactly one organization
+//   - Uses the only organization the API key can access, or keeps the
+//     previously discovered one while it is still listed, see discoverOrganization
+//   - Returns an error listing the candidates if the choice is ambiguous
 //   - Sets status.bindingMode = "Discovered"
 //
 // Status Updates:
 //   - organizationId: Pangolin organization identifier
 //   - organizationName: Human-readable organization name
-//   - subnet: Organization's network subnet (if available)
+//   - subnet: Organization's network subnet (if available), refreshed on every
+//     reconcile; a change emits a SubnetChanged event and the status update
+//     re-enqueues the tunnels and bindings referencing the organization
 //   - bindingMode: "Bound" or "Discovered"
 func (r *PangolinOrganizationReconciler) reconcileOrganization(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization, apiClient *pangolin.Client) error {
 	if org.Spec.OrganizationID != "" {
 		// BINDING MODE: Bind to existing organization
-		orgs, err := apiClient.ListOrganizations(ctx)
+		targetOrg, err := getOrganization(ctx, apiClient, org.Spec.OrganizationID)
 		if err != nil {
-			return fmt.Errorf("failed to list organizations: %w", err)
-		}
-
-		// Find the specified organization
-		var targetOrg *pangolin.Organization
-		for _, o := range orgs {
-			if o.OrgID == org.Spec.OrganizationID {
-				targetOrg = &o
-				break
-			}
-		}
-
-		if targetOrg == nil {
-			return fmt.Errorf("organization %s not found", org.Spec.OrganizationID)
+			return err
 		}
 
 		// Update status from API response
@@ -211,26 +270,158 @@ func (r *PangolinOrganizationReconciler) reconcileOrganization(ctx context.Conte
 		org.Status.BindingMode = "Bound"
 
 	} else {
-		// DISCOVERY MODE: Auto-discover first available organization
+		// DISCOVERY MODE: Use the organization the API key can access
+		if org.Spec.DisableDiscovery != nil && *org.Spec.DisableDiscovery {
+			return fmt.Errorf("organizationId required; discovery disabled")
+		}
 		orgs, err := apiClient.ListOrganizations(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to list organizations: %w", err)
 		}
 
-		if len(orgs) == 0 {
-			return fmt.Errorf("no organizations found")
+		discovered, err := discoverOrganization(orgs, org.Status.OrganizationID)
+		if err != nil {
+			return err
+		}
+		if discovered.OrgID != org.Status.OrganizationID {
+			log.FromContext(ctx).Info("Discovered organization", "orgId", discovered.OrgID,
+				"name", discovered.Name, "candidates", len(orgs))
 		}
 
-		// Use first organization
-		org.Status.OrganizationID = orgs[0].OrgID
-		org.Status.OrganizationName = orgs[0].Name
-		org.Status.Subnet = orgs[0].Subnet
+		org.Status.OrganizationID = discovered.OrgID
+		org.Status.OrganizationName = discovered.Name
+		org.Status.Subnet = discovered.Subnet
 		org.Status.BindingMode = "Discovered"
 	}
 
 	return nil
 }
 
+// getOrganization fetches the organization with orgID. Servers that cannot get a
+// single organization, rejecting the method or not serving the route at all, are
+// served by listing all organizations the API key can access and picking it
+// from the list.
+func getOrganization(ctx context.Context, apiClient *pangolin.Client, orgID string) (*pangolin.Organization, error) {
+	org, err := apiClient.GetOrganization(ctx, orgID)
+	routeMissing := pangolin.IsRouteNotFound(err)
+	if pangolin.IsNotFound(err) && !routeMissing {
+		return nil, fmt.Errorf("organization %s not found in Pangolin: %w", orgID, err)
+	}
+	if !pangolin.IsUnsupported(err) && !routeMissing {
+		if err != nil {
+			return nil, fmt.Errorf("failed to get organization %s: %w", orgID, err)
+		}
+		return org, nil
+	}
+
+	orgs, err := apiClient.ListOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	for _, o := range orgs {
+		if o.OrgID == orgID {
+			return &o, nil
+		}
+	}
+	return nil, fmt.Errorf("organization %s not found", orgID)
+}
+
+// recordSubnetChange reports a change of the organization subnet in Pangolin.
+func (r *PangolinOrganizationReconciler) recordSubnetChange(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization, previousSubnet string) {
+	log.FromContext(ctx).Info("Organization subnet changed", "from", previousSubnet, "to", org.Status.Subnet)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(org, corev1.EventTypeNormal, "SubnetChanged",
+			"Organization subnet changed from %s to %s", previousSubnet, org.Status.Subnet)
+	}
+}
+
+// reconcileActiveAPIKey reports the API key the organization client used last
+// in status.activeAPIKey, warning with an event when it falls back from
+// spec.apiKeyRef to spec.apiKeyFallbackRef. The field is cleared for
+// organizations without a fallback key.
+func (r *PangolinOrganizationReconciler) reconcileActiveAPIKey(
+	ctx context.Context,
+	org *tunnelv1alpha1.PangolinOrganization,
+	apiClient *pangolin.Client,
+) {
+	if org.Spec.APIKeyFallbackRef == nil {
+		org.Status.ActiveAPIKey = ""
+		return
+	}
+	active := "Primary"
+	if apiClient.UsingFallbackAPIKey() {
+		active = "Fallback"
+	}
+	if active == "Fallback" && org.Status.ActiveAPIKey != "Fallback" {
+		log.FromContext(ctx).Info("API key rejected, using the fallback API key",
+			"secret", org.Spec.APIKeyFallbackRef.Name)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(org, corev1.EventTypeWarning, "APIKeyFallback",
+				"API key in secret %s was rejected, using the fallback API key in secret %s",
+				org.Spec.APIKeyRef.Name, org.Spec.APIKeyFallbackRef.Name)
+		}
+	}
+	org.Status.ActiveAPIKey = active
+}
+
+// discoverOrganization picks the organization to use in discovery mode.
+//
+// Discovery must be unambiguous, as every tunnel and resource of the
+// organization is wired to the result: the API key has to see exactly one
+// organization. An organization discovered earlier is kept while it is still
+// listed, so granting the key access to another organization later does not
+// break a working setup. Otherwise more than one candidate is an error listing
+// them, asking for spec.organizationId.
+func discoverOrganization(orgs []pangolin.Organization, previousID string) (pangolin.Organization, error) {
+	if previousID != "" {
+		for _, o := range orgs {
+			if o.OrgID == previousID {
+				return o, nil
+			}
+		}
+	}
+
+	switch len(orgs) {
+	case 0:
+		return pangolin.Organization{}, fmt.Errorf("no organizations found")
+	case 1:
+		return orgs[0], nil
+	}
+
+	sorted := slices.Clone(orgs)
+	slices.SortFunc(sorted, func(a, b pangolin.Organization) int {
+		return strings.Compare(a.OrgID, b.OrgID)
+	})
+	candidates := make([]string, 0, len(sorted))
+	for _, o := range sorted {
+		candidates = append(candidates, fmt.Sprintf("%s (%s)", o.OrgID, o.Name))
+	}
+	return pangolin.Organization{}, fmt.Errorf("API key can access %d organizations, set spec.organizationId to one of: %s",
+		len(orgs), strings.Join(candidates, ", "))
+}
+
+// reconcileServerInfo records the Pangolin server version and capabilities in status.
+//
+// Servers without a version endpoint leave both fields empty. Errors are logged and
+// the previous values are kept, as the organization is usable without this information.
+func (r *PangolinOrganizationReconciler) reconcileServerInfo(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization, apiClient *pangolin.Client) {
+	logger := log.FromContext(ctx)
+
+	info, err := apiClient.GetServerInfo(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to get Pangolin server info")
+		return
+	}
+	if info == nil {
+		org.Status.ServerVersion = ""
+		org.Status.Capabilities = nil
+		return
+	}
+
+	org.Status.ServerVersion = info.Version
+	org.Status.Capabilities = info.Capabilities
+}
+
 // reconcileDomains fetches and caches all available domains for the organization.
 //
 // Domain Discovery:
@@ -285,6 +476,13 @@ func (r *PangolinOrganizationReconciler) reconcileDomains(ctx context.Context, o
 
 	// Update status with complete domain list
 	org.Status.Domains = crdDomains
+	org.Status.DomainCount = len(crdDomains)
+
+	// Forget a default domain that was removed, so it is resolved again below
+	if org.Status.DefaultDomainID != "" && !organizationHasDomain(org, org.Status.DefaultDomainID) {
+		logger.Info("Default domain was removed", "domainId", org.Status.DefaultDomainID)
+		org.Status.DefaultDomainID = ""
+	}
 
 	// Resolve default domain if specified in spec
 	if org.Spec.Defaults != nil && org.Spec.Defaults.DefaultDomain != "" {
@@ -347,61 +545,43 @@ func (r *PangolinOrganizationReconciler) resolveDomainToID(domainInput string, d
 //
 // Status values:
 //   - "Ready": Organization is configured, domains are cached
+//   - "Waiting": A referenced domain awaits verification, see reconcileDomainVerification
 //   - "Error": Reconciliation encountered an error
 //
 // The function also updates the Ready condition with appropriate reason and message.
-// If status is not "Ready", the reconcile will be requeued after 1 minute.
+// If status is not "Ready", the reconcile is requeued: errors with backoff, see
+// errorBackoff, anything else after 1 minute.
 func (r *PangolinOrganizationReconciler) updateOrganizationStatus(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization, status, message string) (ctrl.Result, error) {
 	org.Status.Status = status
 	org.Status.ObservedGeneration = org.Generation
+	org.Status.LastSyncTime = syncTime(org.Status.LastSyncTime, status, time.Now())
+	meta.RemoveStatusCondition(&org.Status.Conditions, PausedCondition)
 
-	// Create Ready condition
-	conditionType := "Ready"
-	conditionStatus := metav1.ConditionTrue
-	reason := "ReconcileSuccess"
-
-	if status != "Ready" {
-		conditionStatus = metav1.ConditionFalse
-		reason = "ReconcileError"
-	}
-
-	now := metav1.NewTime(time.Now())
-	newCondition := metav1.Condition{
-		Type:               conditionType,
-		Status:             conditionStatus,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: now,
-		ObservedGeneration: org.Generation,
-	}
-
-	// Update existing condition or append new one
-	conditionUpdated := false
-	for i, condition := range org.Status.Conditions {
-		if condition.Type == conditionType {
-			// Preserve LastTransitionTime if status hasn't changed
-			if condition.Status != conditionStatus {
-				newCondition.LastTransitionTime = now
-			} else {
-				newCondition.LastTransitionTime = condition.LastTransitionTime
-			}
-			org.Status.Conditions[i] = newCondition
-			conditionUpdated = true
-			break
-		}
+	setReadyCondition(&org.Status.Conditions, org.Generation, status, message)
+	if status == "Waiting" {
+		// Organizations only wait for their domains to be verified
+		setCondition(&org.Status.Conditions, "Ready", metav1.ConditionFalse, "AwaitingVerification", message, org.Generation)
 	}
 
-	if !conditionUpdated {
-		org.Status.Conditions = append(org.Status.Conditions, newCondition)
-	}
+	result := ctrl.Result{RequeueAfter: r.backoff.RequeueAfter(org, status)}
+
+	ctx, cancel := statusContext(ctx)
+	defer cancel()
 
-	// Update status
-	err := r.Status().Update(ctx, org)
-	if status != "Ready" {
-		return ctrl.Result{RequeueAfter: time.Minute}, err
+	// Skip the write when nothing changed
+	if statusUnchanged(ctx, r.Client, org) {
+		return result, nil
 	}
 
-	return ctrl.Result{}, err
+	return result, r.Status().Update(ctx, org)
+}
+
+// updateOrganizationError reports err as the Error status of org. A rate
+// limited Pangolin API error makes the retry wait at least its Retry-After, see
+// errorBackoff.ObserveError.
+func (r *PangolinOrganizationReconciler) updateOrganizationError(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization, err error) (ctrl.Result, error) {
+	r.backoff.ObserveError(org, err)
+	return r.updateOrganizationStatus(ctx, org, "Error", errorMessage(err))
 }
 
 // handleOrganizationDeletion handles cleanup when a PangolinOrganization is being deleted.
@@ -417,17 +597,48 @@ func (r *PangolinOrganizationReconciler) updateOrganizationStatus(ctx context.Co
 func (r *PangolinOrganizationReconciler) handleOrganizationDeletion(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization) (ctrl.Result, error) {
 	// Organizations are not deleted from Pangolin API
 	// Only remove the finalizer to allow Kubernetes to delete the resource
+	r.backoff.Forget(client.ObjectKeyFromObject(org))
+	deleteRateLimitMetrics(org)
 	controllerutil.RemoveFinalizer(org, OrganizationFinalizerName)
 	return ctrl.Result{}, r.Update(ctx, org)
 }
 
+// organizationsForSecret maps a Secret to the organizations using it as
+// apiKeyRef, apiKeyFallbackRef or apiHeadersSecretRef, so a rotated key or
+// header is validated right away.
+func (r *PangolinOrganizationReconciler) organizationsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	orgs := &tunnelv1alpha1.PangolinOrganizationList{}
+	if err := r.List(ctx, orgs, client.InNamespace(secret.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list organizations for secret", "secret", secret.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, org := range orgs.Items {
+		if !matchesClass(&org, r.Class) {
+			continue
+		}
+		if org.Spec.APIKeyRef.Name == secret.GetName() ||
+			(org.Spec.APIKeyFallbackRef != nil && org.Spec.APIKeyFallbackRef.Name == secret.GetName()) ||
+			(org.Spec.APIHeadersSecretRef != nil && org.Spec.APIHeadersSecretRef.Name == secret.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&org)})
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 //
 // Controller Configuration:
 //   - Watches PangolinOrganization resources for changes
-//   - Does not watch Secrets directly (manual trigger required for secret changes)
+//   - Watches Secrets and enqueues the organizations using them as apiKeyRef,
+//     apiKeyFallbackRef or apiHeadersSecretRef, so rotating the API key or the headers
+//     re-validates them against the Pangolin API
 func (r *PangolinOrganizationReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&tunnelv1alpha1.PangolinOrganization{}).
-		Complete(r)
+	r.backoff = newErrorBackoff(r.ErrorRequeueInterval, r.ErrorBackoffCap, r.ResyncInterval)
+	r.clients = newPangolinClientCache()
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&tunnelv1alpha1.PangolinOrganization{}, builder.WithPredicates(classPredicate(r.Class))).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.organizationsForSecret))
+	return r.FullSync.watch(b, &tunnelv1alpha1.PangolinOrganizationList{}, classPredicate(r.Class)).Complete(withReconcileTimeout(r, r.ReconcileTimeout))
 }
@@ -14,16 +14,30 @@ package controller
 
 import (
 	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
 )
 
 var _ = Describe("PangolinOrganization Controller", func() {
@@ -77,4 +91,965 @@ var _ = Describe("PangolinOrganization Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When filtering by class", func() {
+		It("should ignore objects belonging to another class", func() {
+			ctx := context.Background()
+			key := types.NamespacedName{Name: "org-other-class", Namespace: "default"}
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+					Labels:    map[string]string{ClassLabel: "b"},
+				},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: "http://127.0.0.1:1",
+				},
+			}
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, org)).To(Succeed()) }()
+
+			controllerReconciler := &PangolinOrganizationReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Class:  "a",
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, key, org)).To(Succeed())
+			Expect(org.Finalizers).To(BeEmpty())
+			Expect(org.Status.Status).To(BeEmpty())
+		})
+
+		It("should match the label before the annotation", func() {
+			obj := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{ClassLabel: "a"},
+					Annotations: map[string]string{ClassLabel: "b"},
+				},
+			}
+			Expect(matchesClass(obj, "a")).To(BeTrue())
+			Expect(matchesClass(obj, "b")).To(BeFalse())
+			Expect(matchesClass(&tunnelv1alpha1.PangolinOrganization{}, "")).To(BeTrue())
+			Expect(matchesClass(&tunnelv1alpha1.PangolinOrganization{}, "a")).To(BeFalse())
+		})
+	})
+
+	Context("When reading the Pangolin server info", func() {
+		It("should record the version and capabilities", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/version"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"version":"1.9.0","capabilities":["shareLinks","resourceEvents"]}}`))
+			}))
+			defer server.Close()
+
+			org := &tunnelv1alpha1.PangolinOrganization{}
+			(&PangolinOrganizationReconciler{}).reconcileServerInfo(context.Background(), org, pangolin.NewClient(server.URL, "token"))
+
+			Expect(org.Status.ServerVersion).To(Equal("1.9.0"))
+			Expect(org.Status.Capabilities).To(ConsistOf("shareLinks", "resourceEvents"))
+			Expect(org.Status.HasCapability("shareLinks")).To(BeTrue())
+			Expect(org.Status.HasCapability("pools")).To(BeFalse())
+		})
+
+		It("should leave the fields empty when the server has no version endpoint", func() {
+			server := httptest.NewServer(http.NotFoundHandler())
+			defer server.Close()
+
+			org := &tunnelv1alpha1.PangolinOrganization{}
+			(&PangolinOrganizationReconciler{}).reconcileServerInfo(context.Background(), org, pangolin.NewClient(server.URL, "token"))
+
+			Expect(org.Status.ServerVersion).To(BeEmpty())
+			Expect(org.Status.Capabilities).To(BeEmpty())
+		})
+	})
+
+	Context("When discovering the organization", func() {
+		discover := func(orgsJSON string, org *tunnelv1alpha1.PangolinOrganization) error {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/orgs"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":` + orgsJSON + `}}`))
+			}))
+			defer server.Close()
+
+			return (&PangolinOrganizationReconciler{}).reconcileOrganization(context.Background(), org, pangolin.NewClient(server.URL, "token"))
+		}
+
+		It("should use the only organization the API key can access", func() {
+			org := &tunnelv1alpha1.PangolinOrganization{}
+			Expect(discover(`[{"orgId":"alpha","name":"Alpha"}]`, org)).To(Succeed())
+			Expect(org.Status.OrganizationID).To(Equal("alpha"))
+			Expect(org.Status.BindingMode).To(Equal("Discovered"))
+		})
+
+		It("should refuse to guess between several organizations", func() {
+			for _, orgsJSON := range []string{
+				`[{"orgId":"beta","name":"Beta"},{"orgId":"alpha","name":"Alpha"}]`,
+				`[{"orgId":"alpha","name":"Alpha"},{"orgId":"beta","name":"Beta"}]`,
+			} {
+				org := &tunnelv1alpha1.PangolinOrganization{}
+				Expect(discover(orgsJSON, org)).To(MatchError("API key can access 2 organizations, " +
+					"set spec.organizationId to one of: alpha (Alpha), beta (Beta)"))
+				Expect(org.Status.OrganizationID).To(BeEmpty())
+			}
+
+			Expect(discover(`[]`, &tunnelv1alpha1.PangolinOrganization{})).To(MatchError("no organizations found"))
+		})
+
+		It("should keep the previously discovered organization while it is listed", func() {
+			org := &tunnelv1alpha1.PangolinOrganization{}
+			org.Status.OrganizationID = "beta"
+			Expect(discover(`[{"orgId":"beta","name":"Beta"},{"orgId":"alpha","name":"Alpha"}]`, org)).To(Succeed())
+			Expect(org.Status.OrganizationID).To(Equal("beta"))
+		})
+
+		It("should require an organization ID when discovery is disabled", func() {
+			org := &tunnelv1alpha1.PangolinOrganization{}
+			org.Spec.DisableDiscovery = &[]bool{true}[0]
+			Expect((&PangolinOrganizationReconciler{}).reconcileOrganization(context.Background(), org, nil)).To(
+				MatchError("organizationId required; discovery disabled"))
+			Expect(org.Status.OrganizationID).To(BeEmpty())
+		})
+	})
+
+	Context("When binding to an organization by ID", func() {
+		bind := func(handler http.HandlerFunc) (*tunnelv1alpha1.PangolinOrganization, error) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			org := &tunnelv1alpha1.PangolinOrganization{}
+			org.Spec.OrganizationID = "alpha"
+			err := (&PangolinOrganizationReconciler{}).reconcileOrganization(context.Background(), org, pangolin.NewClient(server.URL, "token"))
+			return org, err
+		}
+
+		It("should fetch the organization directly", func() {
+			org, err := bind(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/org/alpha"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"org":{"orgId":"alpha","name":"Alpha","subnet":"100.90.0.0/16"}}}`))
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(org.Status.OrganizationName).To(Equal("Alpha"))
+			Expect(org.Status.Subnet).To(Equal("100.90.0.0/16"))
+			Expect(org.Status.BindingMode).To(Equal("Bound"))
+		})
+
+		It("should report a missing organization without listing", func() {
+			_, err := bind(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/org/alpha"))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"success":false,"error":true,"message":"Organization with ID alpha not found","status":404}`))
+			})
+			Expect(err).To(MatchError(ContainSubstring("organization alpha not found in Pangolin")))
+		})
+
+		It("should fall back to listing when the server cannot get a single organization", func() {
+			org, err := bind(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if req.URL.Path != "/v1/orgs" {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[{"orgId":"alpha","name":"Alpha"}]}}`))
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(org.Status.OrganizationName).To(Equal("Alpha"))
+		})
+
+		It("should fall back to listing when the server does not serve the route", func() {
+			for _, body := range []string{
+				`Cannot GET /v1/org/alpha`,
+				`{"data":null,"success":false,"error":true,"message":"The requests url is not found - /v1/org/alpha","status":404}`,
+			} {
+				org, err := bind(func(w http.ResponseWriter, req *http.Request) {
+					if req.URL.Path != "/v1/orgs" {
+						w.WriteHeader(http.StatusNotFound)
+						_, _ = w.Write([]byte(body))
+						return
+					}
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[{"orgId":"alpha","name":"Alpha"}]}}`))
+				})
+				Expect(err).NotTo(HaveOccurred(), body)
+				Expect(org.Status.OrganizationName).To(Equal("Alpha"))
+			}
+		})
+	})
+
+	Context("When listing the organization domains", func() {
+		It("should count the domains for the printer columns", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/org/org1/domains"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"domains":[` +
+					`{"domainId":"d1","baseDomain":"example.com","verified":true},` +
+					`{"domainId":"d2","baseDomain":"example.org","verified":true}]}}`))
+			}))
+			defer server.Close()
+
+			org := &tunnelv1alpha1.PangolinOrganization{}
+			org.Status.OrganizationID = "org1"
+			Expect((&PangolinOrganizationReconciler{}).reconcileDomains(context.Background(), org, pangolin.NewClient(server.URL, "token"))).To(Succeed())
+			Expect(org.Status.DomainCount).To(Equal(2))
+			Expect(org.Status.DefaultDomainID).To(Equal("d1"))
+		})
+	})
+
+	Context("When the organization subnet changes in Pangolin", func() {
+		It("should refresh the status and re-enqueue dependents", func() {
+			ctx := context.Background()
+			subnet := "100.89.0.0/16"
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/v1/org/org1":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"org":{"orgId":"org1","name":"Org","subnet":"` + subnet + `"}}}`))
+				case "/v1/org/org1/domains":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"domains":[]}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "subnet-org-credentials", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "subnet-org",
+					Namespace:  "default",
+					Finalizers: []string{OrganizationFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+						Key:                  "apiKey",
+					},
+					OrganizationID: "org1",
+				},
+			}
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			DeferCleanup(func() {
+				org := &tunnelv1alpha1.PangolinOrganization{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "subnet-org", Namespace: "default"}, org)).To(Succeed())
+				org.Finalizers = nil
+				Expect(k8sClient.Update(ctx, org)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, org)).To(Succeed())
+			})
+
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "subnet-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "subnet-org"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &PangolinOrganizationReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+			orgKey := types.NamespacedName{Name: "subnet-org", Namespace: "default"}
+			reconcileOrg := func() *tunnelv1alpha1.PangolinOrganization {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: orgKey})
+				Expect(err).NotTo(HaveOccurred())
+				updated := &tunnelv1alpha1.PangolinOrganization{}
+				Expect(k8sClient.Get(ctx, orgKey, updated)).To(Succeed())
+				return updated
+			}
+
+			before := reconcileOrg()
+			Expect(before.Status.Subnet).To(Equal("100.89.0.0/16"))
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("changing the subnet in Pangolin")
+			subnet = "100.90.0.0/16"
+			after := reconcileOrg()
+			Expect(after.Status.Subnet).To(Equal("100.90.0.0/16"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("SubnetChanged")))
+
+			By("enqueueing the tunnels referencing the organization")
+			Expect(organizationSubnetChanged().Update(event.UpdateEvent{ObjectOld: before, ObjectNew: after})).To(BeTrue())
+			Expect(organizationSubnetChanged().Update(event.UpdateEvent{ObjectOld: after, ObjectNew: after})).To(BeFalse())
+			tunnelReconciler := &PangolinTunnelReconciler{Client: k8sClient}
+			Expect(tunnelReconciler.tunnelsForOrganization(ctx, after)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "subnet-tunnel", Namespace: "default"}}))
+		})
+	})
+
+	Context("When the organization references a CA bundle", func() {
+		It("should trust the bundle for the Pangolin API", func() {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[{"orgId":"alpha","name":"Alpha"}]}}`))
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "pangolin-ca", Namespace: "default"},
+				Data:       map[string]string{"ca.crt": string(bundle)},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, configMap)).To(Succeed()) }()
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "private-ca", Namespace: "default"},
+			}
+			_, err := pangolin.NewClient(server.URL, "token").ListOrganizations(ctx)
+			Expect(err).To(MatchError(ContainSubstring("certificate")))
+
+			timeout := int32(5)
+			org.Spec.APITimeoutSeconds = &timeout
+			org.Spec.CABundleRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "pangolin-ca"},
+				Key:                  "ca.crt",
+			}
+			opts, err := pangolinClientOptions(ctx, k8sClient, org)
+			Expect(err).NotTo(HaveOccurred())
+			orgs, err := pangolin.NewClient(server.URL, "token", opts...).ListOrganizations(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orgs).To(HaveLen(1))
+
+			org.Spec.CABundleRef.Key = "missing"
+			_, err = pangolinClientOptions(ctx, k8sClient, org)
+			Expect(err).To(MatchError(`CA bundle key "missing" not found in ConfigMap pangolin-ca`))
+		})
+	})
+
+	Context("When the API key Secret is rotated", func() {
+		It("should enqueue the organization and report a rejected key", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if req.Header.Get("Authorization") != "Bearer valid-key" {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(`{"success":false,"message":"invalid API key"}`))
+					return
+				}
+				switch req.URL.Path {
+				case "/v1/orgs":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[{"orgId":"alpha","name":"Alpha"}]}}`))
+				case "/v1/org/alpha/domains":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"domains":[]}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "rotated-key", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("valid-key")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "rotated-org",
+					Namespace:  "default",
+					Finalizers: []string{OrganizationFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "rotated-key"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			DeferCleanup(func() {
+				org.Finalizers = nil
+				Expect(k8sClient.Update(ctx, org)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, org)).To(Succeed())
+			})
+
+			reconciler := &PangolinOrganizationReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			reconciler.backoff = newErrorBackoff(0, 0, 0)
+			key := client.ObjectKeyFromObject(org)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, org)).To(Succeed())
+			Expect(org.Status.Status).To(Equal("Ready"))
+
+			By("mapping the Secret to the organizations using it")
+			Expect(reconciler.organizationsForSecret(ctx, secret)).To(ConsistOf(reconcile.Request{NamespacedName: key}))
+			other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default"}}
+			Expect(reconciler.organizationsForSecret(ctx, other)).To(BeEmpty())
+
+			By("rotating to a key the API rejects")
+			secret.Data["apiKey"] = []byte("revoked-key")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, org)).To(Succeed())
+			Expect(org.Status.Status).To(Equal("Error"))
+			cond := meta.FindStatusCondition(org.Status.Conditions, "Ready")
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Message).To(ContainSubstring("Pangolin API rejected the API key"))
+		})
+	})
+
+	Context("When Pangolin clients are cached", func() {
+		It("should reuse a client until the API key changes", func() {
+			ctx := context.Background()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "cached-key", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("first-key")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "cached-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: "https://pangolin.example.com",
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "cached-key"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			cache := newPangolinClientCache()
+			first, err := organizationClient(ctx, k8sClient, cache, org, org.Spec.APIKeyRef)
+			Expect(err).NotTo(HaveOccurred())
+			again, err := organizationClient(ctx, k8sClient, cache, org, org.Spec.APIKeyRef)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again).To(BeIdenticalTo(first))
+
+			By("rebuilding the client when the Secret is rotated")
+			secret.Data["apiKey"] = []byte("second-key")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			rotated, err := organizationClient(ctx, k8sClient, cache, org, org.Spec.APIKeyRef)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated).NotTo(BeIdenticalTo(first))
+			Expect(cache.clients).To(HaveLen(1))
+
+			By("rebuilding the client when the endpoint changes")
+			org.Spec.APIEndpoint = "https://other.example.com"
+			moved, err := organizationClient(ctx, k8sClient, cache, org, org.Spec.APIKeyRef)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(moved).NotTo(BeIdenticalTo(rotated))
+
+			By("building a new client every time without a cache")
+			uncached, err := organizationClient(ctx, k8sClient, nil, org, org.Spec.APIKeyRef)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uncached).NotTo(BeIdenticalTo(moved))
+		})
+	})
+
+	Context("When a referenced domain awaits verification", func() {
+		It("should set DomainPending and requeue until the domain is verified", func() {
+			ctx := context.Background()
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "pending-org", Namespace: "default"},
+				Status: tunnelv1alpha1.PangolinOrganizationStatus{
+					DefaultDomainID: "d1",
+					Domains: []tunnelv1alpha1.Domain{
+						{DomainID: "d1", BaseDomain: "example.com", Verified: true},
+						{DomainID: "d2", BaseDomain: "new.example.com", Tries: 2},
+						{DomainID: "d3", BaseDomain: "unused.example.com"},
+					},
+				},
+			}
+
+			controllerReconciler := &PangolinOrganizationReconciler{Client: k8sClient}
+			Expect(controllerReconciler.reconcileDomainVerification(ctx, org)).To(BeZero())
+			Expect(meta.FindStatusCondition(org.Status.Conditions, DomainPendingCondition)).To(BeNil())
+
+			By("referencing the unverified domain from a resource")
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "pending-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "pending-org"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "pending-resource", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef:  tunnelv1alpha1.LocalObjectReference{Name: "pending-tunnel"},
+					Name:       "app",
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app", DomainName: "new.example.com"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			Expect(controllerReconciler.reconcileDomainVerification(ctx, org)).To(Equal(2 * time.Minute))
+			condition := meta.FindStatusCondition(org.Status.Conditions, DomainPendingCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Message).To(Equal("Domains awaiting verification: new.example.com (2 tries)"))
+
+			By("clearing the condition once Pangolin verified the domain")
+			org.Status.Domains[1].Verified = true
+			Expect(controllerReconciler.reconcileDomainVerification(ctx, org)).To(BeZero())
+			Expect(meta.FindStatusCondition(org.Status.Conditions, DomainPendingCondition)).To(BeNil())
+		})
+
+		It("should keep the organization Waiting but usable until the domain is verified", func() {
+			ctx := context.Background()
+			verified := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/v1/org/org1":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"org":{"orgId":"org1","name":"Org"}}}`))
+				case "/v1/org/org1/domains":
+					_, _ = fmt.Fprintf(w, `{"success":true,"data":{"domains":[`+
+						`{"domainId":"d1","baseDomain":"new.example.com","verified":%t,"tries":1}]}}`, verified)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "waiting-org-credentials", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "waiting-org",
+					Namespace:  "default",
+					Finalizers: []string{OrganizationFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+						Key:                  "apiKey",
+					},
+					OrganizationID: "org1",
+					Defaults:       &tunnelv1alpha1.OrganizationDefaults{DefaultDomain: "new.example.com"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			orgKey := types.NamespacedName{Name: "waiting-org", Namespace: "default"}
+			DeferCleanup(func() {
+				org := &tunnelv1alpha1.PangolinOrganization{}
+				Expect(k8sClient.Get(ctx, orgKey, org)).To(Succeed())
+				org.Finalizers = nil
+				Expect(k8sClient.Update(ctx, org)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, org)).To(Succeed())
+			})
+
+			controllerReconciler := &PangolinOrganizationReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: orgKey})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+			updated := &tunnelv1alpha1.PangolinOrganization{}
+			Expect(k8sClient.Get(ctx, orgKey, updated)).To(Succeed())
+			Expect(updated.Status.Status).To(Equal("Waiting"))
+			ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("AwaitingVerification"))
+			Expect(ready.Message).To(Equal("Domains awaiting verification: new.example.com (1 tries)"))
+			Expect(organizationUsable(updated)).To(BeTrue())
+
+			By("becoming Ready once Pangolin verified the domain")
+			verified = true
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: orgKey})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, orgKey, updated)).To(Succeed())
+			Expect(updated.Status.Status).To(Equal("Ready"))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "Ready")).To(BeTrue())
+		})
+	})
+
+	Context("When the organization references API headers", func() {
+		It("should send the headers with every request and enqueue on their rotation", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if req.Header.Get("CF-Access-Client-Id") != "client-id" || req.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[{"orgId":"alpha","name":"Alpha"}]}}`))
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "pangolin-headers", Namespace: "default"},
+				Data: map[string][]byte{
+					"CF-Access-Client-Id": []byte("client-id"),
+					"Authorization":       []byte("Basic ignored"),
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "header-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "api-key"},
+						Key:                  "apiKey",
+					},
+					APIHeadersSecretRef: &corev1.LocalObjectReference{Name: "pangolin-headers"},
+				},
+			}
+			opts, err := pangolinClientOptions(ctx, k8sClient, org)
+			Expect(err).NotTo(HaveOccurred())
+			orgs, err := pangolin.NewClient(server.URL, "token", opts...).ListOrganizations(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orgs).To(HaveLen(1))
+
+			By("mapping the Secret to the organizations using it")
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, org)
+			reconciler := &PangolinOrganizationReconciler{Client: k8sClient}
+			Expect(reconciler.organizationsForSecret(ctx, secret)).To(ConsistOf(
+				reconcile.Request{NamespacedName: client.ObjectKeyFromObject(org)}))
+
+			By("rebuilding the cached client when the headers change")
+			config := clientConfig{headers: map[string]string{"CF-Access-Client-Id": "client-id"}}
+			rotated := clientConfig{headers: map[string]string{"CF-Access-Client-Id": "other-id"}}
+			Expect(clientFingerprint(org, []byte("token"), config)).NotTo(Equal(clientFingerprint(org, []byte("token"), rotated)))
+		})
+	})
+
+	Context("When the API reports its rate limit", func() {
+		It("should export it as metrics and flag a rate limit below the threshold", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-RateLimit-Limit", "100")
+				w.Header().Set("X-RateLimit-Remaining", "5")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[]}}`))
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			keyRef := corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "api-key"},
+				Key:                  "apiKey",
+			}
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "rate-limited-org", Namespace: "default", Generation: 1},
+				Spec:       tunnelv1alpha1.PangolinOrganizationSpec{APIEndpoint: server.URL, APIKeyRef: keyRef},
+			}
+			apiClient, err := newOrganizationClient(org, keyRef, []byte("token"), clientConfig{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = apiClient.ListOrganizations(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			labels := prometheus.Labels{"namespace": "default", "organization": "rate-limited-org", "secret": "api-key"}
+			Expect(testutil.ToFloat64(apiRateLimitRemaining.With(labels))).To(Equal(5.0))
+			Expect(testutil.ToFloat64(apiRateLimitLimit.With(labels))).To(Equal(100.0))
+
+			By("leaving the condition unset without a threshold")
+			reconcileRateLimit(org, apiClient)
+			Expect(meta.FindStatusCondition(org.Status.Conditions, RateLimitLowCondition)).To(BeNil())
+
+			By("setting the condition below the threshold")
+			threshold := int32(10)
+			org.Spec.RateLimitWarningThreshold = &threshold
+			reconcileRateLimit(org, apiClient)
+			condition := meta.FindStatusCondition(org.Status.Conditions, RateLimitLowCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Message).To(Equal("Pangolin API reports 5 of 100 requests remaining"))
+
+			By("clearing the condition at or above the threshold")
+			threshold = 5
+			reconcileRateLimit(org, apiClient)
+			Expect(meta.FindStatusCondition(org.Status.Conditions, RateLimitLowCondition)).To(BeNil())
+
+			By("dropping the metrics of a deleted organization")
+			deleteRateLimitMetrics(org)
+			Expect(testutil.CollectAndCount(apiRateLimitRemaining, "pangolin_api_rate_limit_remaining")).To(BeZero())
+		})
+	})
+
+	Context("When the Pangolin API hangs", func() {
+		It("should cancel the reconcile after the reconcile timeout", func() {
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				select {
+				case <-release:
+				case <-req.Context().Done():
+				}
+			}))
+			defer server.Close()
+			defer close(release)
+
+			apiClient := pangolin.NewClient(server.URL, "token", pangolin.WithRetryConfig(pangolin.RetryConfig{MaxAttempts: 1}))
+			reconciler := withReconcileTimeout(reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				_, err := apiClient.ListOrganizations(ctx)
+				return reconcile.Result{}, err
+			}), 100*time.Millisecond)
+
+			start := time.Now()
+			_, err := reconciler.Reconcile(context.Background(),
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "hung"}})
+			Expect(err).To(MatchError(ContainSubstring("reconcile of default/hung timed out after 100ms")))
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		})
+
+		It("should persist the Error status of a timed out reconcile", func() {
+			var hang atomic.Bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if hang.Load() {
+					<-req.Context().Done()
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/v1/orgs":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[{"orgId":"alpha","name":"Alpha"}]}}`))
+				case "/v1/org/alpha/domains":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"domains":[]}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "hung-key", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "hung-org",
+					Namespace:  "default",
+					Finalizers: []string{OrganizationFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "hung-key"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			DeferCleanup(func() {
+				org.Finalizers = nil
+				Expect(k8sClient.Update(ctx, org)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, org)).To(Succeed())
+			})
+
+			inner := &PangolinOrganizationReconciler{Client: contextClient{k8sClient}, Scheme: k8sClient.Scheme()}
+			inner.backoff = newErrorBackoff(0, 0, 0)
+			reconciler := withReconcileTimeout(inner, 200*time.Millisecond)
+			key := client.ObjectKeyFromObject(org)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, org)).To(Succeed())
+			Expect(org.Status.Status).To(Equal("Ready"))
+
+			By("recording the timeout once the API hangs")
+			hang.Store(true)
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(MatchError(ContainSubstring("timed out")))
+			Expect(k8sClient.Get(ctx, key, org)).To(Succeed())
+			Expect(org.Status.Status).To(Equal("Error"))
+			Expect(meta.FindStatusCondition(org.Status.Conditions, "Ready").Message).To(
+				ContainSubstring("Reconcile timed out waiting for the Pangolin API"))
+		})
+
+		It("should leave reconciles unbounded without a timeout", func() {
+			inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{}, nil
+			})
+			_, isWrapped := withReconcileTimeout(inner, 0).(timeoutReconciler)
+			Expect(isWrapped).To(BeFalse())
+		})
+	})
+
+	Context("When validating the API endpoint", func() {
+		It("should normalize valid endpoints and reject malformed ones", func() {
+			tests := []struct {
+				endpoint, basePath string
+				want, reason       string
+			}{
+				{endpoint: "https://pangolin.example.com", want: "https://pangolin.example.com"},
+				{endpoint: " https://pangolin.example.com// ", want: "https://pangolin.example.com"},
+				{endpoint: "http://pangolin:3003/api/", basePath: "/v2", want: "http://pangolin:3003/api"},
+				{endpoint: "https://pangolin.example.com/v1", basePath: "/", want: "https://pangolin.example.com/v1"},
+				{endpoint: "pangolin.example.com", reason: "UnsupportedScheme"},
+				{endpoint: "ftp://pangolin.example.com", reason: "UnsupportedScheme"},
+				{endpoint: "https://", reason: "MissingHost"},
+				{endpoint: "https://pangolin.example.com/v1/", reason: "IncludesBasePath"},
+				{endpoint: "https://pangolin.example.com/api/v2", basePath: "v2", reason: "IncludesBasePath"},
+				{endpoint: "https://pangolin.example.com?key=1", reason: "InvalidURL"},
+				{endpoint: "pangolin.example.com:443", reason: "UnsupportedScheme"},
+				{endpoint: "https://pangolin.example.com/%zz", reason: "InvalidURL"},
+			}
+			for _, tt := range tests {
+				org := &tunnelv1alpha1.PangolinOrganization{Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: tt.endpoint, APIBasePath: tt.basePath,
+				}}
+				err := reconcileAPIEndpoint(org)
+				condition := meta.FindStatusCondition(org.Status.Conditions, InvalidEndpointCondition)
+				if tt.reason == "" {
+					Expect(err).NotTo(HaveOccurred(), tt.endpoint)
+					Expect(org.Status.APIEndpoint).To(Equal(tt.want), tt.endpoint)
+					Expect(condition).To(BeNil(), tt.endpoint)
+					continue
+				}
+				Expect(err).To(HaveOccurred(), tt.endpoint)
+				Expect(condition).NotTo(BeNil(), tt.endpoint)
+				Expect(condition.Reason).To(Equal(tt.reason), tt.endpoint)
+				Expect(condition.Message).To(Equal(err.Error()), tt.endpoint)
+			}
+		})
+
+		It("should report an invalid endpoint without calling the API", func() {
+			ctx := context.Background()
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "bad-endpoint-org",
+					Namespace:  "default",
+					Finalizers: []string{OrganizationFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: "https://pangolin.example.com/v1",
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "missing-api-key"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			DeferCleanup(func() {
+				org.Finalizers = nil
+				Expect(k8sClient.Update(ctx, org)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, org)).To(Succeed())
+			})
+
+			reconciler := &PangolinOrganizationReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			reconciler.backoff = newErrorBackoff(0, 0, 0)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(org)})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(org), org)).To(Succeed())
+			Expect(org.Status.Status).To(Equal("Error"))
+			Expect(meta.IsStatusConditionTrue(org.Status.Conditions, InvalidEndpointCondition)).To(BeTrue())
+			Expect(meta.FindStatusCondition(org.Status.Conditions, "Ready").Message).To(ContainSubstring(
+				"ends with the API base path /v1"))
+		})
+	})
+
+	Context("When the organization has a fallback API key", func() {
+		It("should switch to the fallback key once the primary key is rejected", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if req.Header.Get("Authorization") != "Bearer new-key" {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(`{"success":false,"message":"Invalid API key"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[{"orgId":"alpha","name":"Alpha"}]}}`))
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			for name, key := range map[string]string{"old-api-key": "old-key", "new-api-key": "new-key"} {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Data:       map[string][]byte{"apiKey": []byte(key)},
+				}
+				Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, secret)
+			}
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "fallback-key-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "old-api-key"},
+						Key:                  "apiKey",
+					},
+					APIKeyFallbackRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "new-api-key"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			apiClient, err := organizationClient(ctx, k8sClient, newPangolinClientCache(), org, org.Spec.APIKeyRef)
+			Expect(err).NotTo(HaveOccurred())
+			orgs, err := apiClient.ListOrganizations(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orgs).To(HaveLen(1))
+
+			By("reporting the fallback key with a warning event")
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &PangolinOrganizationReconciler{Client: k8sClient, Recorder: recorder}
+			reconciler.reconcileActiveAPIKey(ctx, org, apiClient)
+			Expect(org.Status.ActiveAPIKey).To(Equal("Fallback"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("APIKeyFallback")))
+			reconciler.reconcileActiveAPIKey(ctx, org, apiClient)
+			Expect(recorder.Events).NotTo(Receive())
+
+			By("mapping the fallback key Secret to the organization")
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, org)
+			Expect(reconciler.organizationsForSecret(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "new-api-key", Namespace: "default"},
+			})).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(org)}))
+
+			By("leaving clients of a separate key without the fallback")
+			siteClient, err := organizationClient(ctx, k8sClient, nil, org, corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "new-api-key"},
+				Key:                  "apiKey",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = siteClient.ListOrganizations(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(siteClient.UsingFallbackAPIKey()).To(BeFalse())
+		})
+	})
 })
+
+// contextClient fails status writes once their context is done, like the API
+// server does
+type contextClient struct {
+	client.Client
+}
+
+func (c contextClient) Status() client.SubResourceWriter {
+	return contextStatusWriter{c.Client.Status()}
+}
+
+type contextStatusWriter struct {
+	client.SubResourceWriter
+}
+
+func (w contextStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
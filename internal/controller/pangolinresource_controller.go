@@ -3,20 +3,27 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
 	"github.com/bovf/pangolin-operator/pkg/pangolin"
@@ -24,11 +31,52 @@ import (
 
 const ResourceFinalizerName = "resource.pangolin.io/finalizer"
 
+// DomainRemovedCondition is set on HTTP resources whose resolved domain was
+// removed from the organization
+const DomainRemovedCondition = "DomainRemoved"
+
+// ConfigConflictCondition is set on resources binding to an existing Pangolin
+// resource by spec.resourceId, spec.bindByName or spec.bindBySubdomain that
+// also carry create configuration, which is ignored while bound
+const ConfigConflictCondition = "ConfigConflict"
+
+// maxRecentResourceEvents is the number of Pangolin events kept in status
+const maxRecentResourceEvents = 5
+
 // PangolinResourceReconciler reconciles a PangolinResource object
 type PangolinResourceReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// Class restricts this instance to objects with a matching pangolin.io/class
+	// label or annotation. Empty manages only unclassed objects.
+	Class string
+
+	// AllowCrossNamespaceOrg allows organizationRef to point to an organization in another namespace.
+	AllowCrossNamespaceOrg bool
+
+	// ErrorBackoffCap is the longest wait between retries of an object in Error.
+	// Zero uses DefaultErrorBackoffCap.
+	ErrorBackoffCap time.Duration
+
+	// ErrorRequeueInterval is the wait before the first retry of an object in
+	// Error. Zero uses DefaultErrorRequeueInterval.
+	ErrorRequeueInterval time.Duration
+
+	// ResyncInterval, when set, re-reconciles Ready objects that often to correct
+	// drift made outside the operator.
+	ResyncInterval time.Duration
+
+	// ReconcileTimeout bounds each reconcile, cancelling its Pangolin API
+	// requests when exceeded. Zero leaves reconciles unbounded.
+	ReconcileTimeout time.Duration
+
+	backoff *errorBackoff
+	clients *pangolinClientCache
+
+	// FullSync, when set, periodically enqueues every object for a full reconcile.
+	FullSync *FullSync
 }
 
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolinresources,verbs=get;list;watch;create;update;patch;delete
@@ -78,8 +126,17 @@ func (r *PangolinResourceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
-	// Handle deletion if resource is being deleted
+	// Ignore objects managed by another operator instance
+	if !matchesClass(resource, r.Class) {
+		return ctrl.Result{}, nil
+	}
+
+	// Handle deletion if resource is being deleted. While paused the finalizer
+	// is kept, so the Pangolin resource is only deleted once resumed.
 	if resource.DeletionTimestamp != nil {
+		if msg := pausedMessage(resource, r.organizationForResource(ctx, resource)); msg != "" {
+			return markPaused(ctx, r.Client, resource, &resource.Status.Conditions, msg)
+		}
 		return r.handleResourceDeletion(ctx, resource)
 	}
 
@@ -97,7 +154,7 @@ func (r *PangolinResourceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		t, err := r.getTunnelForResource(ctx, resource)
 		if err != nil {
 			logger.Error(err, "Failed to get referenced tunnel")
-			return r.updateResourceStatus(ctx, resource, "Error", err.Error())
+			return r.updateResourceError(ctx, resource, err)
 		}
 		tunnel = t
 
@@ -111,7 +168,7 @@ func (r *PangolinResourceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		o, err := r.getOrganizationForTunnel(ctx, tunnel)
 		if err != nil {
 			logger.Error(err, "Failed to get referenced organization")
-			return r.updateResourceStatus(ctx, resource, "Error", err.Error())
+			return r.updateResourceError(ctx, resource, err)
 		}
 		org = o
 	} else {
@@ -119,17 +176,27 @@ func (r *PangolinResourceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return r.updateResourceStatus(ctx, resource, "Error", "No tunnel reference provided")
 	}
 
+	// Skip API writes while paused directly or through the organization
+	if msg := pausedMessage(resource, org); msg != "" {
+		return markPaused(ctx, r.Client, resource, &resource.Status.Conditions, msg)
+	}
+
 	// Wait for organization to be ready
-	if org.Status.Status != "Ready" {
+	if !organizationUsable(org) {
 		logger.Info("Organization not ready yet, waiting", "organization", org.Name)
 		return r.updateResourceStatus(ctx, resource, "Waiting", "Waiting for organization to be ready")
 	}
 
+	// Report what would be applied instead of calling the API
+	if isDryRun(resource) {
+		return r.reportResourcePlan(ctx, resource, tunnel, org)
+	}
+
 	// Create Pangolin API client using organization credentials
 	apiClient, err := r.createPangolinClientFromOrganization(ctx, org)
 	if err != nil {
 		logger.Error(err, "Failed to create Pangolin API client")
-		return r.updateResourceStatus(ctx, resource, "Error", err.Error())
+		return r.updateResourceError(ctx, resource, err)
 	}
 
 	orgID := org.Status.OrganizationID
@@ -137,11 +204,17 @@ func (r *PangolinResourceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return r.updateResourceStatus(ctx, resource, "Error", "Organization missing organization ID")
 	}
 
+	// Track what this reconcile changes in Pangolin, escalated by each step below
+	resource.Status.LastAction = tunnelv1alpha1.ReconcileActionNoOp
+
+	// Flag create configuration that is ignored because the resource is bound
+	setConfigConflictCondition(resource)
+
 	// Resolve site ID from tunnel or explicit site reference
 	siteID, err := r.resolveSiteForResource(ctx, resource, tunnel)
 	if err != nil {
 		logger.Error(err, "Failed to resolve site for resource")
-		return r.updateResourceStatus(ctx, resource, "Error", err.Error())
+		return r.updateResourceError(ctx, resource, err)
 	}
 
 	// Resolve domain for HTTP resources
@@ -149,45 +222,87 @@ func (r *PangolinResourceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	// 1. Explicit domainId in httpConfig
 	// 2. Domain name in httpConfig (resolved to ID)
 	// 3. Organization default domain
-	if resource.Spec.Protocol == "http" && resource.Spec.HTTPConfig != nil {
-		domainID, fullDomain, err := r.resolveDomainForResource(ctx, resource, org)
-		if err != nil {
+	// A domain removed from the organization is re-resolved to the next match
+	if resolvesDomain(resource) {
+		if err := r.reconcileResourceDomain(ctx, apiClient, resource, org); err != nil {
 			logger.Error(err, "Failed to resolve domain")
-			return r.updateResourceStatus(ctx, resource, "Error", err.Error())
+			return r.updateResourceError(ctx, resource, err)
+		}
+
+		// Pangolin rejects resources on a domain it has not verified yet
+		if msg := domainPendingMessage(resource, org); msg != "" {
+			logger.Info("Domain not verified yet, waiting", "domainID", resource.Status.ResolvedDomainID)
+			return r.updateResourceStatus(ctx, resource, "Waiting", msg)
 		}
-		resource.Status.ResolvedDomainID = domainID
-		resource.Status.FullDomain = fullDomain
 	}
 
 	// Create or bind to existing Pangolin resource
 	pRes, err := r.reconcilePangolinResource(ctx, apiClient, orgID, siteID, resource, org)
+	if err == errMigrationStarted {
+		// Record the resource being replaced before its replacement is created
+		logger.Info("Recreating resource on its new subdomain or domain",
+			"resourceID", resource.Status.MigratingFrom.ResourceID)
+		if _, err := r.updateResourceStatus(ctx, resource, "Migrating",
+			fmt.Sprintf("Recreating resource %s on its new subdomain or domain",
+				resource.Status.MigratingFrom.ResourceID)); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
 	if err != nil {
 		logger.Error(err, "Failed to reconcile Pangolin resource")
-		return r.updateResourceStatus(ctx, resource, "Error", err.Error())
+		return r.updateResourceError(ctx, resource, err)
 	}
 
 	resourceID := pRes.EffectiveID()
 	resource.Status.ResourceID = resourceID
 	logger.Info("Resource created", "resourceID", resourceID)
 
+	// Enable or disable the resource by its schedule, before its targets follow
+	if err := r.reconcileResourceSchedule(ctx, apiClient, resourceID, resource, time.Now()); err != nil {
+		logger.Error(err, "Failed to apply resource schedule")
+		return r.updateResourceError(ctx, resource, err)
+	}
+
+	if err := r.reconcileResourceAuth(ctx, apiClient, resourceID, resource); err != nil {
+		logger.Error(err, "Failed to reconcile resource authentication")
+		return r.updateResourceError(ctx, resource, err)
+	}
+
+	// Apply access rules before targets, so a restricted resource never serves unrestricted
+	if err := r.reconcileAccessRules(ctx, apiClient, resourceID, resource); err != nil {
+		logger.Error(err, "Failed to reconcile access rules")
+		return r.updateResourceError(ctx, resource, err)
+	}
+
+	// Expand spec targets and canary split into the desired target list
+	desiredTargets, err := desiredTargetsForResource(resource)
+	if err != nil {
+		return r.updateResourceError(ctx, resource, err)
+	}
+	applyDefaultTargetMethods(desiredTargets, resource.Spec.Protocol, org)
+	resolveAutoTargetMethods(ctx, probeTargetMethod, resource, desiredTargets)
+
 	// Reconcile targets if target is specified in spec
 	// This ensures the target from spec exists and tracks all targets
-	if len(resource.Spec.Targets) > 0 {
-		logger.Info("Reconciling targets for resource", "resourceID", resourceID, "targetCount", len(resource.Spec.Targets))
+	if len(desiredTargets) > 0 {
+		logger.Info("Reconciling targets for resource", "resourceID", resourceID, "targetCount", len(desiredTargets))
 
-		allTargetIDs, err := r.reconcilePangolinTarget(ctx, apiClient, resourceID, resource, siteID)
+		allTargetIDs, err := r.reconcilePangolinTarget(ctx, apiClient, resourceID, resource, desiredTargets, siteID)
 		if err != nil {
 			logger.Error(err, "Failed to reconcile Pangolin target")
-			return r.updateResourceStatus(ctx, resource, "Error", err.Error())
+			return r.updateResourceError(ctx, resource, err)
 		}
 
 		logger.Info("Targets reconciled", "totalTargets", len(allTargetIDs), "targetIDs", allTargetIDs)
 
-		// Re-fetch resource to avoid optimistic locking conflict
+		// Re-fetch resource to avoid optimistic locking conflict, keeping the status computed so far
+		status := resource.Status.DeepCopy()
 		if err := r.Get(ctx, req.NamespacedName, resource); err != nil {
 			logger.Error(err, "Failed to re-fetch resource before status update")
 			return ctrl.Result{}, err
 		}
+		resource.Status = *status
 
 		resource.Status.TargetIDs = allTargetIDs
 		resource.Status.TargetCount = len(allTargetIDs)
@@ -215,30 +330,65 @@ func (r *PangolinResourceReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
+	// Delete the resource replaced by a recreate now that its replacement serves
+	if err := r.finishResourceMigration(ctx, apiClient, resource); err != nil {
+		logger.Error(err, "Failed to delete replaced resource")
+		return r.updateResourceError(ctx, resource, err)
+	}
+
+	// Surface server-side events (e.g. certificate errors) in status and as Kubernetes events
+	r.reconcileResourceEvents(ctx, apiClient, resourceID, resource)
+
 	// Generate full URL for HTTP resources
-	if resource.Spec.Protocol == "http" && resource.Status.FullDomain != "" {
-		resource.Status.URL = fmt.Sprintf("https://%s", resource.Status.FullDomain)
+	if (resource.Spec.Protocol == "http" || pRes.HTTP) && resource.Status.FullDomain != "" {
+		resource.Status.URL = httpResourceURL(resource.Status.FullDomain, resource.Spec.HTTPConfig)
 		logger.Info("Resource URL set", "url", resource.Status.URL)
 	}
 
-	// Update final status to Ready
-	return r.updateResourceStatus(ctx, resource, "Ready", "Resource and target configured successfully")
+	// Generate proxy endpoint for TCP/UDP resources
+	if resource.Spec.Protocol != "http" && resource.Spec.ProxyConfig != nil {
+		resource.Status.ProxyEndpoint = r.resolveProxyEndpoint(ctx, apiClient, pRes, resource, tunnel)
+	}
+
+	// Ready only tells the spec was applied, runtime health is reported separately
+	setResourceDegradedCondition(resource, tunnel, desiredTargets)
+
+	// Update final status to Ready, or Suspended while spec.suspend keeps the targets disabled,
+	// or Waiting while spec.waitForHealthy waits for targets to report healthy
+	recordReconcileAction("pangolinresource", resource.Status.LastAction)
+	status, message := "Ready", "Resource and target configured successfully"
+	unhealthy := unhealthyTargets(resource, desiredTargets)
+	if resourceSuspended(resource) {
+		status, message = "Suspended", "Resource suspended, its targets are disabled"
+	} else if len(unhealthy) > 0 {
+		status = "Waiting"
+		message = fmt.Sprintf("Waiting for targets to report healthy: %s", strings.Join(unhealthy, ", "))
+	}
+	r.recordSuspendTransition(resource, status)
+	result, err := r.updateResourceStatus(ctx, resource, status, message)
+	if status == "Waiting" && err == nil {
+		result.RequeueAfter = targetHealthPoll
+	}
+	if requeueAfter := scheduleRequeueAfter(resource, time.Now()); err == nil && requeueAfter > 0 &&
+		(result.RequeueAfter == 0 || requeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = requeueAfter
+	}
+	return result, err
 }
 
 // getTunnelForResource retrieves the PangolinTunnel referenced by the resource.
-// The tunnel provides the site context (site ID) for target creation.
+// The tunnel provides the site context (site ID) for target creation. Resources
+// have always followed tunnelRef.namespace, so unlike bindings they may reference
+// a tunnel in another namespace without AllowCrossNamespaceTunnel.
 func (r *PangolinResourceReconciler) getTunnelForResource(ctx context.Context, resource *tunnelv1alpha1.PangolinResource) (*tunnelv1alpha1.PangolinTunnel, error) {
-	tunnel := &tunnelv1alpha1.PangolinTunnel{}
-	// Use tunnel namespace from ref, fallback to resource namespace
-	tunnelNamespace := resource.Spec.TunnelRef.Namespace
-	if tunnelNamespace == "" {
-		tunnelNamespace = resource.Namespace
+	key, err := tunnelKey(resource.Spec.TunnelRef, resource.Namespace, true)
+	if err != nil {
+		return nil, err
 	}
-	if err := r.Get(ctx, types.NamespacedName{
-		Namespace: tunnelNamespace,
-		Name:      resource.Spec.TunnelRef.Name,
-	}, tunnel); err != nil {
-		return nil, fmt.Errorf("failed to get tunnel %s: %w", resource.Spec.TunnelRef.Name, err)
+
+	tunnel := &tunnelv1alpha1.PangolinTunnel{}
+	if err := r.Get(ctx, key, tunnel); err != nil {
+		return nil, fmt.Errorf("failed to get tunnel %s: %w", key, err)
 	}
 	return tunnel, nil
 }
@@ -246,42 +396,55 @@ func (r *PangolinResourceReconciler) getTunnelForResource(ctx context.Context, r
 // getOrganizationForTunnel retrieves the PangolinOrganization referenced by the tunnel.
 // The organization provides API credentials and domain information.
 func (r *PangolinResourceReconciler) getOrganizationForTunnel(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel) (*tunnelv1alpha1.PangolinOrganization, error) {
+	key, err := organizationKey(tunnel.Spec.OrganizationRef, tunnel.Namespace, r.AllowCrossNamespaceOrg)
+	if err != nil {
+		return nil, err
+	}
+
 	org := &tunnelv1alpha1.PangolinOrganization{}
-	if err := r.Get(ctx, types.NamespacedName{
-		Namespace: tunnel.Namespace,
-		Name:      tunnel.Spec.OrganizationRef.Name,
-	}, org); err != nil {
-		return nil, fmt.Errorf("failed to get organization %s: %w", tunnel.Spec.OrganizationRef.Name, err)
+	if err := r.Get(ctx, key, org); err != nil {
+		return nil, fmt.Errorf("failed to get organization %s: %w", key, err)
 	}
 	return org, nil
 }
 
+// organizationForResource retrieves the organization through the resource's
+// tunnel, or returns nil if either cannot be read.
+func (r *PangolinResourceReconciler) organizationForResource(ctx context.Context, resource *tunnelv1alpha1.PangolinResource) *tunnelv1alpha1.PangolinOrganization {
+	tunnel, err := r.getTunnelForResource(ctx, resource)
+	if err != nil {
+		return nil
+	}
+	org, err := r.getOrganizationForTunnel(ctx, tunnel)
+	if err != nil {
+		return nil
+	}
+	return org
+}
+
 // createPangolinClientFromOrganization creates a Pangolin API client using credentials
 // from the referenced organization. The API key is retrieved from the Kubernetes secret
-// specified in the organization's apiKeyRef.
+// specified in the organization's resourceAPIKeyRef, falling back to apiKeyRef.
 func (r *PangolinResourceReconciler) createPangolinClientFromOrganization(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization) (*pangolin.Client, error) {
-	secret := &corev1.Secret{}
-	secretKey := types.NamespacedName{Namespace: org.Namespace, Name: org.Spec.APIKeyRef.Name}
-	if err := r.Get(ctx, secretKey, secret); err != nil {
-		return nil, fmt.Errorf("failed to get API key secret: %w", err)
+	keyRef := org.Spec.APIKeyRef
+	if org.Spec.ResourceAPIKeyRef != nil {
+		keyRef = *org.Spec.ResourceAPIKeyRef
 	}
-	apiKeyBytes, ok := secret.Data[org.Spec.APIKeyRef.Key]
-	if !ok {
-		return nil, fmt.Errorf("API key not found in secret")
-	}
-	return pangolin.NewClient(org.Spec.APIEndpoint, string(apiKeyBytes)), nil
+	return organizationClient(ctx, r.Client, r.clients, org, keyRef)
 }
 
 // reconcilePangolinResource creates or binds to a Pangolin resource.
 //
 // Binding vs Creating:
-//   - If spec.resourceId is set: Bind to existing resource
-//   - If status.resourceId is set: Resource already created (idempotent)
+//   - If spec.resourceId, spec.bindByName or spec.bindBySubdomain is set: Bind
+//     to the existing resource, see bindPangolinResource
+//   - If status.resourceId is set: Resource already created, spec changes are
+//     applied in place (see reconcileResourceDrift)
 //   - Otherwise: Create new resource
 //
-// The function handles both HTTP and TCP resource types:
+// The function handles HTTP, TCP and UDP resource types:
 //   - HTTP: Requires domain resolution and subdomain
-//   - TCP: Requires proxy configuration
+//   - TCP/UDP: Requires proxy configuration, the protocol is passed through
 func (r *PangolinResourceReconciler) reconcilePangolinResource(
 	ctx context.Context,
 	api *pangolin.Client,
@@ -291,16 +454,22 @@ func (r *PangolinResourceReconciler) reconcilePangolinResource(
 ) (*pangolin.Resource, error) {
 	logger := log.FromContext(ctx)
 
-	// If resourceId is specified in spec, bind to existing resource
-	if resource.Spec.ResourceID != "" {
-		resource.Status.BindingMode = "Bound"
-		return &pangolin.Resource{ID: resource.Spec.ResourceID, Name: resource.Spec.Name}, nil
+	// If the spec names an existing resource, bind to it
+	if resource.Spec.BindField() != "" {
+		return r.bindPangolinResource(ctx, api, orgID, resource)
 	}
 
-	// If resourceId already exists in status, resource is already created
+	// If resourceId already exists in status, resource is already created and
+	// only needs to follow spec changes. A resource deleted outside the operator
+	// is created again.
 	if resource.Status.ResourceID != "" {
-		resource.Status.BindingMode = "Created"
-		return &pangolin.Resource{ID: resource.Status.ResourceID, Name: resource.Spec.Name}, nil
+		if resource.Status.BindingMode == "" {
+			resource.Status.BindingMode = "Created"
+		}
+		pRes, err := r.reconcileResourceDrift(ctx, api, orgID, resource)
+		if err != errResourceDeleted {
+			return pRes, err
+		}
 	}
 
 	// Build resource creation spec based on protocol
@@ -322,66 +491,111 @@ func (r *PangolinResourceReconciler) reconcilePangolinResource(
 			DomainID:    domainID,
 			SSO:         resource.Spec.HTTPConfig.SSO,
 			BlockAccess: resource.Spec.HTTPConfig.BlockAccess,
+			ProxyPort:   resource.Spec.HTTPConfig.Port,
+			Tags:        resource.Spec.Tags,
 		}
-	} else if resource.Spec.ProxyConfig != nil {
+	} else if resource.Spec.Protocol != "http" && resource.Spec.ProxyConfig != nil {
 		// TCP/UDP resource with proxy configuration
 		resSpec = pangolin.ResourceCreateSpec{
 			Name:        resource.Spec.Name,
 			HTTP:        false,
 			Protocol:    resource.Spec.Protocol,
 			ProxyPort:   resource.Spec.ProxyConfig.ProxyPort,
-			EnableProxy: *resource.Spec.ProxyConfig.EnableProxy,
+			EnableProxy: proxyEnabled(resource.Spec.ProxyConfig),
+			Tags:        resource.Spec.Tags,
 		}
 	} else {
 		return nil, fmt.Errorf("invalid resource configuration")
 	}
 
-	logger.Info("Creating Pangolin resource", "orgID", orgID, "siteID", siteID, "resourceSpec", resSpec)
+	// Fail fast on a subdomain another PangolinResource already holds
+	if resSpec.HTTP {
+		if err := r.checkSubdomainConflict(ctx, api, orgID, resource, resSpec.Subdomain, resSpec.DomainID); err != nil {
+			return nil, err
+		}
+	}
 
-	// Create resource via Pangolin API
-	pRes, err := api.CreateResource(ctx, orgID, siteID, resSpec)
+	// Adopt a resource matching the spec instead of creating a duplicate, e.g.
+	// when the status was lost. Listing failures fall through to creation.
+	pRes, err := findAdoptableResource(ctx, api, orgID, resSpec)
 	if err != nil {
-		// Handle 409 Conflict - resource already exists
-		if strings.Contains(err.Error(), "409") || strings.Contains(err.Error(), "already exists") {
-			logger.Info("Resource already exists in Pangolin, attempting to find and bind")
-
-			var existingRes *pangolin.Resource
-			var findErr error
-
-			// Try to find existing resource by subdomain + domainID first
-			if resource.Spec.HTTPConfig != nil {
-				existingRes, findErr = api.FindResourceBySubdomain(ctx, orgID,
-					resource.Spec.HTTPConfig.Subdomain,
-					resource.Status.ResolvedDomainID)
-				if findErr != nil {
-					logger.Error(findErr, "Failed to find existing resource by subdomain")
+		logger.Error(err, "Failed to list existing resources")
+	}
+	if pRes != nil {
+		logger.Info("Found existing resource matching the spec, adopting it instead of creating a duplicate",
+			"resourceID", pRes.EffectiveID(), "name", pRes.Name, "subdomain", pRes.Subdomain)
+		// A replacement created by an interrupted recreate stays owned by the operator
+		if resource.Status.MigratingFrom == nil {
+			resource.Status.BindingMode = "Adopted"
+		}
+		escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionAdopted)
+		recordAdoption(r.Recorder, resource, "resource", pRes.EffectiveID())
+	} else {
+		logger.Info("Creating Pangolin resource", "orgID", orgID, "siteID", siteID, "resourceSpec", resSpec)
+
+		// Create resource via Pangolin API
+		pRes, err = api.CreateResource(ctx, orgID, siteID, resSpec)
+		if err != nil {
+			// Handle 409 Conflict - resource already exists
+			if pangolin.IsConflict(err) || strings.Contains(err.Error(), "already exists") {
+				logger.Info("Resource already exists in Pangolin, attempting to find and bind")
+
+				var existingRes *pangolin.Resource
+				var findErr error
+
+				// Try to find existing resource by subdomain + domainID first
+				if resource.Spec.HTTPConfig != nil {
+					existingRes, findErr = api.FindResourceBySubdomain(ctx, orgID,
+						resource.Spec.HTTPConfig.Subdomain,
+						resource.Status.ResolvedDomainID)
+					if findErr != nil {
+						logger.Error(findErr, "Failed to find existing resource by subdomain")
+					}
 				}
-			}
 
-			// Fallback: find by resource name (unique per host)
-			if existingRes == nil && resource.Spec.Name != "" {
-				existingRes, findErr = api.FindResourceByName(ctx, orgID, resource.Spec.Name)
-				if findErr != nil {
-					logger.Error(findErr, "Failed to find existing resource by name")
+				// Fallback: find by resource name (unique per host)
+				if existingRes == nil && resource.Spec.Name != "" {
+					existingRes, findErr = api.FindResourceByName(ctx, orgID, resource.Spec.Name)
+					if findErr != nil {
+						logger.Error(findErr, "Failed to find existing resource by name")
+					}
 				}
-			}
 
-			if existingRes != nil {
-				logger.Info("Found existing resource, binding to it",
-					"resourceID", existingRes.EffectiveID(),
-					"name", existingRes.Name,
-					"subdomain", existingRes.Subdomain)
-				resource.Status.BindingMode = "Bound"
-				pRes = existingRes
+				if existingRes != nil {
+					logger.Info("Found existing resource, binding to it",
+						"resourceID", existingRes.EffectiveID(),
+						"name", existingRes.Name,
+						"subdomain", existingRes.Subdomain)
+					if resource.Status.MigratingFrom == nil {
+						resource.Status.BindingMode = "Adopted"
+					}
+					escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionAdopted)
+					recordAdoption(r.Recorder, resource, "resource", existingRes.EffectiveID())
+					pRes = existingRes
+				} else {
+					lookup := fmt.Sprintf("name=%s", resource.Spec.Name)
+					if resource.Spec.HTTPConfig != nil {
+						lookup = fmt.Sprintf("subdomain=%s, domainID=%s, %s",
+							resource.Spec.HTTPConfig.Subdomain, resource.Status.ResolvedDomainID, lookup)
+					}
+					return nil, fmt.Errorf("resource exists but could not be found (%s): %w", lookup, err)
+				}
+			} else if pangolin.IsInvalid(err) && len(resSpec.Tags) > 0 {
+				return nil, fmt.Errorf("failed to create Pangolin resource, check spec.tags: %w", err)
 			} else {
-				return nil, fmt.Errorf("resource exists but could not be found (subdomain=%s, domainID=%s, name=%s): %w",
-					resource.Spec.HTTPConfig.Subdomain, resource.Status.ResolvedDomainID, resource.Spec.Name, err)
+				return nil, fmt.Errorf("failed to create Pangolin resource: %w", err)
 			}
 		} else {
-			return nil, fmt.Errorf("failed to create Pangolin resource: %w", err)
+			resource.Status.BindingMode = "Created"
+			escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionCreated)
+			resource.Status.AppliedSpec = &tunnelv1alpha1.AppliedResourceSpec{
+				Protocol:  resSpec.Protocol,
+				HTTP:      resSpec.HTTP,
+				Subdomain: resSpec.Subdomain,
+				DomainID:  resSpec.DomainID,
+				ProxyPort: resSpec.ProxyPort,
+			}
 		}
-	} else {
-		resource.Status.BindingMode = "Created"
 	}
 
 	// Update SSO settings (for both new and existing resources)
@@ -392,9 +606,139 @@ func (r *PangolinResourceReconciler) reconcilePangolinResource(
 		}
 	}
 
+	if err := r.updateResourceStickySession(ctx, api, pRes.EffectiveID(), resource); err != nil {
+		logger.Error(err, "Failed to update sticky session, resource created/bound but sticky session not configured")
+	}
+
 	return pRes, nil
 }
 
+// findAdoptableResource looks for an existing Pangolin resource that spec would
+// duplicate: an HTTP resource on the same subdomain and domain, or a TCP/UDP
+// resource with the same name, protocol and proxy port. It returns nil if there
+// is none.
+func findAdoptableResource(ctx context.Context, api *pangolin.Client, orgID string, spec pangolin.ResourceCreateSpec) (*pangolin.Resource, error) {
+	if !spec.HTTP && spec.Name == "" {
+		return nil, nil
+	}
+	resources, err := api.ListResources(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range resources {
+		existing := &resources[i]
+		if existing.HTTP != spec.HTTP {
+			continue
+		}
+		if spec.HTTP {
+			if existing.Subdomain == spec.Subdomain && existing.DomainID == spec.DomainID {
+				return existing, nil
+			}
+		} else if existing.Name == spec.Name && existing.Protocol == spec.Protocol && existing.ProxyPort == spec.ProxyPort {
+			return existing, nil
+		}
+	}
+	return nil, nil
+}
+
+// reconcileResourceDrift updates a created resource in Pangolin when it no longer
+// matches the spec.
+//
+// The resource is fetched from the API and compared against the desired subdomain
+// and domain of HTTP resources, or the proxy port of TCP/UDP resources, and
+// against spec.tags if set. Diverging fields are sent in a single UpdateResource
+// call. Changing the protocol is not an in-place update, so it returns an error
+// until the resource is recreated. A subdomain or domain Pangolin cannot change
+// in place starts a recreate instead, see startResourceMigration. A resource
+// deleted outside the operator is forgotten and errResourceDeleted returned, so
+// it is created again.
+//
+// Returns the resource as fetched before any update.
+func (r *PangolinResourceReconciler) reconcileResourceDrift(
+	ctx context.Context,
+	api *pangolin.Client,
+	orgID string,
+	resource *tunnelv1alpha1.PangolinResource,
+) (*pangolin.Resource, error) {
+	logger := log.FromContext(ctx)
+	resourceID := resource.Status.ResourceID
+
+	current, err := api.GetResource(ctx, resourceID)
+	if pangolin.IsNotFound(err) {
+		logger.Info("Pangolin resource was deleted outside the operator, recreating it", "resourceID", resourceID)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(resource, corev1.EventTypeWarning, "ResourceDeleted",
+				"Resource %s was deleted in Pangolin, recreating it", resourceID)
+		}
+		resetResourceStatus(resource)
+		resource.Status.BindingMode = ""
+		return nil, errResourceDeleted
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pangolin resource %s: %w", resourceID, err)
+	}
+
+	observedProtocol := current.Protocol
+	if current.HTTP {
+		observedProtocol = "http"
+	}
+	if observedProtocol != "" && observedProtocol != resource.Spec.Protocol {
+		return nil, fmt.Errorf("protocol cannot be changed from %s to %s in place, delete and recreate the resource",
+			observedProtocol, resource.Spec.Protocol)
+	}
+
+	var update pangolin.ResourceUpdateSpec
+	if resource.Spec.Protocol == "http" && resource.Spec.HTTPConfig != nil {
+		if subdomain := resource.Spec.HTTPConfig.Subdomain; current.Subdomain != subdomain {
+			update.Subdomain = &subdomain
+		}
+		if domainID := resource.Status.ResolvedDomainID; domainID != "" && current.DomainID != domainID {
+			update.DomainID = &domainID
+		}
+		if update.Subdomain != nil || update.DomainID != nil {
+			if err := r.checkSubdomainConflict(ctx, api, orgID, resource, resource.Spec.HTTPConfig.Subdomain,
+				resource.Status.ResolvedDomainID); err != nil {
+				return nil, err
+			}
+		}
+	} else if resource.Spec.ProxyConfig != nil {
+		if proxyPort := resource.Spec.ProxyConfig.ProxyPort; current.ProxyPort != proxyPort {
+			update.ProxyPort = &proxyPort
+		}
+	}
+	if tags := resource.Spec.Tags; tags != nil && !maps.Equal(current.Tags, tags) {
+		update.Tags = &tags
+	}
+	if update == (pangolin.ResourceUpdateSpec{}) {
+		return current, nil
+	}
+
+	logger.Info("Pangolin resource differs from spec, updating", "resourceID", resourceID, "update", update)
+	if _, err := api.UpdateResource(ctx, resourceID, update); err != nil {
+		if pangolin.IsUnsupported(err) && (update.Subdomain != nil || update.DomainID != nil) {
+			return nil, startResourceMigration(resource, err)
+		}
+		if pangolin.IsInvalid(err) && update.Tags != nil {
+			return nil, fmt.Errorf("failed to update Pangolin resource %s, check spec.tags: %w", resourceID, err)
+		}
+		return nil, fmt.Errorf("failed to update Pangolin resource %s: %w", resourceID, err)
+	}
+	escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+
+	if applied := resource.Status.AppliedSpec; applied != nil {
+		if update.Subdomain != nil {
+			applied.Subdomain = *update.Subdomain
+		}
+		if update.DomainID != nil {
+			applied.DomainID = *update.DomainID
+		}
+		if update.ProxyPort != nil {
+			applied.ProxyPort = *update.ProxyPort
+		}
+	}
+	return current, nil
+}
+
 // resolveSiteForResource determines the site ID from the resource spec or tunnel.
 //
 // Resolution order:
@@ -438,9 +782,11 @@ func (r *PangolinResourceReconciler) resolveSiteForResource(
 // Process:
 //  1. List all existing targets for the resource
 //  2. Check if target matching spec already exists (by IP, port, method, siteID)
-//  3. If not exists: create new target
-//  4. If exists: skip creation
-//  5. Return complete list of all target IDs
+//  3. If exists: update its enabled state when it changed
+//  4. If not exists: update a target no longer matching the spec in place, see
+//     targetUpdatableToSpec, or create a new target when there is none
+//  5. Delete targets still not matching the spec
+//  6. Return complete list of all target IDs
 //
 // All targets are equal - there is no primary/secondary hierarchy.
 func (r *PangolinResourceReconciler) reconcilePangolinTarget(
@@ -448,6 +794,7 @@ func (r *PangolinResourceReconciler) reconcilePangolinTarget(
 	api *pangolin.Client,
 	resourceID string,
 	resource *tunnelv1alpha1.PangolinResource,
+	desiredTargets []tunnelv1alpha1.TargetConfig,
 	siteID string,
 ) ([]string, error) {
 	logger := log.FromContext(ctx)
@@ -461,62 +808,94 @@ func (r *PangolinResourceReconciler) reconcilePangolinTarget(
 
 	logger.Info("Found existing targets", "count", len(existingTargets))
 
-	// Get desired targets from spec
-	desiredTargets := resource.Spec.Targets
 	if len(desiredTargets) == 0 {
 		logger.Info("No targets specified in resource spec")
 		return []string{}, nil
 	}
 
-	// Create targets that don't exist
+	// Validate mTLS client certificates up front so a broken Secret fails the reconcile
+	clientCerts, err := r.loadTargetClientCerts(ctx, resource, desiredTargets)
+	if err != nil {
+		return nil, err
+	}
+	certIDs := r.reuploadRotatedClientCerts(ctx, api, resourceID, resource, clientCerts)
+
+	// Existing targets claimed by a desired target, by target ID
+	claimed := map[string]bool{}
+
+	// Keep targets matching the spec, only syncing their enabled state and health check
+	var unmatched []tunnelv1alpha1.TargetConfig
 	for _, desiredTarget := range desiredTargets {
-		targetExists := false
-
-		for _, t := range existingTargets {
-			if r.targetMatchesSpec(t, desiredTarget, siteID) {
-				logger.Info("Target matching spec already exists",
-					"targetID", t.EffectiveID(),
-					"ip", t.IP,
-					"port", t.Port,
-					"path", desiredTarget.Path)
-				targetExists = true
-				break
-			}
+		existing := findTarget(existingTargets, claimed, func(t pangolin.Target) bool {
+			return r.targetMatchesSpec(t, desiredTarget, siteID)
+		})
+		if existing == nil {
+			unmatched = append(unmatched, desiredTarget)
+			continue
+		}
+		logger.Info("Target matching spec already exists",
+			"targetID", existing.EffectiveID(),
+			"ip", existing.IP,
+			"port", existing.Port,
+			"path", desiredTarget.Path)
+		if enabled := targetEnabled(resource, desiredTarget); existing.Enabled != enabled ||
+			!healthCheckMatches(*existing, desiredTarget) || !clientCertMatches(*existing, desiredTarget, certIDs) {
+			r.updatePangolinTarget(ctx, api, resourceID, resource, existing, desiredTarget, certIDs)
 		}
+	}
 
-		if !targetExists {
-			logger.Info("Target matching spec not found, creating new target",
-				"ip", desiredTarget.IP,
-				"port", desiredTarget.Port,
-				"path", desiredTarget.Path)
-
-			tSpec := pangolin.TargetCreateSpec{
-				IP:            desiredTarget.IP,
-				Port:          desiredTarget.Port,
-				Method:        desiredTarget.Method,
-				Enabled:       true,
-				Path:          desiredTarget.Path,
-				PathMatchType: desiredTarget.PathMatchType,
-				Priority:      desiredTarget.Priority,
-			}
+	// Update other targets in place to the remaining spec, create targets when none are left
+	for _, desiredTarget := range unmatched {
+		existing := findTarget(existingTargets, claimed, func(t pangolin.Target) bool {
+			return r.targetUpdatableToSpec(t, desiredTarget, siteID)
+		})
+		if existing != nil {
+			r.updatePangolinTarget(ctx, api, resourceID, resource, existing, desiredTarget, certIDs)
+			continue
+		}
+
+		logger.Info("Target matching spec not found, creating new target",
+			"ip", desiredTarget.IP,
+			"port", desiredTarget.Port,
+			"path", desiredTarget.Path)
+
+		tSpec := pangolin.TargetCreateSpec{
+			IP:            desiredTarget.IP,
+			Port:          desiredTarget.Port,
+			Method:        desiredTarget.Method,
+			Enabled:       targetEnabled(resource, desiredTarget),
+			Path:          desiredTarget.Path,
+			PathMatchType: desiredTarget.PathMatchType,
+			Priority:      desiredTarget.Priority,
+			Weight:        desiredTarget.Weight,
+			HealthCheck:   targetHealthCheck(desiredTarget),
+		}
 
-			// Respect explicit enabled=false in spec
-			if resource.Spec.Enabled != nil && !*resource.Spec.Enabled {
-				tSpec.Enabled = false
+		if ref := desiredTarget.ClientCertSecretRef; ref != nil {
+			certID, err := r.uploadTargetClientCert(ctx, api, resourceID, resource, clientCerts[ref.Name])
+			if err != nil {
+				logger.Error(err, "Failed to upload client certificate", "secret", ref.Name)
+				continue // Try other targets
 			}
+			tSpec.ClientCertificateID = &certID
+		}
 
-			logger.Info("Creating target", "siteID", siteID, "spec", tSpec)
+		logger.Info("Creating target", "siteID", siteID, "spec", tSpec)
 
-			_, err := api.CreateTarget(ctx, resourceID, siteID, tSpec)
-			if err != nil {
-				// Handle "already exists" error gracefully (race condition)
-				if !strings.Contains(err.Error(), "already exists") {
-					logger.Error(err, "Failed to create Pangolin target", "path", desiredTarget.Path)
-					continue // Try other targets
-				}
-				logger.Info("Target creation reported 'already exists', considering as success")
-			} else {
-				logger.Info("Target created successfully", "path", desiredTarget.Path)
+		_, err := api.CreateTarget(ctx, resourceID, siteID, tSpec)
+		if err != nil {
+			// Handle "already exists" error gracefully (race condition)
+			if !strings.Contains(err.Error(), "already exists") {
+				logger.Error(err, "Failed to create Pangolin target", "path", desiredTarget.Path)
+				continue // Try other targets
+			}
+			logger.Info("Target creation reported 'already exists', considering as success")
+			recordAdoption(r.Recorder, resource, "target", fmt.Sprintf("%s:%d", tSpec.IP, tSpec.Port))
+		} else {
+			logger.Info("Target created successfully", "path", desiredTarget.Path)
+			escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+			if resource.Status.AppliedSpec != nil {
+				resource.Status.AppliedSpec.TargetMethod = tSpec.Method
 			}
 		}
 	}
@@ -547,6 +926,8 @@ func (r *PangolinResourceReconciler) reconcilePangolinTarget(
 				if err := api.DeleteTarget(ctx, resourceID, targetID); err != nil {
 					logger.Error(err, "Failed to delete orphaned target", "targetID", targetID)
 					// Continue with other deletions
+				} else {
+					escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
 				}
 			}
 		}
@@ -566,10 +947,95 @@ func (r *PangolinResourceReconciler) reconcilePangolinTarget(
 		}
 	}
 
+	resource.Status.TargetHealth = targetHealth(existingTargets)
+
 	logger.Info("Final target list", "totalTargets", len(targetIDs), "targetIDs", targetIDs)
 	return targetIDs, nil
 }
 
+// findTarget returns the first target matching match that is not claimed yet and
+// claims it. Returns nil when no target matches.
+func findTarget(targets []pangolin.Target, claimed map[string]bool, match func(pangolin.Target) bool) *pangolin.Target {
+	for i := range targets {
+		id := targets[i].EffectiveID()
+		if claimed[id] || !match(targets[i]) {
+			continue
+		}
+		claimed[id] = true
+		return &targets[i]
+	}
+	return nil
+}
+
+// targetEnabled reports whether a target should receive traffic. Targets of a
+// disabled or suspended resource, or of one outside its schedule, are disabled
+// regardless of their own setting.
+func targetEnabled(resource *tunnelv1alpha1.PangolinResource, spec tunnelv1alpha1.TargetConfig) bool {
+	if !resourceEnabled(resource) || resourceSuspended(resource) {
+		return false
+	}
+	return spec.Enabled == nil || *spec.Enabled
+}
+
+// updatePangolinTarget updates target in place to spec. A client certificate
+// uploaded again in certIDs is switched to. Failures are logged so the
+// remaining targets are still reconciled.
+func (r *PangolinResourceReconciler) updatePangolinTarget(
+	ctx context.Context,
+	api *pangolin.Client,
+	resourceID string,
+	resource *tunnelv1alpha1.PangolinResource,
+	target *pangolin.Target,
+	spec tunnelv1alpha1.TargetConfig,
+	certIDs map[string]int,
+) {
+	logger := log.FromContext(ctx)
+
+	tSpec := pangolin.TargetUpdateSpec{
+		IP:          spec.IP,
+		Port:        spec.Port,
+		Method:      spec.Method,
+		Enabled:     targetEnabled(resource, spec),
+		Priority:    spec.Priority,
+		Weight:      spec.Weight,
+		HealthCheck: targetHealthCheck(spec),
+	}
+	if ref := spec.ClientCertSecretRef; ref != nil {
+		if certID, ok := certIDs[ref.Name]; ok {
+			tSpec.ClientCertificateID = &certID
+		}
+	}
+	logger.Info("Updating target", "targetID", target.EffectiveID(), "spec", tSpec)
+
+	if _, err := api.UpdateTarget(ctx, resourceID, target.EffectiveID(), tSpec); err != nil {
+		logger.Error(err, "Failed to update Pangolin target", "targetID", target.EffectiveID())
+		return
+	}
+
+	escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+	if resource.Status.AppliedSpec != nil {
+		resource.Status.AppliedSpec.TargetMethod = tSpec.Method
+	}
+}
+
+// targetUpdatableToSpec checks if a target can be updated in place to spec.
+//
+// Address, method, priority, weight, health check and enabled state are updated
+// in place. Targets on another site or with a different mTLS setting are
+// recreated instead.
+func (r *PangolinResourceReconciler) targetUpdatableToSpec(
+	target pangolin.Target,
+	spec tunnelv1alpha1.TargetConfig,
+	siteID string,
+) bool {
+	siteMatch := true
+	if siteID != "" {
+		siteMatch = target.SiteID == mustParseInt(siteID)
+	}
+	certMatch := (spec.ClientCertSecretRef != nil) == (target.ClientCertificateID != nil)
+	return siteMatch && certMatch
+}
+
 // targetMatchesSpec checks if a target matches the desired specification.
 //
 // A target matches if:
@@ -577,6 +1043,11 @@ func (r *PangolinResourceReconciler) reconcilePangolinTarget(
 //   - Port matches
 //   - Method matches
 //   - SiteID matches (if siteID is specified)
+//   - Priority matches (if priority is specified)
+//   - Weight matches (if weight is specified)
+//   - A client certificate is configured exactly when clientCertSecretRef is set
+//
+// A target that doesn't match is updated in place when possible, see targetUpdatableToSpec.
 func (r *PangolinResourceReconciler) targetMatchesSpec(
 	target pangolin.Target,
 	spec tunnelv1alpha1.TargetConfig,
@@ -592,7 +1063,155 @@ func (r *PangolinResourceReconciler) targetMatchesSpec(
 		siteMatch = target.SiteID == siteIDInt
 	}
 
-	return ipMatch && portMatch && methodMatch && siteMatch
+	priorityMatch := spec.Priority == 0 || target.Priority == int(spec.Priority)
+
+	weightMatch := true
+	if spec.Weight != nil {
+		weightMatch = target.Weight != nil && *target.Weight == *spec.Weight
+	}
+
+	certMatch := (spec.ClientCertSecretRef != nil) == (target.ClientCertificateID != nil)
+
+	return ipMatch && portMatch && methodMatch && siteMatch && priorityMatch && weightMatch && certMatch
+}
+
+// loadTargetClientCerts fetches the client certificate Secrets referenced by the
+// desired targets, keyed by Secret name.
+//
+// Each Secret must contain non-empty tls.crt and tls.key entries.
+func (r *PangolinResourceReconciler) loadTargetClientCerts(
+	ctx context.Context,
+	resource *tunnelv1alpha1.PangolinResource,
+	targets []tunnelv1alpha1.TargetConfig,
+) (map[string]*corev1.Secret, error) {
+	secrets := map[string]*corev1.Secret{}
+	for _, t := range targets {
+		ref := t.ClientCertSecretRef
+		if ref == nil {
+			continue
+		}
+		if _, ok := secrets[ref.Name]; ok {
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: resource.Namespace}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get client certificate secret %s: %w", ref.Name, err)
+		}
+		for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+			if len(secret.Data[key]) == 0 {
+				return nil, fmt.Errorf("client certificate secret %s is missing key %q", ref.Name, key)
+			}
+		}
+		secrets[ref.Name] = secret
+	}
+	return secrets, nil
+}
+
+// reuploadRotatedClientCerts uploads the client certificate Secrets changed
+// since their last upload again and returns their certificate IDs by Secret
+// name. The uploaded versions are recorded in status.clientCertVersions; a
+// failed upload keeps the previous version so the next reconcile retries it.
+//
+// Secrets without a recorded version are uploaded when their targets are
+// created, so they are only recorded here.
+func (r *PangolinResourceReconciler) reuploadRotatedClientCerts(
+	ctx context.Context,
+	api *pangolin.Client,
+	resourceID string,
+	resource *tunnelv1alpha1.PangolinResource,
+	secrets map[string]*corev1.Secret,
+) map[string]int {
+	logger := log.FromContext(ctx)
+
+	certIDs := map[string]int{}
+	versions := map[string]string{}
+	for name, secret := range secrets {
+		versions[name] = secret.ResourceVersion
+		recorded, ok := resource.Status.ClientCertVersions[name]
+		if !ok || recorded == secret.ResourceVersion {
+			continue
+		}
+
+		logger.Info("Client certificate secret changed, uploading it again", "secret", name)
+		certID, err := r.uploadTargetClientCert(ctx, api, resourceID, resource, secret)
+		if err != nil {
+			logger.Error(err, "Failed to upload rotated client certificate", "secret", name)
+			versions[name] = recorded
+			continue
+		}
+		certIDs[name] = certID
+		escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+	}
+
+	if len(versions) == 0 {
+		versions = nil
+	}
+	resource.Status.ClientCertVersions = versions
+	return certIDs
+}
+
+// clientCertMatches reports whether target presents the client certificate
+// uploaded again in certIDs, if its Secret was uploaded again
+func clientCertMatches(target pangolin.Target, spec tunnelv1alpha1.TargetConfig, certIDs map[string]int) bool {
+	if spec.ClientCertSecretRef == nil {
+		return true
+	}
+	certID, ok := certIDs[spec.ClientCertSecretRef.Name]
+	return !ok || (target.ClientCertificateID != nil && *target.ClientCertificateID == certID)
+}
+
+// uploadTargetClientCert uploads a client certificate Secret to Pangolin and returns its ID.
+// The certificate is named after the Secret, so re-uploading replaces it.
+func (r *PangolinResourceReconciler) uploadTargetClientCert(
+	ctx context.Context,
+	api *pangolin.Client,
+	resourceID string,
+	resource *tunnelv1alpha1.PangolinResource,
+	secret *corev1.Secret,
+) (int, error) {
+	name := fmt.Sprintf("%s-%s", resource.Namespace, secret.Name)
+	cert, err := api.UploadClientCertificate(ctx, resourceID, name,
+		string(secret.Data[corev1.TLSCertKey]), string(secret.Data[corev1.TLSPrivateKeyKey]))
+	if err != nil {
+		return 0, err
+	}
+	return cert.CertificateID, nil
+}
+
+// desiredTargetsForResource returns the targets the operator manages for a resource:
+// spec.targets followed by the stable and canary targets from spec.canary.
+func desiredTargetsForResource(resource *tunnelv1alpha1.PangolinResource) ([]tunnelv1alpha1.TargetConfig, error) {
+	targets := append([]tunnelv1alpha1.TargetConfig{}, resource.Spec.Targets...)
+	if resource.Spec.Canary == nil {
+		return targets, nil
+	}
+
+	canaryTargets, err := canaryTargets(resource.Spec.Canary)
+	if err != nil {
+		return nil, err
+	}
+	return append(targets, canaryTargets...), nil
+}
+
+// canaryTargets translates a canary split into the stable and canary targets.
+//
+// The stable target is weighted 100-percent and the canary target percent, so
+// adjusting spec.canary.percent shifts traffic between the two.
+func canaryTargets(canary *tunnelv1alpha1.CanaryConfig) ([]tunnelv1alpha1.TargetConfig, error) {
+	if canary.Percent < 0 || canary.Percent > 100 {
+		return nil, fmt.Errorf("canary percent must be between 0 and 100, got %d", canary.Percent)
+	}
+
+	stableWeight := 100 - canary.Percent
+	canaryWeight := canary.Percent
+
+	stable := canary.Stable
+	stable.Weight = &stableWeight
+	canaryTarget := canary.Canary
+	canaryTarget.Weight = &canaryWeight
+
+	return []tunnelv1alpha1.TargetConfig{stable, canaryTarget}, nil
 }
 
 // resolveDomainForResource resolves the domain ID and full domain for HTTP resources.
@@ -600,7 +1219,7 @@ func (r *PangolinResourceReconciler) targetMatchesSpec(
 // Resolution priority:
 //  1. spec.httpConfig.domainId (explicit domain ID)
 //  2. spec.httpConfig.domainName (resolve name to ID via org domains)
-//  3. org.status.defaultDomainId (organization default)
+//  3. org.status.defaultDomainId (organization default), unless httpConfig.noDefaultDomain is set
 //
 // Returns:
 //   - domainId: Pangolin domain identifier
@@ -645,6 +1264,11 @@ func (r *PangolinResourceReconciler) resolveDomainForResource(
 		return "", "", fmt.Errorf("domain %q not found in organization %s", domainName, org.Name)
 	}
 
+	// Resources opting out of the fallback must name their domain explicitly
+	if resource.Spec.HTTPConfig != nil && resource.Spec.HTTPConfig.NoDefaultDomain != nil && *resource.Spec.HTTPConfig.NoDefaultDomain {
+		return "", "", errDomainRequired
+	}
+
 	// Priority 3: Organization default domain
 	if org.Status.DefaultDomainID != "" {
 		domainID := org.Status.DefaultDomainID
@@ -665,6 +1289,267 @@ func (r *PangolinResourceReconciler) resolveDomainForResource(
 	return "", "", fmt.Errorf("could not resolve domain for resource")
 }
 
+// reconcileResourceDomain resolves the domain of an HTTP resource and reacts to
+// its previously resolved domain disappearing from the organization.
+//
+// When the resolved domain was removed, the DomainRemoved condition is set.
+// If another domain resolves (e.g. the organization default), the status is
+// moved to it and created resources are updated in Pangolin; otherwise an
+// error is returned and the resource stays in Error until a domain is available.
+func (r *PangolinResourceReconciler) reconcileResourceDomain(
+	ctx context.Context,
+	apiClient *pangolin.Client,
+	resource *tunnelv1alpha1.PangolinResource,
+	org *tunnelv1alpha1.PangolinOrganization,
+) error {
+	logger := log.FromContext(ctx)
+
+	previousDomainID := resource.Status.ResolvedDomainID
+	removed := previousDomainID != "" && !organizationHasDomain(org, previousDomainID)
+
+	domainID, fullDomain, err := r.resolveDomainForResource(ctx, resource, org)
+	if err != nil {
+		if removed {
+			setCondition(&resource.Status.Conditions, DomainRemovedCondition, metav1.ConditionTrue, "DomainRemoved",
+				fmt.Sprintf("domain %s was removed from organization %s: %v", previousDomainID, org.Name, err), resource.Generation)
+		}
+		return err
+	}
+
+	switch {
+	case removed && domainID != previousDomainID:
+		// Created resources are moved in Pangolin, bound ones are owned by someone else
+		if resource.Status.ResourceID != "" && resource.Status.BindingMode == "Created" {
+			if _, err := apiClient.UpdateResource(ctx, resource.Status.ResourceID, pangolin.ResourceUpdateSpec{
+				DomainID: &domainID,
+			}); err != nil {
+				return fmt.Errorf("failed to move resource to domain %s: %w", domainID, err)
+			}
+		}
+
+		escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+
+		message := fmt.Sprintf("domain %s was removed from organization %s, moved to %s", previousDomainID, org.Name, domainID)
+		logger.Info("Resolved domain was removed, re-resolved", "previousDomainID", previousDomainID, "domainID", domainID)
+		setCondition(&resource.Status.Conditions, DomainRemovedCondition, metav1.ConditionFalse, "Reresolved", message,
+			resource.Generation)
+		if r.Recorder != nil {
+			r.Recorder.Event(resource, corev1.EventTypeWarning, "DomainRemoved", message)
+		}
+	case meta.IsStatusConditionTrue(resource.Status.Conditions, DomainRemovedCondition):
+		setCondition(&resource.Status.Conditions, DomainRemovedCondition, metav1.ConditionFalse, "DomainAvailable",
+			fmt.Sprintf("domain %s is available", domainID), resource.Generation)
+	}
+
+	resource.Status.ResolvedDomainID = domainID
+	resource.Status.FullDomain = fullDomain
+	return nil
+}
+
+// resolvesDomain reports whether the domain of the resource is resolved from
+// spec.httpConfig. Bound resources keep the domain of the resource they bind
+// to, as their httpConfig is ignored.
+func resolvesDomain(resource *tunnelv1alpha1.PangolinResource) bool {
+	return resource.Spec.Protocol == "http" && resource.Spec.HTTPConfig != nil && resource.Spec.BindField() == ""
+}
+
+// setConfigConflictCondition sets the ConfigConflict condition when the spec
+// binds to an existing Pangolin resource while create configuration is also
+// given, and removes it otherwise.
+func setConfigConflictCondition(resource *tunnelv1alpha1.PangolinResource) {
+	ignored := ignoredBindFields(resource)
+	if len(ignored) == 0 {
+		meta.RemoveStatusCondition(&resource.Status.Conditions, ConfigConflictCondition)
+		return
+	}
+	message := fmt.Sprintf("%s binds to existing %s, ignoring %s",
+		resource.Spec.BindField(), bindDescription(resource), strings.Join(ignored, ", "))
+	setCondition(&resource.Status.Conditions, ConfigConflictCondition, metav1.ConditionTrue, "BindIgnoresCreateConfig",
+		message, resource.Generation)
+}
+
+// ignoredBindFields lists the create configuration fields of a resource that
+// are ignored because the spec binds it to an existing Pangolin resource
+func ignoredBindFields(resource *tunnelv1alpha1.PangolinResource) []string {
+	if resource.Spec.BindField() == "" {
+		return nil
+	}
+	var ignored []string
+	if resource.Spec.HTTPConfig != nil {
+		ignored = append(ignored, "spec.httpConfig")
+	}
+	if resource.Spec.ProxyConfig != nil {
+		ignored = append(ignored, "spec.proxyConfig")
+	}
+	return ignored
+}
+
+// bindDescription describes the existing Pangolin resource the spec binds to
+func bindDescription(resource *tunnelv1alpha1.PangolinResource) string {
+	switch {
+	case resource.Spec.ResourceID != "":
+		return "resource " + resource.Spec.ResourceID
+	case resource.Spec.BindByName != "":
+		return fmt.Sprintf("resource named %q", resource.Spec.BindByName)
+	default:
+		return fmt.Sprintf("resource on subdomain %q", resource.Spec.BindBySubdomain)
+	}
+}
+
+// bindPangolinResource fetches the Pangolin resource bound by spec.resourceId,
+// or resolved from spec.bindByName or spec.bindBySubdomain, see
+// findResourceToBind.
+//
+// A bound resource that does not exist (a wrong ID, or deleted outside the
+// operator) is an error. Otherwise its domain and authentication settings are
+// reported in status, as the bound resource's configuration is authoritative.
+func (r *PangolinResourceReconciler) bindPangolinResource(
+	ctx context.Context,
+	api *pangolin.Client,
+	orgID string,
+	resource *tunnelv1alpha1.PangolinResource,
+) (*pangolin.Resource, error) {
+	resourceID := resource.Spec.ResourceID
+	if resourceID == "" {
+		found, err := findResourceToBind(ctx, api, orgID, resource)
+		if err != nil {
+			return nil, err
+		}
+		resourceID = found.EffectiveID()
+	}
+
+	bound, err := api.GetResource(ctx, resourceID)
+	if pangolin.IsNotFound(err) {
+		return nil, fmt.Errorf("bound resource %s not found in Pangolin: %w", resourceID, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bound resource %s: %w", resourceID, err)
+	}
+	if bound.EffectiveID() == "" {
+		bound.ID = resourceID
+	}
+
+	if resource.Status.ResourceID != resourceID {
+		escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionAdopted)
+	}
+	resource.Status.BindingMode = "Bound"
+	if bound.FullDomain != "" {
+		resource.Status.FullDomain = bound.FullDomain
+		resource.Status.ResolvedDomainID = bound.DomainID
+	}
+	resource.Status.SSOEnabled = bound.SSO
+	resource.Status.BlockAccessEnabled = bound.BlockAccess
+	return bound, nil
+}
+
+// findResourceToBind looks up the resource of the organization named by
+// spec.bindByName, or serving spec.bindBySubdomain as its subdomain or full
+// domain. Exactly one resource must match, so a binding never silently picks
+// one of several.
+func findResourceToBind(
+	ctx context.Context,
+	api *pangolin.Client,
+	orgID string,
+	resource *tunnelv1alpha1.PangolinResource,
+) (*pangolin.Resource, error) {
+	resources, err := api.ListResources(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources to bind: %w", err)
+	}
+
+	var matches []pangolin.Resource
+	for _, candidate := range resources {
+		if name := resource.Spec.BindByName; name != "" && candidate.Name == name {
+			matches = append(matches, candidate)
+		}
+		if subdomain := resource.Spec.BindBySubdomain; subdomain != "" &&
+			(candidate.Subdomain == subdomain || candidate.FullDomain == subdomain) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no %s found in Pangolin", bindDescription(resource))
+	case 1:
+		return &matches[0], nil
+	}
+	ids := make([]string, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.EffectiveID())
+	}
+	slices.Sort(ids)
+	return nil, fmt.Errorf("%d resources match %s (%s), bind by spec.resourceId instead",
+		len(matches), resource.Spec.BindField(), strings.Join(ids, ", "))
+}
+
+// organizationHasDomain reports whether domainID is one of the organization's domains
+func organizationHasDomain(org *tunnelv1alpha1.PangolinOrganization, domainID string) bool {
+	for _, domain := range org.Status.Domains {
+		if domain.DomainID == domainID {
+			return true
+		}
+	}
+	return false
+}
+
+// httpResourceURL builds the public URL of an HTTP resource.
+// The scheme comes from httpConfig.urlScheme (default https), and the port is only
+// included when it is set and not the default port of that scheme.
+func httpResourceURL(fullDomain string, httpConfig *tunnelv1alpha1.HTTPConfig) string {
+	scheme, defaultPort := "https", int32(443)
+	if httpConfig != nil && httpConfig.URLScheme == "http" {
+		scheme, defaultPort = "http", 80
+	}
+	if httpConfig != nil && httpConfig.Port > 0 && httpConfig.Port != defaultPort {
+		return fmt.Sprintf("%s://%s:%d", scheme, fullDomain, httpConfig.Port)
+	}
+	return fmt.Sprintf("%s://%s", scheme, fullDomain)
+}
+
+// proxyEnabled reports whether the proxy of a TCP/UDP resource is enabled,
+// defaulting to true like the CRD when enableProxy is not set.
+func proxyEnabled(config *tunnelv1alpha1.ProxyConfig) bool {
+	return config.EnableProxy == nil || *config.EnableProxy
+}
+
+// resolveProxyEndpoint builds the endpoint clients use to reach a TCP/UDP resource.
+//
+// The host is the relay address the API assigned to the resource, looked up with
+// GetResource when the create or bind response didn't carry it, and falls back to
+// the tunnel endpoint. Any port on the host is replaced by spec.proxyConfig.proxyPort.
+// Returns an empty string when no host is known.
+func (r *PangolinResourceReconciler) resolveProxyEndpoint(
+	ctx context.Context,
+	api *pangolin.Client,
+	pRes *pangolin.Resource,
+	resource *tunnelv1alpha1.PangolinResource,
+	tunnel *tunnelv1alpha1.PangolinTunnel,
+) string {
+	logger := log.FromContext(ctx)
+
+	host := pRes.ProxyAddress
+	if host == "" {
+		fetched, err := api.GetResource(ctx, pRes.EffectiveID())
+		if err != nil {
+			logger.Error(err, "Failed to get resource proxy address, falling back to the tunnel endpoint")
+		} else {
+			host = fetched.ProxyAddress
+		}
+	}
+	if host == "" && tunnel != nil {
+		host = tunnel.Status.Endpoint
+	}
+	if host == "" {
+		return ""
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(resource.Spec.ProxyConfig.ProxyPort)))
+}
+
 // updateResourceStatus updates the status of a PangolinResource with the given status and message.
 //
 // Status values:
@@ -673,53 +1558,67 @@ func (r *PangolinResourceReconciler) resolveDomainForResource(
 //   - "Waiting": Waiting for dependencies (org, tunnel)
 //   - "Creating": Resource is being created
 //   - "Deleting": Resource is being deleted
+//   - "DryRun": The plan in status was computed but not applied, see DryRunAnnotation
 //
 // The function also updates the Ready condition with appropriate reason and message.
-// If status is not "Ready", the reconcile will be requeued after 1 minute.
+// If status is not "Ready", the reconcile is requeued: errors with backoff, see
+// errorBackoff, anything else after 1 minute.
 func (r *PangolinResourceReconciler) updateResourceStatus(ctx context.Context, resource *tunnelv1alpha1.PangolinResource, status, message string) (ctrl.Result, error) {
+	return r.updateResourceStatusReason(ctx, resource, status, "", message)
+}
+
+// updateResourceStatusReason is updateResourceStatus with reason on the Ready
+// condition instead of the one for status, unless reason is empty.
+func (r *PangolinResourceReconciler) updateResourceStatusReason(
+	ctx context.Context,
+	resource *tunnelv1alpha1.PangolinResource,
+	status, reason, message string,
+) (ctrl.Result, error) {
 	resource.Status.Status = status
 	resource.Status.ObservedGeneration = resource.Generation
+	resource.Status.LastSyncTime = syncTime(resource.Status.LastSyncTime, status, time.Now())
+	meta.RemoveStatusCondition(&resource.Status.Conditions, PausedCondition)
+	if status != "DryRun" {
+		resource.Status.Plan = nil
+	}
 
-	condType := "Ready"
-	condStatus := metav1.ConditionTrue
-	reason := "ReconcileSuccess"
-	if status != "Ready" {
-		condStatus = metav1.ConditionFalse
-		reason = "ReconcileError"
-	}
-	now := metav1.NewTime(time.Now())
-	newCond := metav1.Condition{
-		Type:               condType,
-		Status:             condStatus,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: now,
-		ObservedGeneration: resource.Generation,
-	}
-
-	// Update existing condition or append new one
-	updated := false
-	for i, c := range resource.Status.Conditions {
-		if c.Type == condType {
-			if c.Status != condStatus {
-				newCond.LastTransitionTime = now
-			} else {
-				newCond.LastTransitionTime = c.LastTransitionTime
-			}
-			resource.Status.Conditions[i] = newCond
-			updated = true
-			break
-		}
+	setReadyCondition(&resource.Status.Conditions, resource.Generation, status, message)
+	if reason != "" {
+		setCondition(&resource.Status.Conditions, "Ready", metav1.ConditionFalse, reason, message, resource.Generation)
 	}
-	if !updated {
-		resource.Status.Conditions = append(resource.Status.Conditions, newCond)
+
+	result := ctrl.Result{RequeueAfter: r.backoff.RequeueAfter(resource, status)}
+
+	ctx, cancel := statusContext(ctx)
+	defer cancel()
+
+	// Skip the write when nothing changed
+	if statusUnchanged(ctx, r.Client, resource) {
+		return result, nil
 	}
 
-	err := r.Status().Update(ctx, resource)
-	if status != "Ready" {
-		return ctrl.Result{RequeueAfter: time.Minute}, err
+	return result, r.Status().Update(ctx, resource)
+}
+
+// updateResourceError reports err as the Error status of resource, with the
+// Ready condition reason of errorReason. A rate limited Pangolin API error makes
+// the retry wait at least its Retry-After, see errorBackoff.ObserveError.
+func (r *PangolinResourceReconciler) updateResourceError(ctx context.Context, resource *tunnelv1alpha1.PangolinResource, err error) (ctrl.Result, error) {
+	r.backoff.ObserveError(resource, err)
+	return r.updateResourceStatusReason(ctx, resource, "Error", errorReason(err), errorMessage(err))
+}
+
+// recordSuspendTransition emits an event when the resource enters or leaves the
+// Suspended status
+func (r *PangolinResourceReconciler) recordSuspendTransition(resource *tunnelv1alpha1.PangolinResource, status string) {
+	if r.Recorder == nil || (status == "Suspended") == (resource.Status.Status == "Suspended") {
+		return
+	}
+	if status == "Suspended" {
+		r.Recorder.Event(resource, corev1.EventTypeNormal, "Suspended", "Resource suspended, its targets were disabled")
+	} else {
+		r.Recorder.Event(resource, corev1.EventTypeNormal, "Resumed", "Resource resumed, its targets were enabled")
 	}
-	return ctrl.Result{}, err
 }
 
 // updateResourceSSO updates the SSO and BlockAccess settings for a resource.
@@ -765,6 +1664,9 @@ func (r *PangolinResourceReconciler) updateResourceSSO(
 	}
 
 	// Update status to reflect SSO configuration
+	if resource.Status.SSOEnabled != sso || resource.Status.BlockAccessEnabled != blockAccess {
+		escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+	}
 	resource.Status.SSOEnabled = sso
 	resource.Status.BlockAccessEnabled = blockAccess
 
@@ -785,20 +1687,265 @@ func (r *PangolinResourceReconciler) updateResourceSSO(
 	return nil
 }
 
+// updateResourceStickySession applies spec.stickySession to the Pangolin resource.
+// Nothing is sent when it is not set or already applied according to status.
+func (r *PangolinResourceReconciler) updateResourceStickySession(
+	ctx context.Context,
+	api *pangolin.Client,
+	resourceID string,
+	resource *tunnelv1alpha1.PangolinResource,
+) error {
+	sticky := resource.Spec.StickySession
+	if sticky == nil || *sticky == resource.Status.StickySession {
+		return nil
+	}
+
+	if _, err := api.UpdateResource(ctx, resourceID, pangolin.ResourceUpdateSpec{StickySession: sticky}); err != nil {
+		return fmt.Errorf("failed to update resource sticky session: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Updated sticky session", "resourceID", resourceID, "stickySession", *sticky)
+	escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+	resource.Status.StickySession = *sticky
+	return nil
+}
+
+// reconcileResourceEvents copies the latest Pangolin events for the resource into
+// status.recentEvents and emits a Kubernetes event for each one not seen before,
+// so failures like certificate issuance errors show up in `kubectl describe`.
+// Kubernetes events use the fixed reasons PangolinEvent and PangolinWarning,
+// as Pangolin's own reasons are not CamelCase identifiers; those lead the
+// message instead.
+//
+// Failing to fetch events never fails the reconcile.
+func (r *PangolinResourceReconciler) reconcileResourceEvents(
+	ctx context.Context,
+	api *pangolin.Client,
+	resourceID string,
+	resource *tunnelv1alpha1.PangolinResource,
+) {
+	logger := log.FromContext(ctx)
+
+	events, err := api.ListResourceEvents(ctx, resourceID)
+	if err != nil {
+		logger.Error(err, "Failed to list resource events", "resourceID", resourceID)
+		return
+	}
+
+	recent := latestResourceEvents(events, maxRecentResourceEvents)
+
+	if r.Recorder != nil {
+		for _, e := range recent {
+			if containsResourceEvent(resource.Status.RecentEvents, e) {
+				continue
+			}
+			eventType, reason := corev1.EventTypeNormal, "PangolinEvent"
+			if e.Type == "warning" || e.Type == "error" {
+				eventType, reason = corev1.EventTypeWarning, "PangolinWarning"
+			}
+			message := e.Message
+			if e.Reason != "" {
+				message = fmt.Sprintf("%s: %s", e.Reason, e.Message)
+			}
+			r.Recorder.Event(resource, eventType, reason, message)
+		}
+	}
+
+	resource.Status.RecentEvents = recent
+}
+
+// latestResourceEvents converts the newest n Pangolin events to their CRD form.
+// Events are expected oldest first, as returned by the API.
+func latestResourceEvents(events []pangolin.ResourceEvent, n int) []tunnelv1alpha1.ResourceEvent {
+	if len(events) > n {
+		events = events[len(events)-n:]
+	}
+	result := make([]tunnelv1alpha1.ResourceEvent, 0, len(events))
+	for _, e := range events {
+		result = append(result, tunnelv1alpha1.ResourceEvent{
+			Type:      e.Type,
+			Reason:    e.Reason,
+			Message:   e.Message,
+			Timestamp: e.Timestamp,
+		})
+	}
+	return result
+}
+
+// containsResourceEvent reports whether an event is already recorded in status.
+func containsResourceEvent(events []tunnelv1alpha1.ResourceEvent, event tunnelv1alpha1.ResourceEvent) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
 // handleResourceDeletion handles the cleanup when a PangolinResource is deleted.
-// Currently performs no Pangolin API cleanup, only removes the finalizer.
 //
-// Future enhancement: Could optionally delete targets or resources from Pangolin API.
+// With the Delete policy (the default for created resources) the targets and the
+// resource are deleted from Pangolin before the finalizer is removed. Bound
+// resources are owned by someone else and retained unless the policy says otherwise.
 func (r *PangolinResourceReconciler) handleResourceDeletion(ctx context.Context, resource *tunnelv1alpha1.PangolinResource) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// Resources own no child objects, so Orphan behaves like Retain
+	policy := effectiveDeletionPolicy(resource.Spec.DeletionPolicy, resource.Status.BindingMode)
+	logger.Info("Deleting resource", "deletionPolicy", policy, "resourceID", resource.Status.ResourceID)
+
+	if policy == tunnelv1alpha1.DeletionPolicyDelete {
+		if err := r.deletePangolinResource(ctx, resource); err != nil {
+			logger.Error(err, "Failed to delete Pangolin resource")
+			return ctrl.Result{}, err
+		}
+	}
+
+	r.backoff.Forget(client.ObjectKeyFromObject(resource))
 	controllerutil.RemoveFinalizer(resource, ResourceFinalizerName)
 	return ctrl.Result{}, r.Update(ctx, resource)
 }
 
+// deletePangolinResource deletes the resource and its targets from Pangolin,
+// and the resource it replaces while it is recreated.
+// Targets or resources that are already gone are not an error, so deletion is idempotent.
+// If the tunnel or organization is gone the API can't be reached, so deletion is skipped.
+func (r *PangolinResourceReconciler) deletePangolinResource(ctx context.Context, resource *tunnelv1alpha1.PangolinResource) error {
+	logger := log.FromContext(ctx)
+
+	migration := resource.Status.MigratingFrom
+	if resource.Status.ResourceID == "" && migration == nil {
+		return nil
+	}
+
+	tunnel, err := r.getTunnelForResource(ctx, resource)
+	if errors.IsNotFound(err) {
+		logger.Info("Tunnel not found, skipping resource deletion", "resourceID", resource.Status.ResourceID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	org, err := r.getOrganizationForTunnel(ctx, tunnel)
+	if errors.IsNotFound(err) {
+		logger.Info("Organization not found, skipping resource deletion", "resourceID", resource.Status.ResourceID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := r.createPangolinClientFromOrganization(ctx, org)
+	if err != nil {
+		return err
+	}
+
+	// A resource deleted while it is recreated also deletes the one it replaces
+	if err := deleteReplacedResource(ctx, apiClient, resource); err != nil {
+		return err
+	}
+	if resource.Status.ResourceID == "" {
+		return nil
+	}
+
+	// Delete targets first so none are left dangling if the resource deletion fails
+	for _, targetID := range resourceTargetIDs(resource) {
+		if err := apiClient.DeleteTarget(ctx, resource.Status.ResourceID, targetID); err != nil {
+			return fmt.Errorf("failed to delete target %s: %w", targetID, err)
+		}
+	}
+	return apiClient.DeleteResource(ctx, resource.Status.ResourceID)
+}
+
+// resourceTargetIDs returns the target IDs recorded in status, including the
+// deprecated single targetId of resources reconciled by older versions.
+func resourceTargetIDs(resource *tunnelv1alpha1.PangolinResource) []string {
+	ids := slices.Clone(resource.Status.TargetIDs)
+	if id := resource.Status.TargetID; id != "" && !slices.Contains(ids, id) {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// resourcesForOrganizations maps the organizations in namespace matching uses
+// to the resources on their tunnels, so a change to an object the organizations
+// build the resource API client from is picked up right away.
+func (r *PangolinResourceReconciler) resourcesForOrganizations(
+	ctx context.Context,
+	namespace string,
+	uses func(*tunnelv1alpha1.PangolinOrganization) bool,
+) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	orgs := &tunnelv1alpha1.PangolinOrganizationList{}
+	if err := r.List(ctx, orgs, client.InNamespace(namespace)); err != nil {
+		logger.Error(err, "Failed to list organizations", "namespace", namespace)
+		return nil
+	}
+	var matched []*tunnelv1alpha1.PangolinOrganization
+	for i := range orgs.Items {
+		if uses(&orgs.Items[i]) {
+			matched = append(matched, &orgs.Items[i])
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	tunnels := &tunnelv1alpha1.PangolinTunnelList{}
+	if err := r.List(ctx, tunnels); err != nil {
+		logger.Error(err, "Failed to list tunnels of organizations", "namespace", namespace)
+		return nil
+	}
+	orgTunnels := map[types.NamespacedName]bool{}
+	for _, tunnel := range tunnels.Items {
+		for _, org := range matched {
+			if referencesOrganization(tunnel.Spec.OrganizationRef, tunnel.Namespace, r.AllowCrossNamespaceOrg, org) {
+				orgTunnels[client.ObjectKeyFromObject(&tunnel)] = true
+			}
+		}
+	}
+
+	resources := &tunnelv1alpha1.PangolinResourceList{}
+	if err := r.List(ctx, resources); err != nil {
+		logger.Error(err, "Failed to list resources of organizations", "namespace", namespace)
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, resource := range resources.Items {
+		if !matchesClass(&resource, r.Class) {
+			continue
+		}
+		if key, err := tunnelKey(resource.Spec.TunnelRef, resource.Namespace, true); err == nil && orgTunnels[key] {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&resource)})
+		}
+	}
+	return requests
+}
+
+// resourcesForConfigMap maps a ConfigMap to the resources of the organizations
+// using it as caBundleRef, so a renewed CA bundle is trusted right away
+func (r *PangolinResourceReconciler) resourcesForConfigMap(ctx context.Context, configMap client.Object) []reconcile.Request {
+	return r.resourcesForOrganizations(ctx, configMap.GetNamespace(), func(org *tunnelv1alpha1.PangolinOrganization) bool {
+		return org.Spec.CABundleRef != nil && org.Spec.CABundleRef.Name == configMap.GetName()
+	})
+}
+
 // SetupWithManager sets up the controller with the Manager.
+//
+// Secrets are watched so a changed password or PIN code of spec.httpConfig.auth,
+// a rotated client certificate or a rotated resourceAPIKeyRef of the
+// organization is applied without waiting for the next resync. ConfigMaps are
+// watched for the caBundleRef of the organization.
 func (r *PangolinResourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&tunnelv1alpha1.PangolinResource{}).
-		Complete(r)
+	r.backoff = newErrorBackoff(r.ErrorRequeueInterval, r.ErrorBackoffCap, r.ResyncInterval)
+	r.clients = newPangolinClientCache()
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&tunnelv1alpha1.PangolinResource{}, builder.WithPredicates(classPredicate(r.Class))).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.resourcesForSecret)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.resourcesForConfigMap))
+	return r.FullSync.watch(b, &tunnelv1alpha1.PangolinResourceList{}, classPredicate(r.Class)).Complete(withReconcileTimeout(r, r.ReconcileTimeout))
 }
 
 // mustParseInt converts a string to an integer, returning 0 on error.
@@ -14,16 +14,29 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
 )
 
 var _ = Describe("PangolinResource Controller", func() {
@@ -77,4 +90,2395 @@ var _ = Describe("PangolinResource Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When a canary split is configured", func() {
+		canary := &tunnelv1alpha1.CanaryConfig{
+			Stable: tunnelv1alpha1.TargetConfig{IP: "10.0.0.1", Port: 8080, Method: "http"},
+			Canary: tunnelv1alpha1.TargetConfig{IP: "10.0.0.2", Port: 8080, Method: "http"},
+		}
+
+		It("should weight the targets by the canary percent", func() {
+			for percent, weights := range map[int32][2]int32{0: {100, 0}, 10: {90, 10}, 50: {50, 50}, 100: {0, 100}} {
+				canary.Percent = percent
+				targets, err := canaryTargets(canary)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(targets).To(HaveLen(2))
+				Expect(targets[0].IP).To(Equal("10.0.0.1"))
+				Expect(*targets[0].Weight).To(Equal(weights[0]))
+				Expect(targets[1].IP).To(Equal("10.0.0.2"))
+				Expect(*targets[1].Weight).To(Equal(weights[1]))
+			}
+		})
+
+		It("should reject a percent outside 0-100", func() {
+			canary.Percent = 101
+			_, err := canaryTargets(canary)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should update a target whose priority changed", func() {
+			reconciler := &PangolinResourceReconciler{}
+			weight := int32(90)
+			target := pangolin.Target{IP: "10.0.0.1", Port: 8080, Method: "http", Priority: 100, Weight: &weight}
+			spec := canary.Stable
+			spec.Weight = &weight
+			Expect(reconciler.targetMatchesSpec(target, spec, "")).To(BeTrue())
+
+			spec.Priority = 200
+			Expect(reconciler.targetMatchesSpec(target, spec, "")).To(BeFalse())
+			Expect(reconciler.targetUpdatableToSpec(target, spec, "")).To(BeTrue())
+		})
+	})
+
+	Context("When building the URL of an HTTP resource", func() {
+		It("should include a non-default port", func() {
+			Expect(httpResourceURL("app.example.com", &tunnelv1alpha1.HTTPConfig{Port: 8443})).
+				To(Equal("https://app.example.com:8443"))
+		})
+
+		It("should omit the default port", func() {
+			Expect(httpResourceURL("app.example.com", &tunnelv1alpha1.HTTPConfig{})).
+				To(Equal("https://app.example.com"))
+			Expect(httpResourceURL("app.example.com", &tunnelv1alpha1.HTTPConfig{Port: 443})).
+				To(Equal("https://app.example.com"))
+		})
+
+		It("should honor the URL scheme override", func() {
+			Expect(httpResourceURL("app.example.com", &tunnelv1alpha1.HTTPConfig{URLScheme: "http"})).
+				To(Equal("http://app.example.com"))
+			Expect(httpResourceURL("app.example.com", &tunnelv1alpha1.HTTPConfig{URLScheme: "http", Port: 8080})).
+				To(Equal("http://app.example.com:8080"))
+			Expect(httpResourceURL("app.example.com", &tunnelv1alpha1.HTTPConfig{URLScheme: "https"})).
+				To(Equal("https://app.example.com"))
+		})
+	})
+
+	Context("When Pangolin reports events for a resource", func() {
+		It("should surface the latest events in status and as Kubernetes events", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/resource/42/events"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"events":[
+					{"type":"info","reason":"Created","message":"resource created","timestamp":"2025-01-01T00:00:00Z"},
+					{"type":"error","reason":"CertificateFailed","message":"acme challenge failed","timestamp":"2025-01-01T00:01:00Z"}
+				]}}`))
+			}))
+			defer server.Close()
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &PangolinResourceReconciler{Recorder: recorder}
+			resource := &tunnelv1alpha1.PangolinResource{}
+
+			controllerReconciler.reconcileResourceEvents(context.Background(), pangolin.NewClient(server.URL, "token"), "42", resource)
+
+			Expect(resource.Status.RecentEvents).To(HaveLen(2))
+			Expect(resource.Status.RecentEvents[1].Reason).To(Equal("CertificateFailed"))
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect(<-recorder.Events).To(Equal("Normal PangolinEvent Created: resource created"))
+			Expect(<-recorder.Events).To(Equal("Warning PangolinWarning CertificateFailed: acme challenge failed"))
+
+			By("not re-emitting events that are already in status")
+			controllerReconciler.reconcileResourceEvents(context.Background(), pangolin.NewClient(server.URL, "token"), "42", resource)
+			Expect(recorder.Events).To(BeEmpty())
+		})
+	})
+
+	Context("When a resource disables the default domain fallback", func() {
+		org := &tunnelv1alpha1.PangolinOrganization{
+			Status: tunnelv1alpha1.PangolinOrganizationStatus{
+				DefaultDomainID: "domain1",
+				Domains:         []tunnelv1alpha1.Domain{{DomainID: "domain1", BaseDomain: "example.com"}},
+			},
+		}
+		noDefault := true
+
+		It("should fail fast when no domain is specified", func() {
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app", NoDefaultDomain: &noDefault},
+				},
+			}
+			_, _, err := (&PangolinResourceReconciler{}).resolveDomainForResource(context.Background(), resource, org)
+			Expect(err).To(MatchError(errDomainRequired))
+		})
+
+		It("should report DomainRequired as the reason of the Ready condition", func() {
+			ctx := context.Background()
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "domain-required", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef:  tunnelv1alpha1.LocalObjectReference{Name: "tunnel"},
+					Name:       "app",
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app", NoDefaultDomain: &noDefault},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			err := fmt.Errorf("failed to resolve domain: %w", errDomainRequired)
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, updateErr := controllerReconciler.updateResourceStatusReason(ctx, resource, "Error",
+				errorReason(err), errorMessage(err))
+			Expect(updateErr).NotTo(HaveOccurred())
+
+			ready := meta.FindStatusCondition(resource.Status.Conditions, "Ready")
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("DomainRequired"))
+			Expect(errorReason(fmt.Errorf("other"))).To(Equal("ReconcileError"))
+		})
+
+		It("should still resolve an explicit domain", func() {
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app", DomainName: "example.com", NoDefaultDomain: &noDefault},
+				},
+			}
+			domainID, fullDomain, err := (&PangolinResourceReconciler{}).resolveDomainForResource(context.Background(), resource, org)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domainID).To(Equal("domain1"))
+			Expect(fullDomain).To(Equal("app.example.com"))
+		})
+	})
+
+	Context("When a target requires a client certificate", func() {
+		newSecret := func(name string, data map[string][]byte) *corev1.Secret {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Data:       data,
+			}
+			Expect(k8sClient.Create(context.Background(), secret)).To(Succeed())
+			DeferCleanup(func() { Expect(k8sClient.Delete(context.Background(), secret)).To(Succeed()) })
+			return secret
+		}
+
+		It("should upload the certificate and reference it from the target", func() {
+			newSecret("backend-mtls", map[string][]byte{
+				corev1.TLSCertKey:       []byte("CERT"),
+				corev1.TLSPrivateKeyKey: []byte("KEY"),
+			})
+
+			var uploaded, created map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/resource/42/client-certificate":
+					Expect(json.NewDecoder(req.Body).Decode(&uploaded)).To(Succeed())
+					_, _ = w.Write([]byte(`{"success":true,"data":{"certificateId":7}}`))
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/resource/42/target":
+					Expect(json.NewDecoder(req.Body).Decode(&created)).To(Succeed())
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targetId":1}}`))
+				default:
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targets":[]}}`))
+				}
+			}))
+			defer server.Close()
+
+			resource := &tunnelv1alpha1.PangolinResource{ObjectMeta: metav1.ObjectMeta{Name: "mtls", Namespace: "default"}}
+			targets := []tunnelv1alpha1.TargetConfig{{
+				IP: "10.0.0.1", Port: 8443, Method: "https",
+				ClientCertSecretRef: &corev1.LocalObjectReference{Name: "backend-mtls"},
+			}}
+
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.reconcilePangolinTarget(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "42", resource, targets, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uploaded).To(HaveKeyWithValue("certificate", "CERT"))
+			Expect(uploaded).To(HaveKeyWithValue("privateKey", "KEY"))
+			Expect(created).To(HaveKeyWithValue("clientCertificateId", BeEquivalentTo(7)))
+		})
+
+		It("should upload a rotated certificate again and switch the target to it", func() {
+			secret := newSecret("backend-mtls-rotated", map[string][]byte{
+				corev1.TLSCertKey:       []byte("NEWCERT"),
+				corev1.TLSPrivateKeyKey: []byte("NEWKEY"),
+			})
+
+			var uploaded, updated map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/resource/42/client-certificate":
+					Expect(json.NewDecoder(req.Body).Decode(&uploaded)).To(Succeed())
+					_, _ = w.Write([]byte(`{"success":true,"data":{"certificateId":8}}`))
+				case req.Method == http.MethodPost && req.URL.Path == "/v1/resource/42/target/1":
+					Expect(json.NewDecoder(req.Body).Decode(&updated)).To(Succeed())
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targetId":1}}`))
+				default:
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targets":[{"targetId":1,"ip":"10.0.0.1","port":8443,` +
+						`"method":"https","enabled":true,"clientCertificateId":7}]}}`))
+				}
+			}))
+			defer server.Close()
+
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "mtls", Namespace: "default"},
+				Status: tunnelv1alpha1.PangolinResourceStatus{
+					ClientCertVersions: map[string]string{"backend-mtls-rotated": "outdated"},
+				},
+			}
+			targets := []tunnelv1alpha1.TargetConfig{{
+				IP: "10.0.0.1", Port: 8443, Method: "https",
+				ClientCertSecretRef: &corev1.LocalObjectReference{Name: "backend-mtls-rotated"},
+			}}
+
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.reconcilePangolinTarget(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "42", resource, targets, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uploaded).To(HaveKeyWithValue("certificate", "NEWCERT"))
+			Expect(updated).To(HaveKeyWithValue("clientCertificateId", BeEquivalentTo(8)))
+			Expect(resource.Status.ClientCertVersions).To(
+				HaveKeyWithValue("backend-mtls-rotated", secret.ResourceVersion))
+		})
+
+		It("should reject a secret without a private key", func() {
+			newSecret("backend-mtls-nokey", map[string][]byte{corev1.TLSCertKey: []byte("CERT")})
+
+			resource := &tunnelv1alpha1.PangolinResource{ObjectMeta: metav1.ObjectMeta{Name: "mtls", Namespace: "default"}}
+			targets := []tunnelv1alpha1.TargetConfig{{
+				IP: "10.0.0.1", Port: 8443, Method: "https",
+				ClientCertSecretRef: &corev1.LocalObjectReference{Name: "backend-mtls-nokey"},
+			}}
+
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.loadTargetClientCerts(context.Background(), resource, targets)
+			Expect(err).To(MatchError(ContainSubstring(`missing key "tls.key"`)))
+		})
+	})
+
+	Context("When deleting a resource", func() {
+		ctx := context.Background()
+		var (
+			server               *httptest.Server
+			deletedResources     []string
+			controllerReconciler *PangolinResourceReconciler
+		)
+
+		BeforeEach(func() {
+			deletedResources = nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Method == http.MethodDelete {
+					deletedResources = append(deletedResources, req.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true}`))
+			}))
+			DeferCleanup(server.Close)
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "resource-deletion-key", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "resource-deletion-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "resource-deletion-key"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "resource-deletion-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "resource-deletion-org"},
+				},
+			}
+			for _, obj := range []client.Object{secret, org, tunnel} {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, obj)
+			}
+
+			controllerReconciler = &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		})
+
+		// newResource creates a resource with the finalizer and the given status
+		newResource := func(name, bindingMode string, policy tunnelv1alpha1.DeletionPolicy) *tunnelv1alpha1.PangolinResource {
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  "default",
+					Finalizers: []string{ResourceFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef:      tunnelv1alpha1.LocalObjectReference{Name: "resource-deletion-tunnel"},
+					DeletionPolicy: policy,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+			resource.Status.ResourceID = "42"
+			resource.Status.BindingMode = bindingMode
+			return resource
+		}
+
+		It("should delete created resources by default", func() {
+			resource := newResource("delete-created", "Created", "")
+			_, err := controllerReconciler.handleResourceDeletion(ctx, resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedResources).To(Equal([]string{"/v1/resource/42"}))
+			Expect(resource.Finalizers).To(BeEmpty())
+		})
+
+		It("should delete the targets before the resource", func() {
+			resource := newResource("delete-targets", "Created", "")
+			resource.Status.TargetIDs = []string{"7", "8"}
+			resource.Status.TargetID = "7"
+			_, err := controllerReconciler.handleResourceDeletion(ctx, resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedResources).To(Equal([]string{
+				"/v1/resource/42/target/7",
+				"/v1/resource/42/target/8",
+				"/v1/resource/42",
+			}))
+		})
+
+		It("should tolerate targets and resources that are already deleted", func() {
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				deletedResources = append(deletedResources, req.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			})
+			resource := newResource("delete-gone", "Created", "")
+			resource.Status.TargetIDs = []string{"7"}
+			_, err := controllerReconciler.handleResourceDeletion(ctx, resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedResources).To(HaveLen(2))
+			Expect(resource.Finalizers).To(BeEmpty())
+		})
+
+		It("should retain bound resources by default", func() {
+			resource := newResource("retain-bound", "Bound", "")
+			_, err := controllerReconciler.handleResourceDeletion(ctx, resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedResources).To(BeEmpty())
+			Expect(resource.Finalizers).To(BeEmpty())
+		})
+
+		It("should delete bound resources with the Delete policy", func() {
+			resource := newResource("delete-bound", "Bound", tunnelv1alpha1.DeletionPolicyDelete)
+			_, err := controllerReconciler.handleResourceDeletion(ctx, resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedResources).To(Equal([]string{"/v1/resource/42"}))
+		})
+
+		It("should retain created resources with the Orphan policy", func() {
+			resource := newResource("orphan-created", "Created", tunnelv1alpha1.DeletionPolicyOrphan)
+			_, err := controllerReconciler.handleResourceDeletion(ctx, resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedResources).To(BeEmpty())
+			Expect(resource.Finalizers).To(BeEmpty())
+		})
+	})
+
+	Context("When creating an HTTP resource", func() {
+		It("should record the translated spec sent to Pangolin", func() {
+			var created map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if req.Method == http.MethodPut && req.URL.Path == "/v1/org/org1/resource" {
+					Expect(json.NewDecoder(req.Body).Decode(&created)).To(Succeed())
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":{}}`))
+			}))
+			defer server.Close()
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				Status: tunnelv1alpha1.PangolinOrganizationStatus{
+					Domains: []tunnelv1alpha1.Domain{{DomainID: "domain1", BaseDomain: "example.com"}},
+				},
+			}
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Name:     "app",
+					Protocol: "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{
+						Subdomain:  "app",
+						DomainName: "example.com",
+						Port:       8443,
+					},
+				},
+			}
+
+			_, err := (&PangolinResourceReconciler{}).reconcilePangolinResource(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", "3", resource, org)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(created).To(HaveKeyWithValue("protocol", "tcp"))
+			Expect(resource.Status.AppliedSpec).To(Equal(&tunnelv1alpha1.AppliedResourceSpec{
+				Protocol:  "tcp",
+				HTTP:      true,
+				Subdomain: "app",
+				DomainID:  "domain1",
+				ProxyPort: 8443,
+			}))
+		})
+	})
+
+	Context("When the organization is paused with pauseChildren", func() {
+		It("should not call the Pangolin API for dependent resources", func() {
+			ctx := context.Background()
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			pauseChildren := true
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "paused-org",
+					Namespace:   "default",
+					Annotations: map[string]string{PausedAnnotation: "true"},
+				},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint:   server.URL,
+					PauseChildren: &pauseChildren,
+				},
+			}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "paused-org-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "paused-org"},
+				},
+			}
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "paused-org-resource",
+					Namespace:  "default",
+					Finalizers: []string{ResourceFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef: tunnelv1alpha1.LocalObjectReference{Name: "paused-org-tunnel"},
+					Protocol:  "tcp",
+				},
+			}
+			for _, obj := range []client.Object{org, tunnel, resource} {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, obj)
+			}
+			tunnel.Status.Status = "Ready"
+			Expect(k8sClient.Status().Update(ctx, tunnel)).To(Succeed())
+
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(resource)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests).To(BeZero())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, PausedCondition)).To(BeTrue())
+
+			By("resuming once the organization is unpaused")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(org), org)).To(Succeed())
+			org.Annotations = nil
+			Expect(k8sClient.Update(ctx, org)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(resource)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, PausedCondition)).To(BeNil())
+		})
+	})
+
+	Context("When a paused resource is deleted", func() {
+		It("should keep the finalizer without deleting it from Pangolin", func() {
+			ctx := context.Background()
+			var deletes int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Method == http.MethodDelete {
+					deletes++
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{}}`))
+			}))
+			defer server.Close()
+
+			pauseChildren := true
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "paused-delete-credentials", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "paused-delete-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "paused-delete-credentials"},
+						Key:                  "apiKey",
+					},
+					PauseChildren: &pauseChildren,
+				},
+			}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "paused-delete-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "paused-delete-org"},
+				},
+			}
+			for _, obj := range []client.Object{secret, org, tunnel} {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, obj)
+			}
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "paused-delete-resource",
+					Namespace:   "default",
+					Annotations: map[string]string{PausedAnnotation: "true"},
+					Finalizers:  []string{ResourceFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef: tunnelv1alpha1.LocalObjectReference{Name: "paused-delete-tunnel"},
+					Protocol:  "tcp",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			resource.Status.ResourceID = "42"
+			resource.Status.BindingMode = "Created"
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			key := client.ObjectKeyFromObject(resource)
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletes).To(BeZero())
+			Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(ResourceFinalizerName))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, PausedCondition)).To(BeTrue())
+
+			By("keeping it while the organization pauses its children")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(org), org)).To(Succeed())
+			org.Annotations = map[string]string{PausedAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, org)).To(Succeed())
+			resource.Annotations = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletes).To(BeZero())
+			Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(ResourceFinalizerName))
+
+			By("deleting it from Pangolin once resumed")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(org), org)).To(Succeed())
+			org.Annotations = nil
+			Expect(k8sClient.Update(ctx, org)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletes).NotTo(BeZero())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, key, resource))).To(BeTrue())
+		})
+	})
+
+	Context("When the resolved domain is removed from the organization", func() {
+		It("should move the resource to the default domain", func() {
+			var updated map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.URL.Path == "/v1/org/org1/domains":
+					// domain1 was removed, only domain2 is left
+					_, _ = w.Write([]byte(`{"success":true,"data":{"domains":[
+						{"domainId":"domain2","baseDomain":"example.org","verified":true}
+					]}}`))
+				case req.Method == http.MethodPost && req.URL.Path == "/v1/resource/42":
+					Expect(json.NewDecoder(req.Body).Decode(&updated)).To(Succeed())
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "org"},
+				Status: tunnelv1alpha1.PangolinOrganizationStatus{
+					OrganizationID:  "org1",
+					Domains:         []tunnelv1alpha1.Domain{{DomainID: "domain1", BaseDomain: "example.com", Verified: true}},
+					DefaultDomainID: "domain1",
+				},
+			}
+			Expect((&PangolinOrganizationReconciler{}).reconcileDomains(context.Background(), org, apiClient)).To(Succeed())
+			Expect(org.Status.DefaultDomainID).To(Equal("domain2"))
+
+			recorder := record.NewFakeRecorder(10)
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app"},
+				},
+				Status: tunnelv1alpha1.PangolinResourceStatus{
+					ResourceID:       "42",
+					BindingMode:      "Created",
+					ResolvedDomainID: "domain1",
+					FullDomain:       "app.example.com",
+				},
+			}
+
+			err := (&PangolinResourceReconciler{Recorder: recorder}).reconcileResourceDomain(context.Background(), apiClient, resource, org)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resource.Status.ResolvedDomainID).To(Equal("domain2"))
+			Expect(resource.Status.FullDomain).To(Equal("app.example.org"))
+			Expect(updated).To(HaveKeyWithValue("domainId", "domain2"))
+			condition := meta.FindStatusCondition(resource.Status.Conditions, DomainRemovedCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("Reresolved"))
+			Expect(<-recorder.Events).To(ContainSubstring("Warning DomainRemoved"))
+		})
+
+		It("should report the removal when no other domain resolves", func() {
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "org"},
+				Status: tunnelv1alpha1.PangolinOrganizationStatus{
+					Domains: []tunnelv1alpha1.Domain{{DomainID: "domain2", BaseDomain: "example.org"}},
+				},
+			}
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app", DomainName: "example.com"},
+				},
+				Status: tunnelv1alpha1.PangolinResourceStatus{ResolvedDomainID: "domain1"},
+			}
+
+			err := (&PangolinResourceReconciler{}).reconcileResourceDomain(context.Background(), nil, resource, org)
+			Expect(err).To(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, DomainRemovedCondition)).To(BeTrue())
+		})
+	})
+
+	Context("When reporting what a reconcile changed in Pangolin", func() {
+		It("should record Created on the first reconcile and NoOp on an unchanged one", func() {
+			ctx := context.Background()
+			var targets []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/org/org1/resource":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/resource/42/target":
+					targets = append(targets, `{"targetId":7,"siteId":3,"ip":"10.0.0.1","port":5432,"method":"tcp","enabled":true}`)
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targetId":7}}`))
+				case req.Method == http.MethodGet && req.URL.Path == "/v1/resource/42/targets":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targets":[` + strings.Join(targets, ",") + `]}}`))
+				case req.Method == http.MethodGet && req.URL.Path == "/v1/resource/42":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42,"http":false,"protocol":"tcp","proxyPort":5432}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "action-credentials", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "action-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "action-credentials"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "action-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "action-org"},
+				},
+			}
+			enableProxy := true
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "action-resource",
+					Namespace:  "default",
+					Finalizers: []string{ResourceFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef:   tunnelv1alpha1.LocalObjectReference{Name: "action-tunnel"},
+					Name:        "db",
+					Protocol:    "tcp",
+					ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 5432, EnableProxy: &enableProxy},
+					Targets:     []tunnelv1alpha1.TargetConfig{{IP: "10.0.0.1", Port: 5432}},
+				},
+			}
+			for _, obj := range []client.Object{secret, org, tunnel, resource} {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, obj)
+			}
+			org.Status.Status = "Ready"
+			org.Status.OrganizationID = "org1"
+			Expect(k8sClient.Status().Update(ctx, org)).To(Succeed())
+			tunnel.Status.Status = "Ready"
+			tunnel.Status.SiteID = 3
+			Expect(k8sClient.Status().Update(ctx, tunnel)).To(Succeed())
+
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			reconcileAction := func() tunnelv1alpha1.ReconcileAction {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(resource)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resource), resource)).To(Succeed())
+				Expect(resource.Status.Status).To(Equal("Ready"))
+				return resource.Status.LastAction
+			}
+
+			Expect(reconcileAction()).To(Equal(tunnelv1alpha1.ReconcileActionCreated))
+			Expect(targets).To(HaveLen(1))
+
+			By("reconciling again without changes")
+			Expect(reconcileAction()).To(Equal(tunnelv1alpha1.ReconcileActionNoOp))
+			Expect(targets).To(HaveLen(1))
+		})
+	})
+
+	Context("When a resource sets a sticky session", func() {
+		It("should apply it once", func() {
+			var updates []map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/resource/42"))
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				updates = append(updates, body)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+			}))
+			defer server.Close()
+
+			sticky := true
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{StickySession: &sticky},
+			}
+			controllerReconciler := &PangolinResourceReconciler{}
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			Expect(controllerReconciler.updateResourceStickySession(context.Background(), apiClient, "42", resource)).To(Succeed())
+			Expect(updates).To(Equal([]map[string]interface{}{{"stickySession": true}}))
+			Expect(resource.Status.StickySession).To(BeTrue())
+
+			By("not sending it again once applied")
+			Expect(controllerReconciler.updateResourceStickySession(context.Background(), apiClient, "42", resource)).To(Succeed())
+			Expect(updates).To(HaveLen(1))
+		})
+	})
+
+	Context("When a full sync is triggered", func() {
+		It("should enqueue every managed resource", func() {
+			ctx := context.Background()
+			names := []string{"full-sync-a", "full-sync-b", "full-sync-other-class"}
+			for _, name := range names {
+				resource := &tunnelv1alpha1.PangolinResource{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec: tunnelv1alpha1.PangolinResourceSpec{
+						Name:     name,
+						Protocol: "http",
+					},
+				}
+				if name == "full-sync-other-class" {
+					resource.Labels = map[string]string{ClassLabel: "other"}
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, resource)
+			}
+
+			fullSync := &FullSync{Client: k8sClient, Interval: time.Hour}
+			queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer queue.ShutDown()
+			src := fullSync.Source(&tunnelv1alpha1.PangolinResourceList{}, classPredicate(""))
+			Expect(src.Start(ctx, queue)).To(Succeed())
+
+			Expect(fullSync.Trigger(ctx)).To(Succeed())
+
+			var enqueued []string
+			Eventually(func() []string {
+				for queue.Len() > 0 {
+					req, _ := queue.Get()
+					enqueued = append(enqueued, req.Name)
+					queue.Done(req)
+				}
+				return enqueued
+			}).Should(ContainElements("full-sync-a", "full-sync-b"))
+			Consistently(func() []string { return enqueued }, "100ms").ShouldNot(ContainElement("full-sync-other-class"))
+		})
+	})
+
+	Context("When building the proxy endpoint of a TCP resource", func() {
+		newServer := func(data string) *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/resource/42"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":` + data + `}`))
+			}))
+		}
+		resource := &tunnelv1alpha1.PangolinResource{
+			Spec: tunnelv1alpha1.PangolinResourceSpec{
+				Protocol:    "tcp",
+				ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 25565},
+			},
+		}
+		tunnel := &tunnelv1alpha1.PangolinTunnel{
+			Status: tunnelv1alpha1.PangolinTunnelStatus{Endpoint: "203.0.113.1:51820"},
+		}
+
+		It("should prefer the proxy address assigned by the API", func() {
+			server := newServer(`{"resourceId":42,"proxyAddress":"relay.example.com"}`)
+			defer server.Close()
+
+			controllerReconciler := &PangolinResourceReconciler{}
+			endpoint := controllerReconciler.resolveProxyEndpoint(context.Background(),
+				pangolin.NewClient(server.URL, "token"), &pangolin.Resource{ID: "42"}, resource, tunnel)
+			Expect(endpoint).To(Equal("relay.example.com:25565"))
+		})
+
+		It("should fall back to the tunnel endpoint", func() {
+			server := newServer(`{"resourceId":42}`)
+			defer server.Close()
+
+			controllerReconciler := &PangolinResourceReconciler{}
+			endpoint := controllerReconciler.resolveProxyEndpoint(context.Background(),
+				pangolin.NewClient(server.URL, "token"), &pangolin.Resource{ID: "42"}, resource, tunnel)
+			Expect(endpoint).To(Equal("203.0.113.1:25565"))
+		})
+	})
+
+	Context("When the spec of a created resource changes", func() {
+		var updates []map[string]interface{}
+		newServer := func(current string) *httptest.Server {
+			updates = nil
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if req.URL.Path == "/v1/org/org1/resources" {
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resources":[` + current + `]}}`))
+					return
+				}
+				Expect(req.URL.Path).To(Equal("/v1/resource/42"))
+				if req.Method == http.MethodPost {
+					var body map[string]interface{}
+					Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+					updates = append(updates, body)
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":` + current + `}`))
+			}))
+		}
+		httpResource := func(subdomain string) *tunnelv1alpha1.PangolinResource {
+			return &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: subdomain},
+				},
+				Status: tunnelv1alpha1.PangolinResourceStatus{
+					ResourceID:       "42",
+					ResolvedDomainID: "d1",
+					LastAction:       tunnelv1alpha1.ReconcileActionNoOp,
+				},
+			}
+		}
+
+		It("should update a changed subdomain in place", func() {
+			server := newServer(`{"resourceId":42,"http":true,"protocol":"tcp","subdomain":"app","domainId":"d1"}`)
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			resource := httpResource("app")
+			_, err := (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(), apiClient, "org1", resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updates).To(BeEmpty())
+			Expect(resource.Status.LastAction).To(Equal(tunnelv1alpha1.ReconcileActionNoOp))
+
+			resource = httpResource("web")
+			_, err = (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(), apiClient, "org1", resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updates).To(Equal([]map[string]interface{}{{"subdomain": "web"}}))
+			Expect(resource.Status.LastAction).To(Equal(tunnelv1alpha1.ReconcileActionUpdated))
+		})
+
+		It("should update a changed proxy port in place", func() {
+			server := newServer(`{"resourceId":42,"http":false,"protocol":"tcp","proxyPort":5432}`)
+			defer server.Close()
+
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol:    "tcp",
+					ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 5433},
+				},
+				Status: tunnelv1alpha1.PangolinResourceStatus{ResourceID: "42"},
+			}
+			_, err := (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updates).To(Equal([]map[string]interface{}{{"proxyPort": float64(5433)}}))
+		})
+
+		It("should recreate a resource deleted in Pangolin", func() {
+			var created int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.URL.Path == "/v1/resource/42":
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"success":false,"message":"Resource not found"}`))
+				case req.URL.Path == "/v1/org/org1/resources":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resources":[]}}`))
+				case req.Method == http.MethodPut:
+					created++
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":43,"protocol":"tcp","proxyPort":5432}}`))
+				default:
+					_, _ = w.Write([]byte(`{"success":true}`))
+				}
+			}))
+			defer server.Close()
+
+			recorder := record.NewFakeRecorder(10)
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Name:        "db",
+					Protocol:    "tcp",
+					ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 5432},
+				},
+				Status: tunnelv1alpha1.PangolinResourceStatus{
+					ResourceID:  "42",
+					BindingMode: "Created",
+					TargetIDs:   []string{"7"},
+					TargetCount: 1,
+				},
+			}
+			pRes, err := (&PangolinResourceReconciler{Recorder: recorder}).reconcilePangolinResource(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", "1", resource, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(Equal(1))
+			Expect(pRes.EffectiveID()).To(Equal("43"))
+			Expect(resource.Status.TargetIDs).To(BeEmpty())
+			Expect(resource.Status.BindingMode).To(Equal("Created"))
+			Expect(recorder.Events).To(Receive(Equal("Warning ResourceDeleted Resource 42 was deleted in Pangolin, recreating it")))
+		})
+
+		It("should reject switching between http and tcp", func() {
+			server := newServer(`{"resourceId":42,"http":false,"protocol":"tcp","proxyPort":5432}`)
+			defer server.Close()
+
+			_, err := (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", httpResource("app"))
+			Expect(err).To(MatchError(ContainSubstring("protocol cannot be changed from tcp to http")))
+			Expect(updates).To(BeEmpty())
+		})
+
+		It("should reset tags changed in Pangolin and leave them alone without spec.tags", func() {
+			server := newServer(`{"resourceId":42,"http":true,"protocol":"tcp","subdomain":"app","domainId":"d1",` +
+				`"tags":{"team":"web","owner":"someone"}}`)
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			_, err := (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(), apiClient, "org1",
+				httpResource("app"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updates).To(BeEmpty())
+
+			resource := httpResource("app")
+			resource.Spec.Tags = map[string]string{"team": "web", "env": "prod"}
+			_, err = (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(), apiClient, "org1", resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updates).To(Equal([]map[string]interface{}{
+				{"tags": map[string]interface{}{"team": "web", "env": "prod"}},
+			}))
+
+			By("removing all tags for an empty spec.tags")
+			updates = nil
+			resource.Spec.Tags = map[string]string{}
+			_, err = (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(), apiClient, "org1", resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updates).To(Equal([]map[string]interface{}{{"tags": map[string]interface{}{}}}))
+		})
+
+		It("should fail when Pangolin rejects the tags", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if req.Method == http.MethodPost {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"success":false,"message":"Unknown tag cost-center"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42,"http":true,"protocol":"tcp",` +
+					`"subdomain":"app","domainId":"d1"}}`))
+			}))
+			defer server.Close()
+
+			resource := httpResource("app")
+			resource.Spec.Tags = map[string]string{"cost-center": "42"}
+			_, err := (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", resource)
+			Expect(err).To(MatchError(ContainSubstring("failed to update Pangolin resource 42, check spec.tags")))
+			Expect(err).To(MatchError(ContainSubstring("Unknown tag cost-center")))
+		})
+	})
+
+	Context("When Pangolin cannot change the domain of a resource in place", func() {
+		var requests []string
+		BeforeEach(func() {
+			requests = nil
+		})
+		newServer := func() *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				requests = append(requests, req.Method+" "+req.URL.Path)
+				switch req.Method {
+				case http.MethodGet:
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42,"http":true,"protocol":"tcp",` +
+						`"subdomain":"app","domainId":"d1"}}`))
+				case http.MethodPost:
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					_, _ = w.Write([]byte(`{"success":false,"message":"Cannot change the domain of a resource"}`))
+				default:
+					_, _ = w.Write([]byte(`{"success":true,"data":{}}`))
+				}
+			}))
+		}
+		movedResource := func(annotations map[string]string) *tunnelv1alpha1.PangolinResource {
+			return &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "web"},
+				},
+				Status: tunnelv1alpha1.PangolinResourceStatus{
+					ResourceID:         "42",
+					ResolvedDomainID:   "d1",
+					BindingMode:        "Created",
+					TargetIDs:          []string{"7", "8"},
+					AuthSecretVersions: "password=auth/password@1",
+				},
+			}
+		}
+
+		It("should require the recreate annotation", func() {
+			server := newServer()
+			defer server.Close()
+
+			resource := movedResource(nil)
+			_, err := (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", resource)
+			Expect(err).To(MatchError(ContainSubstring("annotate it with pangolin.io/allow-recreate=true")))
+			Expect(resource.Status.ResourceID).To(Equal("42"))
+			Expect(resource.Status.MigratingFrom).To(BeNil())
+
+			By("keeping resources their deletion policy retains")
+			resource = movedResource(map[string]string{RecreateAnnotation: "true"})
+			resource.Status.BindingMode = "Adopted"
+			_, err = (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", resource)
+			Expect(err).To(MatchError(ContainSubstring("deletionPolicy Retain keeps it")))
+		})
+
+		It("should recreate an annotated resource and delete the replaced one", func() {
+			server := newServer()
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			resource := movedResource(map[string]string{RecreateAnnotation: "true"})
+			_, err := (&PangolinResourceReconciler{}).reconcileResourceDrift(context.Background(), apiClient, "org1", resource)
+			Expect(err).To(Equal(errMigrationStarted))
+			Expect(resource.Status.MigratingFrom).To(Equal(&tunnelv1alpha1.ResourceMigration{
+				ResourceID: "42", TargetIDs: []string{"7", "8"},
+			}))
+			Expect(resource.Status.ResourceID).To(BeEmpty())
+			Expect(resource.Status.TargetIDs).To(BeEmpty())
+			Expect(resource.Status.AuthSecretVersions).To(BeEmpty())
+
+			By("deleting the replaced resource once its replacement is configured")
+			requests = nil
+			resource.Status.ResourceID = "43"
+			recorder := record.NewFakeRecorder(10)
+			Expect((&PangolinResourceReconciler{Recorder: recorder}).finishResourceMigration(
+				context.Background(), apiClient, resource)).To(Succeed())
+			Expect(requests).To(Equal([]string{
+				"DELETE /v1/resource/42/target/7", "DELETE /v1/resource/42/target/8", "DELETE /v1/resource/42",
+			}))
+			Expect(resource.Status.MigratingFrom).To(BeNil())
+			Expect(recorder.Events).To(Receive(ContainSubstring("Resource 42 replaced by resource 43")))
+		})
+	})
+
+	Context("When a target of the spec changes", func() {
+		var target pangolin.Target
+		var calls []string
+		var updates []map[string]interface{}
+		newServer := func() *httptest.Server {
+			target = pangolin.Target{TargetID: 7, SiteID: 3, IP: "10.0.0.1", Port: 5432, Method: "tcp", Enabled: true}
+			calls, updates = nil, nil
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.Method == http.MethodGet && req.URL.Path == "/v1/resource/42/targets":
+					data, _ := json.Marshal(target)
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targets":[` + string(data) + `]}}`))
+				case req.Method == http.MethodPost && req.URL.Path == "/v1/resource/42/target/7":
+					var body map[string]interface{}
+					Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+					updates = append(updates, body)
+					target.Port = int32(body["port"].(float64))
+					target.Enabled = body["enabled"].(bool)
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targetId":7}}`))
+				default:
+					calls = append(calls, req.Method+" "+req.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+		}
+		reconcileTargets := func(server *httptest.Server, desired tunnelv1alpha1.TargetConfig) *tunnelv1alpha1.PangolinResource {
+			resource := &tunnelv1alpha1.PangolinResource{}
+			desired.Method = "tcp"
+			ids, err := (&PangolinResourceReconciler{}).reconcilePangolinTarget(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "42", resource, []tunnelv1alpha1.TargetConfig{desired}, "3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]string{"7"}))
+			return resource
+		}
+
+		It("should update a changed port in place", func() {
+			server := newServer()
+			defer server.Close()
+
+			resource := reconcileTargets(server, tunnelv1alpha1.TargetConfig{IP: "10.0.0.1", Port: 5433})
+			Expect(updates).To(Equal([]map[string]interface{}{
+				{"ip": "10.0.0.1", "port": float64(5433), "method": "tcp", "enabled": true},
+			}))
+			Expect(calls).To(BeEmpty())
+			Expect(resource.Status.LastAction).To(Equal(tunnelv1alpha1.ReconcileActionUpdated))
+		})
+
+		It("should disable a target without deleting it", func() {
+			server := newServer()
+			defer server.Close()
+
+			disabled := false
+			reconcileTargets(server, tunnelv1alpha1.TargetConfig{IP: "10.0.0.1", Port: 5432, Enabled: &disabled})
+			Expect(updates).To(HaveLen(1))
+			Expect(updates[0]).To(HaveKeyWithValue("enabled", false))
+			Expect(calls).To(BeEmpty())
+
+			By("leaving it alone once disabled")
+			reconcileTargets(server, tunnelv1alpha1.TargetConfig{IP: "10.0.0.1", Port: 5432, Enabled: &disabled})
+			Expect(updates).To(HaveLen(1))
+		})
+
+		It("should configure a health check and report the target health", func() {
+			server := newServer()
+			defer server.Close()
+
+			expected := int32(204)
+			desired := tunnelv1alpha1.TargetConfig{IP: "10.0.0.1", Port: 5432, HealthCheck: &tunnelv1alpha1.TargetHealthCheck{
+				Path: "/healthz", ExpectedStatus: &expected,
+			}}
+			resource := reconcileTargets(server, desired)
+			Expect(updates).To(HaveLen(1))
+			Expect(updates[0]).To(HaveKeyWithValue("hcEnabled", true))
+			Expect(updates[0]).To(HaveKeyWithValue("hcPath", "/healthz"))
+			Expect(updates[0]).To(HaveKeyWithValue("hcInterval", float64(30)))
+			Expect(updates[0]).To(HaveKeyWithValue("hcTimeout", float64(5)))
+			Expect(updates[0]).To(HaveKeyWithValue("hcStatus", float64(204)))
+			Expect(updates[0]).To(HaveKeyWithValue("hcHostname", "10.0.0.1"))
+			Expect(calls).To(BeEmpty())
+			Expect(resource.Status.TargetHealth).To(BeNil())
+
+			By("leaving it alone once configured and reporting the health")
+			target.HCEnabled, target.HCPath, target.HCInterval, target.HCTimeout = true, "/healthz", 30, 5
+			target.HCStatus, target.HCHealth = &expected, "unhealthy"
+			resource = reconcileTargets(server, desired)
+			Expect(updates).To(HaveLen(1))
+			Expect(resource.Status.TargetHealth).To(Equal(map[string]string{"10.0.0.1:5432": "unhealthy"}))
+
+			By("turning it off")
+			disabled := false
+			desired.HealthCheck.Enabled = &disabled
+			reconcileTargets(server, desired)
+			Expect(updates).To(HaveLen(2))
+			Expect(updates[1]).To(HaveKeyWithValue("hcEnabled", false))
+			Expect(updates[1]).NotTo(HaveKey("hcPath"))
+		})
+	})
+
+	Context("When a resource waits for healthy targets", func() {
+		It("should list the enabled targets whose health check is not healthy yet", func() {
+			disabled, wait := false, true
+			targets := []tunnelv1alpha1.TargetConfig{
+				{IP: "10.0.0.1", Port: 8080},
+				{IP: "10.0.0.2", Port: 8080},
+				{IP: "10.0.0.3", Port: 8080, Enabled: &disabled},
+				{IP: "10.0.0.4", Port: 8080},
+			}
+			resource := &tunnelv1alpha1.PangolinResource{
+				Status: tunnelv1alpha1.PangolinResourceStatus{TargetHealth: map[string]string{
+					"10.0.0.1:8080": "healthy",
+					"10.0.0.2:8080": "unknown",
+					"10.0.0.3:8080": "unhealthy",
+				}},
+			}
+			Expect(unhealthyTargets(resource, targets)).To(BeNil())
+
+			resource.Spec.WaitForHealthy = &wait
+			Expect(unhealthyTargets(resource, targets)).To(Equal([]string{"10.0.0.2:8080 (unknown)"}))
+
+			By("marking the resource Ready once all of them report healthy")
+			resource.Status.TargetHealth["10.0.0.2:8080"] = "healthy"
+			Expect(unhealthyTargets(resource, targets)).To(BeEmpty())
+		})
+	})
+
+	Context("When retrying a resource in Error", func() {
+		It("should back off up to the cap and start over when the spec changes", func() {
+			backoff := newErrorBackoff(0, time.Minute, 0)
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default", Generation: 1},
+			}
+
+			var intervals []time.Duration
+			for range 4 {
+				intervals = append(intervals, backoff.RequeueAfter(resource, "Error"))
+			}
+			Expect(intervals).To(Equal([]time.Duration{15 * time.Second, 30 * time.Second, time.Minute, time.Minute}))
+			Expect(backoff.RequeueAfter(resource, "Waiting")).To(Equal(time.Minute))
+
+			By("editing the spec mid-backoff")
+			resource.Generation = 2
+			Expect(backoff.RequeueAfter(resource, "Error")).To(Equal(15 * time.Second))
+
+			By("resetting once the resource is ready")
+			Expect(backoff.RequeueAfter(resource, "Error")).To(Equal(30 * time.Second))
+			Expect(backoff.RequeueAfter(resource, "Ready")).To(BeZero())
+			Expect(backoff.RequeueAfter(resource, "Error")).To(Equal(15 * time.Second))
+		})
+
+		It("should use the configured error requeue and resync intervals", func() {
+			backoff := newErrorBackoff(time.Minute, 5*time.Minute, 30*time.Minute)
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "tuned", Namespace: "default", Generation: 1},
+			}
+
+			Expect(backoff.RequeueAfter(resource, "Error")).To(Equal(time.Minute))
+			Expect(backoff.RequeueAfter(resource, "Error")).To(Equal(2 * time.Minute))
+			Expect(backoff.RequeueAfter(resource, "Ready")).To(Equal(30 * time.Minute))
+			Expect(backoff.RequeueAfter(resource, "Suspended")).To(Equal(30 * time.Minute))
+			Expect(backoff.RequeueAfter(resource, "Error")).To(Equal(time.Minute))
+
+			By("keeping the first retry within the cap")
+			Expect(newErrorBackoff(time.Hour, 5*time.Minute, 0).RequeueAfter(resource, "Error")).To(Equal(5 * time.Minute))
+		})
+
+		It("should wait out the Retry-After of a rate limited request, up to the cap", func() {
+			retryAfter := "120"
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				requests++
+				w.Header().Set("Retry-After", retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			_, err := apiClient.GetResource(context.Background(), "42")
+			Expect(pangolin.IsRateLimited(err)).To(BeTrue())
+			Expect(pangolin.RetryAfter(err)).To(Equal(2 * time.Minute))
+			Expect(requests).To(Equal(1), "waits longer than a request may take are left to the requeue")
+
+			backoff := newErrorBackoff(0, 10*time.Minute, 0)
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "throttled", Namespace: "default", Generation: 1},
+			}
+			backoff.ObserveError(resource, err)
+			Expect(backoff.RequeueAfter(resource, "Error")).To(Equal(2 * time.Minute))
+			Expect(backoff.RequeueAfter(resource, "Error")).To(Equal(30*time.Second), "the Retry-After applies once")
+
+			By("capping a misbehaving Retry-After")
+			retryAfter = "86400"
+			_, err = apiClient.GetResource(context.Background(), "42")
+			backoff.ObserveError(resource, err)
+			Expect(backoff.RequeueAfter(resource, "Error")).To(Equal(10 * time.Minute))
+		})
+	})
+
+	Context("When a target uses method auto", func() {
+		It("should use https for TLS backends and http otherwise, probing each backend once", func() {
+			var probes []string
+			probe := func(ctx context.Context, address string) (string, bool) {
+				probes = append(probes, address)
+				switch address {
+				case "10.0.0.1:443":
+					return "https", true
+				case "10.0.0.2:8080":
+					return "http", true
+				default:
+					return "", false
+				}
+			}
+			targets := func() []tunnelv1alpha1.TargetConfig {
+				return []tunnelv1alpha1.TargetConfig{
+					{IP: "10.0.0.1", Port: 443, Method: TargetMethodAuto},
+					{IP: "10.0.0.2", Port: 8080, Method: TargetMethodAuto},
+					{IP: "10.0.0.3", Port: 8443, Method: TargetMethodAuto},
+					{IP: "10.0.0.4", Port: 80, Method: "http"},
+				}
+			}
+			resource := &tunnelv1alpha1.PangolinResource{}
+
+			resolved := targets()
+			resolveAutoTargetMethods(context.Background(), probe, resource, resolved)
+			Expect([]string{resolved[0].Method, resolved[1].Method, resolved[2].Method, resolved[3].Method}).
+				To(Equal([]string{"https", "http", "http", "http"}))
+			Expect(resource.Status.ProbedTargetMethods).To(Equal(map[string]string{
+				"10.0.0.1:443":  "https",
+				"10.0.0.2:8080": "http",
+			}))
+
+			By("reusing cached results and probing inconclusive backends again")
+			probes = nil
+			resolved = targets()
+			resolveAutoTargetMethods(context.Background(), probe, resource, resolved)
+			Expect(resolved[0].Method).To(Equal("https"))
+			Expect(probes).To(Equal([]string{"10.0.0.3:8443"}))
+		})
+	})
+	Context("When a resource with the same subdomain already exists in Pangolin", func() {
+		It("should adopt it and report the adoption", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/v1/org/org1/resource":
+					w.WriteHeader(http.StatusConflict)
+					_, _ = w.Write([]byte(`{"success":false,"message":"Resource already exists"}`))
+				case "/v1/org/org1/resources":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resources":[{"resourceId":77,"name":"app","subdomain":"app","domainId":"domain1"}]}}`))
+				case "/v1/resource/77":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":77,"name":"app","http":true,"protocol":"tcp","subdomain":"app","domainId":"domain1"}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				Status: tunnelv1alpha1.PangolinOrganizationStatus{
+					Domains: []tunnelv1alpha1.Domain{{DomainID: "domain1", BaseDomain: "example.com"}},
+				},
+			}
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Name:       "app",
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app", DomainName: "example.com"},
+				},
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &PangolinResourceReconciler{Recorder: recorder}
+			apiClient := pangolin.NewClient(server.URL, "token")
+			pRes, err := reconciler.reconcilePangolinResource(context.Background(), apiClient, "org1", "3", resource, org)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pRes.EffectiveID()).To(Equal("77"))
+			Expect(resource.Status.BindingMode).To(Equal("Adopted"))
+			Expect(resource.Status.LastAction).To(Equal(tunnelv1alpha1.ReconcileActionAdopted))
+			Expect(recorder.Events).To(Receive(Equal("Normal Adopted Adopted existing Pangolin resource 77")))
+
+			By("keeping the binding mode once the resource ID is in status")
+			resource.Status.ResourceID = "77"
+			_, err = reconciler.reconcilePangolinResource(context.Background(), apiClient, "org1", "3", resource, org)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resource.Status.BindingMode).To(Equal("Adopted"))
+			Expect(effectiveDeletionPolicy(resource.Spec.DeletionPolicy, resource.Status.BindingMode)).
+				To(Equal(tunnelv1alpha1.DeletionPolicyRetain))
+		})
+
+		It("should adopt a matching resource without trying to create a duplicate", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.Method).NotTo(Equal(http.MethodPut), "unexpected create %s", req.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				if req.Method == http.MethodPost {
+					_, _ = w.Write([]byte(`{"success":true,"data":{}}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":{"resources":[` +
+					`{"resourceId":77,"name":"app","http":true,"protocol":"tcp","subdomain":"app","domainId":"domain2"},` +
+					`{"resourceId":78,"name":"app","http":true,"protocol":"tcp","subdomain":"app","domainId":"domain1"},` +
+					`{"resourceId":79,"name":"db","http":false,"protocol":"tcp","proxyPort":5433},` +
+					`{"resourceId":80,"name":"db","http":false,"protocol":"tcp","proxyPort":5432}]}}`))
+			}))
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+			reconciler := &PangolinResourceReconciler{Client: k8sClient, Recorder: record.NewFakeRecorder(10)}
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				Status: tunnelv1alpha1.PangolinOrganizationStatus{
+					Domains: []tunnelv1alpha1.Domain{{DomainID: "domain1", BaseDomain: "example.com"}},
+				},
+			}
+			httpResource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Name:       "app",
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app", DomainName: "example.com"},
+				},
+			}
+			pRes, err := reconciler.reconcilePangolinResource(context.Background(), apiClient, "org1", "3", httpResource, org)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pRes.EffectiveID()).To(Equal("78"))
+			Expect(httpResource.Status.BindingMode).To(Equal("Adopted"))
+
+			enableProxy := true
+			tcpResource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Name:        "db",
+					Protocol:    "tcp",
+					ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 5432, EnableProxy: &enableProxy},
+				},
+			}
+			pRes, err = reconciler.reconcilePangolinResource(context.Background(), apiClient, "org1", "3", tcpResource, org)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pRes.EffectiveID()).To(Equal("80"))
+			Expect(tcpResource.Status.BindingMode).To(Equal("Adopted"))
+			Expect(tcpResource.Status.LastAction).To(Equal(tunnelv1alpha1.ReconcileActionAdopted))
+		})
+	})
+	Context("When a resource has a schedule", func() {
+		workHours := &tunnelv1alpha1.ResourceSchedule{
+			Windows: []tunnelv1alpha1.ScheduleWindow{{Start: "0 8 * * 1-5", End: "0 18 * * mon-fri"}},
+		}
+		// Monday 2026-10-19
+		at := func(day, hour, minute int) time.Time {
+			return time.Date(2026, time.October, day, hour, minute, 0, 0, time.UTC)
+		}
+
+		It("should compute the active state and the next boundary", func() {
+			for _, tc := range []struct {
+				now    time.Time
+				active bool
+				next   time.Time
+			}{
+				{now: at(19, 7, 30), active: false, next: at(19, 8, 0)},
+				{now: at(19, 8, 0), active: true, next: at(19, 18, 0)},
+				{now: at(19, 12, 0), active: true, next: at(19, 18, 0)},
+				{now: at(23, 18, 0), active: false, next: at(26, 8, 0)},
+				{now: at(24, 12, 0), active: false, next: at(26, 8, 0)},
+			} {
+				active, next, err := evaluateSchedule(workHours, tc.now)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(active).To(Equal(tc.active), "at %s", tc.now)
+				Expect(next).To(Equal(tc.next), "at %s", tc.now)
+			}
+
+			_, _, err := evaluateSchedule(&tunnelv1alpha1.ResourceSchedule{
+				Windows: []tunnelv1alpha1.ScheduleWindow{{Start: "0 25 * * *", End: "0 18 * * *"}},
+			}, at(19, 12, 0))
+			Expect(err).To(MatchError(ContainSubstring(`invalid value "25" in hour field`)))
+		})
+
+		It("should toggle the resource at the window boundaries and requeue for the next one", func() {
+			var updates []map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/resource/42"))
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				updates = append(updates, body)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+			}))
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &PangolinResourceReconciler{Recorder: recorder}
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{Schedule: workHours},
+			}
+			target := tunnelv1alpha1.TargetConfig{IP: "10.0.0.1", Port: 80}
+
+			By("disabling the resource before work hours")
+			Expect(reconciler.reconcileResourceSchedule(context.Background(), apiClient, "42", resource, at(19, 7, 30))).To(Succeed())
+			Expect(updates).To(Equal([]map[string]interface{}{{"enabled": false}}))
+			Expect(*resource.Status.ScheduleActive).To(BeFalse())
+			Expect(targetEnabled(resource, target)).To(BeFalse())
+			Expect(scheduleRequeueAfter(resource, at(19, 7, 30))).To(Equal(30 * time.Minute))
+			Expect(recorder.Events).To(Receive(Equal("Normal ScheduleClosed Resource disabled by schedule until 2026-10-19T08:00:00Z")))
+
+			By("leaving the resource alone while the window stays closed")
+			Expect(reconciler.reconcileResourceSchedule(context.Background(), apiClient, "42", resource, at(19, 7, 45))).To(Succeed())
+			Expect(updates).To(HaveLen(1))
+
+			By("enabling the resource when the window opens")
+			Expect(reconciler.reconcileResourceSchedule(context.Background(), apiClient, "42", resource, at(19, 8, 0))).To(Succeed())
+			Expect(updates).To(Equal([]map[string]interface{}{{"enabled": false}, {"enabled": true}}))
+			Expect(targetEnabled(resource, target)).To(BeTrue())
+			Expect(resource.Status.NextScheduleBoundary.Time).To(Equal(at(19, 18, 0)))
+			Expect(scheduleRequeueAfter(resource, at(19, 8, 0))).To(Equal(10 * time.Hour))
+			Expect(resource.Status.LastAction).To(Equal(tunnelv1alpha1.ReconcileActionUpdated))
+
+			By("re-enabling the resource when the schedule is removed outside of a window")
+			Expect(reconciler.reconcileResourceSchedule(context.Background(), apiClient, "42", resource, at(19, 19, 0))).To(Succeed())
+			resource.Spec.Schedule = nil
+			Expect(reconciler.reconcileResourceSchedule(context.Background(), apiClient, "42", resource, at(19, 19, 5))).To(Succeed())
+			Expect(updates[len(updates)-2:]).To(Equal([]map[string]interface{}{{"enabled": false}, {"enabled": true}}))
+			Expect(resource.Status.ScheduleActive).To(BeNil())
+			Expect(scheduleRequeueAfter(resource, at(19, 19, 5))).To(BeZero())
+		})
+	})
+	Context("When a bound resource also carries create configuration", func() {
+		It("should flag the ignored fields with the ConfigConflict condition", func() {
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol:   "http",
+					ResourceID: "42",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app"},
+				},
+			}
+			setConfigConflictCondition(resource)
+			cond := meta.FindStatusCondition(resource.Status.Conditions, ConfigConflictCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(Equal("spec.resourceId binds to existing resource 42, ignoring spec.httpConfig"))
+			Expect(cond.ObservedGeneration).To(Equal(int64(2)))
+
+			resource.Spec.HTTPConfig = nil
+			setConfigConflictCondition(resource)
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, ConfigConflictCondition)).To(BeNil())
+		})
+
+		It("should not resolve the domain of the ignored httpConfig", func() {
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app", DomainName: "missing.example.com"},
+				},
+			}
+			Expect(resolvesDomain(resource)).To(BeTrue())
+
+			for _, bind := range []tunnelv1alpha1.PangolinResourceSpec{
+				{ResourceID: "42"}, {BindByName: "dashboard"}, {BindBySubdomain: "dash.example.com"},
+			} {
+				resource.Spec.ResourceID = bind.ResourceID
+				resource.Spec.BindByName = bind.BindByName
+				resource.Spec.BindBySubdomain = bind.BindBySubdomain
+				Expect(resolvesDomain(resource)).To(BeFalse())
+			}
+		})
+	})
+	Context("When binding to an existing resource by ID", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.Method).To(Equal(http.MethodGet))
+				w.Header().Set("Content-Type", "application/json")
+				if req.URL.Path != "/v1/resource/42" {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"success":false,"message":"Resource not found"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42,"name":"dashboard","http":true,` +
+					`"subdomain":"dash","domainId":"d1","fullDomain":"dash.example.com","sso":true}}`))
+			}))
+			DeferCleanup(server.Close)
+		})
+
+		It("should report the bound resource's domain and authentication", func() {
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{ResourceID: "42"},
+			}
+			pRes, err := (&PangolinResourceReconciler{}).reconcilePangolinResource(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", "1", resource, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pRes.EffectiveID()).To(Equal("42"))
+			Expect(pRes.HTTP).To(BeTrue())
+			Expect(resource.Status.BindingMode).To(Equal("Bound"))
+			Expect(resource.Status.LastAction).To(Equal(tunnelv1alpha1.ReconcileActionAdopted))
+			Expect(resource.Status.FullDomain).To(Equal("dash.example.com"))
+			Expect(resource.Status.ResolvedDomainID).To(Equal("d1"))
+			Expect(resource.Status.SSOEnabled).To(BeTrue())
+		})
+
+		It("should fail when the bound resource does not exist", func() {
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{ResourceID: "43"},
+			}
+			_, err := (&PangolinResourceReconciler{}).reconcilePangolinResource(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", "1", resource, nil)
+			Expect(err).To(MatchError(ContainSubstring("bound resource 43 not found in Pangolin")))
+			Expect(pangolin.IsNotFound(err)).To(BeTrue())
+			Expect(resource.Status.BindingMode).To(BeEmpty())
+		})
+	})
+
+	Context("When binding to an existing resource by name or subdomain", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.Method).To(Equal(http.MethodGet))
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/v1/org/org1/resources":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resources":[` +
+						`{"resourceId":42,"name":"dashboard","subdomain":"dash","fullDomain":"dash.example.com"},` +
+						`{"resourceId":43,"name":"db","subdomain":"dash","fullDomain":"dash.other.com"},` +
+						`{"resourceId":44,"name":"db"}]}}`))
+				case "/v1/resource/42":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42,"name":"dashboard","http":true,` +
+						`"subdomain":"dash","domainId":"d1","fullDomain":"dash.example.com"}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			DeferCleanup(server.Close)
+		})
+
+		It("should resolve the name or domain to the bound resource", func() {
+			for _, spec := range []tunnelv1alpha1.PangolinResourceSpec{
+				{BindByName: "dashboard"},
+				{BindBySubdomain: "dash.example.com"},
+			} {
+				resource := &tunnelv1alpha1.PangolinResource{Spec: spec}
+				pRes, err := (&PangolinResourceReconciler{}).reconcilePangolinResource(context.Background(),
+					pangolin.NewClient(server.URL, "token"), "org1", "1", resource, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pRes.EffectiveID()).To(Equal("42"))
+				Expect(resource.Status.BindingMode).To(Equal("Bound"))
+				Expect(resource.Status.FullDomain).To(Equal("dash.example.com"))
+			}
+		})
+
+		It("should fail when no or several resources match", func() {
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{BindByName: "missing"},
+			}
+			_, err := (&PangolinResourceReconciler{}).reconcilePangolinResource(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", "1", resource, nil)
+			Expect(err).To(MatchError(`no resource named "missing" found in Pangolin`))
+
+			resource.Spec = tunnelv1alpha1.PangolinResourceSpec{BindBySubdomain: "dash"}
+			_, err = (&PangolinResourceReconciler{}).reconcilePangolinResource(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", "1", resource, nil)
+			Expect(err).To(MatchError("2 resources match spec.bindBySubdomain (42, 43), bind by spec.resourceId instead"))
+			Expect(resource.Status.BindingMode).To(BeEmpty())
+		})
+	})
+
+	Context("When an HTTP resource has access rules", func() {
+		It("should create, replace and remove the rules in Pangolin", func() {
+			rules := map[int]pangolin.ResourceRule{
+				// Added in the Pangolin UI, must survive
+				7: {RuleID: 7, Action: "ACCEPT", Match: "IP", Value: "192.0.2.1", Priority: 100, Enabled: true},
+			}
+			nextID := 10
+			var applyRules []bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.Method == http.MethodGet && req.URL.Path == "/v1/resource/42/rules":
+					list := []pangolin.ResourceRule{}
+					for _, rule := range rules {
+						list = append(list, rule)
+					}
+					body, _ := json.Marshal(list)
+					_, _ = fmt.Fprintf(w, `{"success":true,"data":{"rules":%s}}`, body)
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/resource/42/rule":
+					var rule pangolin.ResourceRule
+					Expect(json.NewDecoder(req.Body).Decode(&rule)).To(Succeed())
+					rule.RuleID = nextID
+					nextID++
+					rules[rule.RuleID] = rule
+					body, _ := json.Marshal(rule)
+					_, _ = fmt.Fprintf(w, `{"success":true,"data":%s}`, body)
+				case req.Method == http.MethodDelete && strings.HasPrefix(req.URL.Path, "/v1/resource/42/rule/"):
+					id, err := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/v1/resource/42/rule/"))
+					Expect(err).NotTo(HaveOccurred())
+					delete(rules, id)
+					_, _ = w.Write([]byte(`{"success":true}`))
+				case req.Method == http.MethodPost && req.URL.Path == "/v1/resource/42":
+					var body struct {
+						ApplyRules bool `json:"applyRules"`
+					}
+					Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+					applyRules = append(applyRules, body.ApplyRules)
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+				default:
+					Fail(fmt.Sprintf("unexpected request %s %s", req.Method, req.URL.Path))
+				}
+			}))
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			reconciler := &PangolinResourceReconciler{}
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{HTTPConfig: &tunnelv1alpha1.HTTPConfig{
+					AccessRules: []tunnelv1alpha1.AccessRule{
+						{Action: "allow", Match: "cidr", Value: "10.0.0.0/8"},
+						{Action: "deny", Match: "path", Value: "/admin/*"},
+					},
+				}},
+			}
+
+			By("creating the rules and enabling rule evaluation")
+			Expect(reconciler.reconcileAccessRules(context.Background(), apiClient, "42", resource)).To(Succeed())
+			Expect(resource.Status.AccessRuleIDs).To(Equal([]int{10, 11}))
+			Expect(rules[10]).To(Equal(pangolin.ResourceRule{RuleID: 10, Action: "ACCEPT", Match: "CIDR",
+				Value: "10.0.0.0/8", Priority: 1, Enabled: true}))
+			Expect(rules[11].Action).To(Equal("DROP"))
+			Expect(rules[11].Priority).To(Equal(2))
+			Expect(applyRules).To(Equal([]bool{true}))
+			Expect(resource.Status.LastAction).To(Equal(tunnelv1alpha1.ReconcileActionUpdated))
+
+			By("leaving matching rules alone")
+			resource.Status.LastAction = ""
+			Expect(reconciler.reconcileAccessRules(context.Background(), apiClient, "42", resource)).To(Succeed())
+			Expect(resource.Status.AccessRuleIDs).To(Equal([]int{10, 11}))
+			Expect(resource.Status.LastAction).To(BeEmpty())
+
+			By("replacing a changed rule")
+			resource.Spec.HTTPConfig.AccessRules[1].Value = "/internal/*"
+			Expect(reconciler.reconcileAccessRules(context.Background(), apiClient, "42", resource)).To(Succeed())
+			Expect(resource.Status.AccessRuleIDs).To(Equal([]int{10, 12}))
+			Expect(rules).NotTo(HaveKey(11))
+			Expect(rules[12].Value).To(Equal("/internal/*"))
+			Expect(applyRules).To(HaveLen(1))
+
+			By("removing the managed rules but keeping rule evaluation for the remaining rule")
+			resource.Spec.HTTPConfig.AccessRules = nil
+			Expect(reconciler.reconcileAccessRules(context.Background(), apiClient, "42", resource)).To(Succeed())
+			Expect(resource.Status.AccessRuleIDs).To(BeEmpty())
+			Expect(rules).To(HaveLen(1))
+			Expect(rules).To(HaveKey(7))
+			Expect(applyRules).To(Equal([]bool{true}))
+
+			By("disabling rule evaluation once no rules are left")
+			delete(rules, 7)
+			resource.Status.AccessRuleIDs = []int{10}
+			Expect(reconciler.reconcileAccessRules(context.Background(), apiClient, "42", resource)).To(Succeed())
+			Expect(applyRules).To(Equal([]bool{true, false}))
+		})
+	})
+
+	Context("When an HTTP resource is protected by a password and PIN code", func() {
+		It("should apply the credentials and re-apply them when the secret changes", func() {
+			ctx := context.Background()
+			var calls []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.Method).To(Equal(http.MethodPost))
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				calls = append(calls, fmt.Sprintf("%s %v", req.URL.Path, body))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true}`))
+			}))
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "dashboard-auth", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte("s3cret"), "pin": []byte("123456")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &PangolinResourceReconciler{Client: k8sClient, Recorder: recorder}
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol: "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Auth: &tunnelv1alpha1.ResourceAuth{
+						PasswordSecretRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "dashboard-auth"},
+							Key:                  "password",
+						},
+						PincodeSecretRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "dashboard-auth"},
+							Key:                  "pin",
+						},
+					}},
+				},
+			}
+
+			By("applying both credentials")
+			Expect(reconciler.reconcileResourceAuth(ctx, apiClient, "42", resource)).To(Succeed())
+			Expect(calls).To(Equal([]string{
+				"/v1/resource/42/password map[password:s3cret]",
+				"/v1/resource/42/pincode map[pincode:123456]",
+			}))
+			Expect(resource.Status.PasswordEnabled).To(BeTrue())
+			Expect(resource.Status.PincodeEnabled).To(BeTrue())
+			Expect(resource.Status.AuthSecretVersions).To(Equal(fmt.Sprintf(
+				"password=dashboard-auth/password@%s,pincode=dashboard-auth/pin@%s",
+				secret.ResourceVersion, secret.ResourceVersion)))
+			Expect(recorder.Events).To(Receive(Equal("Normal AuthConfigured Authentication configured: password=true, pincode=true")))
+
+			By("not re-applying unchanged credentials")
+			Expect(reconciler.reconcileResourceAuth(ctx, apiClient, "42", resource)).To(Succeed())
+			Expect(calls).To(HaveLen(2))
+
+			By("re-applying a rotated password")
+			secret.Data["password"] = []byte("rotated")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			Expect(reconciler.reconcileResourceAuth(ctx, apiClient, "42", resource)).To(Succeed())
+			Expect(calls[2]).To(Equal("/v1/resource/42/password map[password:rotated]"))
+
+			By("rejecting a malformed PIN code")
+			secret.Data["pin"] = []byte("12ab")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			Expect(reconciler.reconcileResourceAuth(ctx, apiClient, "42", resource)).To(
+				MatchError("PIN code in secret dashboard-auth must be 6 digits"))
+
+			By("removing the protection when the references are removed")
+			calls = nil
+			resource.Spec.HTTPConfig.Auth = nil
+			Expect(reconciler.reconcileResourceAuth(ctx, apiClient, "42", resource)).To(Succeed())
+			Expect(calls).To(Equal([]string{
+				"/v1/resource/42/password map[password:<nil>]",
+				"/v1/resource/42/pincode map[pincode:<nil>]",
+			}))
+			Expect(resource.Status.PasswordEnabled).To(BeFalse())
+			Expect(resource.Status.AuthSecretVersions).To(BeEmpty())
+		})
+
+		It("should enqueue the resources using a secret", func() {
+			ctx := context.Background()
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "auth-watch", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef: tunnelv1alpha1.LocalObjectReference{Name: "tunnel"},
+					Protocol:  "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Auth: &tunnelv1alpha1.ResourceAuth{
+						PincodeSecretRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "auth-watch-pin"},
+							Key:                  "pin",
+						},
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			reconciler := &PangolinResourceReconciler{Client: k8sClient}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "auth-watch-pin", Namespace: "default"}}
+			Expect(reconciler.resourcesForSecret(ctx, secret)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "auth-watch", Namespace: "default"}}))
+			secret.Name = "other"
+			Expect(reconciler.resourcesForSecret(ctx, secret)).To(BeEmpty())
+		})
+
+		It("should enqueue the resources of organizations using a secret or config map", func() {
+			ctx := context.Background()
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "client-watch-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: "https://pangolin.example.com",
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "client-watch-key"},
+						Key:                  "apiKey",
+					},
+					ResourceAPIKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "client-watch-resource-key"},
+						Key:                  "apiKey",
+					},
+					CABundleRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "client-watch-ca"},
+						Key:                  "ca.crt",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, org)
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "client-watch-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "client-watch-org"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "client-watch", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef: tunnelv1alpha1.LocalObjectReference{Name: "client-watch-tunnel"},
+					Protocol:  "tcp",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			reconciler := &PangolinResourceReconciler{Client: k8sClient}
+			expected := reconcile.Request{NamespacedName: types.NamespacedName{Name: "client-watch", Namespace: "default"}}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "client-watch-resource-key", Namespace: "default"}}
+			Expect(reconciler.resourcesForSecret(ctx, secret)).To(ConsistOf(expected))
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "client-watch-ca", Namespace: "default"}}
+			Expect(reconciler.resourcesForConfigMap(ctx, configMap)).To(ConsistOf(expected))
+
+			configMap.Name = "other"
+			Expect(reconciler.resourcesForConfigMap(ctx, configMap)).To(BeEmpty())
+		})
+	})
+
+	Context("When reporting the runtime health of a resource", func() {
+		It("should set Degraded while the site is offline or all targets are disabled", func() {
+			disabled := false
+			resource := &tunnelv1alpha1.PangolinResource{}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "edge"},
+				Status:     tunnelv1alpha1.PangolinTunnelStatus{NiceID: "brave-tiger", SiteType: "newt", Online: true},
+			}
+			targets := []tunnelv1alpha1.TargetConfig{
+				{IP: "10.0.0.1", Port: 80, Enabled: &disabled},
+				{IP: "10.0.0.2", Port: 80},
+			}
+			degraded := func() *metav1.Condition {
+				setResourceDegradedCondition(resource, tunnel, targets)
+				return meta.FindStatusCondition(resource.Status.Conditions, DegradedCondition)
+			}
+
+			Expect(degraded()).To(BeNil())
+
+			tunnel.Status.Online = false
+			Expect(degraded().Reason).To(Equal("SiteOffline"))
+			Expect(degraded().Message).To(Equal("Site brave-tiger of tunnel edge reports offline"))
+
+			tunnel.Status.Online = true
+			targets[1].Enabled = &disabled
+			Expect(degraded().Reason).To(Equal("TargetsDisabled"))
+
+			By("not flagging a resource disabled on purpose")
+			resource.Spec.Enabled = &disabled
+			Expect(degraded()).To(BeNil())
+		})
+	})
+
+	Context("When a resource is suspended", func() {
+		It("should disable its targets and enable them again on resume", func() {
+			ctx := context.Background()
+			var target *pangolin.Target
+			var updates []bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/org/org1/resource":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/resource/42/target":
+					target = &pangolin.Target{TargetID: 7, SiteID: 3, IP: "10.0.0.1", Port: 5432, Method: "tcp", Enabled: true}
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targetId":7}}`))
+				case req.Method == http.MethodPost && req.URL.Path == "/v1/resource/42/target/7":
+					var body map[string]interface{}
+					Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+					target.Enabled = body["enabled"].(bool)
+					updates = append(updates, target.Enabled)
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targetId":7}}`))
+				case req.Method == http.MethodGet && req.URL.Path == "/v1/resource/42/targets":
+					targets := "[]"
+					if target != nil {
+						data, _ := json.Marshal(target)
+						targets = "[" + string(data) + "]"
+					}
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targets":` + targets + `}}`))
+				case req.Method == http.MethodGet && req.URL.Path == "/v1/resource/42":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42,"http":false,"protocol":"tcp","proxyPort":5433}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "suspend-credentials", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "suspend-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "suspend-credentials"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "suspend-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "suspend-org"},
+				},
+			}
+			enableProxy := true
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "suspend-resource",
+					Namespace:  "default",
+					Finalizers: []string{ResourceFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef:   tunnelv1alpha1.LocalObjectReference{Name: "suspend-tunnel"},
+					Name:        "db",
+					Protocol:    "tcp",
+					ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 5433, EnableProxy: &enableProxy},
+					Targets:     []tunnelv1alpha1.TargetConfig{{IP: "10.0.0.1", Port: 5432}},
+				},
+			}
+			for _, obj := range []client.Object{secret, org, tunnel, resource} {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, obj)
+			}
+			org.Status.Status = "Ready"
+			org.Status.OrganizationID = "org1"
+			Expect(k8sClient.Status().Update(ctx, org)).To(Succeed())
+			tunnel.Status.Status = "Ready"
+			tunnel.Status.SiteID = 3
+			tunnel.Status.SiteType = "local"
+			Expect(k8sClient.Status().Update(ctx, tunnel)).To(Succeed())
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+			key := client.ObjectKeyFromObject(resource)
+			reconcileResource := func(suspend bool) reconcile.Result {
+				Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+				resource.Spec.Suspend = &suspend
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+				return result
+			}
+
+			reconcileResource(false)
+			Expect(resource.Status.Status).To(Equal("Ready"))
+			Expect(target.Enabled).To(BeTrue())
+
+			By("suspending the resource")
+			result := reconcileResource(true)
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(resource.Status.Status).To(Equal("Suspended"))
+			Expect(resource.Status.ResourceID).To(Equal("42"))
+			Expect(updates).To(Equal([]bool{false}))
+			cond := meta.FindStatusCondition(resource.Status.Conditions, "Ready")
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("Suspended"))
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, DegradedCondition)).To(BeNil())
+			Expect(<-recorder.Events).To(Equal("Normal Suspended Resource suspended, its targets were disabled"))
+
+			By("resuming the resource")
+			reconcileResource(false)
+			Expect(resource.Status.Status).To(Equal("Ready"))
+			Expect(updates).To(Equal([]bool{false, true}))
+			Expect(<-recorder.Events).To(Equal("Normal Resumed Resource resumed, its targets were enabled"))
+		})
+	})
+
+	Context("When reconciling a UDP resource", func() {
+		It("should create a udp proxy resource with udp targets and a proxy endpoint", func() {
+			ctx := context.Background()
+			var created, target map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/org/org1/resource":
+					Expect(json.NewDecoder(req.Body).Decode(&created)).To(Succeed())
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/resource/42/target":
+					Expect(json.NewDecoder(req.Body).Decode(&target)).To(Succeed())
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targetId":7}}`))
+				case req.Method == http.MethodGet && req.URL.Path == "/v1/resource/42/targets":
+					targets := "[]"
+					if target != nil {
+						targets = `[{"targetId":7,"siteId":3,"ip":"10.0.0.53","port":53,"method":"udp","enabled":true}]`
+					}
+					_, _ = w.Write([]byte(`{"success":true,"data":{"targets":` + targets + `}}`))
+				case req.Method == http.MethodGet && req.URL.Path == "/v1/resource/42":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42,"http":false,"protocol":"udp","proxyPort":5353}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "udp-credentials", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "udp-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "udp-credentials"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "udp-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "udp-org"},
+				},
+			}
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "udp-resource",
+					Namespace:  "default",
+					Finalizers: []string{ResourceFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef:   tunnelv1alpha1.LocalObjectReference{Name: "udp-tunnel"},
+					Name:        "dns",
+					Protocol:    "udp",
+					ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 5353},
+					Targets:     []tunnelv1alpha1.TargetConfig{{IP: "10.0.0.53", Port: 53}},
+				},
+			}
+			for _, obj := range []client.Object{secret, org, tunnel, resource} {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, obj)
+			}
+			org.Status.Status = "Ready"
+			org.Status.OrganizationID = "org1"
+			Expect(k8sClient.Status().Update(ctx, org)).To(Succeed())
+			tunnel.Status.Status = "Ready"
+			tunnel.Status.SiteID = 3
+			tunnel.Status.SiteType = "local"
+			tunnel.Status.Endpoint = "edge.example.com"
+			Expect(k8sClient.Status().Update(ctx, tunnel)).To(Succeed())
+
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			key := client.ObjectKeyFromObject(resource)
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+
+			Expect(resource.Status.Status).To(Equal("Ready"))
+			Expect(created).To(HaveKeyWithValue("http", false))
+			Expect(created).To(HaveKeyWithValue("protocol", "udp"))
+			Expect(created).To(HaveKeyWithValue("proxyPort", float64(5353)))
+			Expect(created).To(HaveKeyWithValue("enableProxy", true))
+			Expect(created).NotTo(HaveKey("subdomain"))
+			Expect(target).To(HaveKeyWithValue("method", "udp"))
+			Expect(resource.Status.ProxyEndpoint).To(Equal("edge.example.com:5353"))
+			Expect(resource.Status.URL).To(BeEmpty())
+			Expect(resource.Status.FullDomain).To(BeEmpty())
+
+			By("reconciling again without recreating the resource")
+			created = nil
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+			Expect(resource.Status.Status).To(Equal("Ready"))
+			Expect(created).To(BeNil())
+		})
+
+		It("should report a conflicting resource that cannot be found", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if req.Method == http.MethodPut {
+					w.WriteHeader(http.StatusConflict)
+					_, _ = w.Write([]byte(`{"success":false,"message":"Resource already exists"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":{"resources":[]}}`))
+			}))
+			defer server.Close()
+
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Name:        "dns",
+					Protocol:    "udp",
+					ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 5353},
+				},
+			}
+			reconciler := &PangolinResourceReconciler{}
+			_, err := reconciler.reconcilePangolinResource(context.Background(),
+				pangolin.NewClient(server.URL, "token"), "org1", "3", resource, &tunnelv1alpha1.PangolinOrganization{})
+			Expect(err).To(MatchError(ContainSubstring("resource exists but could not be found (name=dns)")))
+			Expect(pangolin.IsConflict(err)).To(BeTrue())
+		})
+	})
+
+	Context("When another resource already serves the subdomain", func() {
+		var holder *tunnelv1alpha1.PangolinResource
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/org/org1/resources"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"resources":[
+					{"resourceId":9,"name":"app","http":true,"subdomain":"app","domainId":"d1"},
+					{"resourceId":10,"name":"legacy","http":true,"subdomain":"legacy","domainId":"d1"}
+				]}}`))
+			}))
+			DeferCleanup(server.Close)
+
+			holder = &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "subdomain-holder", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef:  tunnelv1alpha1.LocalObjectReference{Name: "tunnel"},
+					Name:       "app",
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app"},
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), holder)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, context.Background(), holder)
+			holder.Status.ResourceID = "9"
+			Expect(k8sClient.Status().Update(context.Background(), holder)).To(Succeed())
+		})
+
+		check := func(resource *tunnelv1alpha1.PangolinResource, subdomain string) error {
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &PangolinResourceReconciler{Client: k8sClient, Recorder: recorder}
+			return reconciler.checkSubdomainConflict(context.Background(), pangolin.NewClient(server.URL, "token"),
+				"org1", resource, subdomain, "d1")
+		}
+
+		It("should name the resource holding it", func() {
+			resource := &tunnelv1alpha1.PangolinResource{ObjectMeta: metav1.ObjectMeta{Name: "newcomer", Namespace: "default"}}
+			err := check(resource, "app")
+			Expect(err).To(MatchError(`subdomain "app" on domain d1 is already used by PangolinResource default/subdomain-holder`))
+			cond := meta.FindStatusCondition(resource.Status.Conditions, SubdomainConflictCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("SubdomainConflict"))
+
+			By("clearing the condition once the subdomain is free")
+			Expect(check(resource, "free")).To(Succeed())
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, SubdomainConflictCondition)).To(BeNil())
+		})
+
+		It("should leave a resource no one holds to adoption before creation", func() {
+			resource := &tunnelv1alpha1.PangolinResource{ObjectMeta: metav1.ObjectMeta{Name: "newcomer", Namespace: "default"}}
+			Expect(check(resource, "legacy")).To(Succeed())
+		})
+
+		It("should reject moving a created resource onto a used subdomain", func() {
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "mover", Namespace: "default"},
+				Status:     tunnelv1alpha1.PangolinResourceStatus{ResourceID: "42"},
+			}
+			Expect(check(resource, "legacy")).To(MatchError(ContainSubstring("already used by Pangolin resource 10 (legacy)")))
+		})
+
+		It("should not conflict with the resource it holds itself", func() {
+			Expect(check(holder, "app")).To(Succeed())
+		})
+	})
+
+	Context("When the domain of a resource awaits verification", func() {
+		It("should wait instead of creating the resource", func() {
+			org := &tunnelv1alpha1.PangolinOrganization{Status: tunnelv1alpha1.PangolinOrganizationStatus{
+				Domains: []tunnelv1alpha1.Domain{{DomainID: "d2", BaseDomain: "new.example.com"}},
+			}}
+			resource := &tunnelv1alpha1.PangolinResource{}
+			resource.Status.ResolvedDomainID = "d2"
+
+			Expect(domainPendingMessage(resource, org)).To(Equal("Waiting for domain new.example.com to be verified"))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, DomainPendingCondition)).To(BeTrue())
+
+			By("not holding back resources that already exist")
+			resource.Status.ResourceID = "42"
+			Expect(domainPendingMessage(resource, org)).To(BeEmpty())
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, DomainPendingCondition)).To(BeNil())
+
+			By("not waiting for domains whose verification failed")
+			resource.Status.ResourceID = ""
+			org.Status.Domains[0].Failed = true
+			Expect(domainPendingMessage(resource, org)).To(BeEmpty())
+		})
+	})
+
+	Context("When a resource is annotated for a dry run", func() {
+		It("should report the plan without calling the Pangolin API", func() {
+			ctx := context.Background()
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "dry-run-credentials", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "dry-run-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "dry-run-credentials"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "dry-run-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "dry-run-org"},
+				},
+			}
+			resource := &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "dry-run-resource",
+					Namespace:   "default",
+					Finalizers:  []string{ResourceFinalizerName},
+					Annotations: map[string]string{DryRunAnnotation: "true"},
+				},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef:  tunnelv1alpha1.LocalObjectReference{Name: "dry-run-tunnel"},
+					Name:       "web",
+					Protocol:   "http",
+					HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "app"},
+					Targets:    []tunnelv1alpha1.TargetConfig{{IP: "10.0.0.10", Port: 8080}},
+				},
+			}
+			for _, obj := range []client.Object{secret, org, tunnel, resource} {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, obj)
+			}
+			org.Status.Status = "Ready"
+			org.Status.OrganizationID = "org1"
+			org.Status.DefaultDomainID = "domain1"
+			org.Status.Domains = []tunnelv1alpha1.Domain{{DomainID: "domain1", BaseDomain: "example.com", Verified: true}}
+			Expect(k8sClient.Status().Update(ctx, org)).To(Succeed())
+			tunnel.Status.Status = "Ready"
+			tunnel.Status.SiteID = 3
+			Expect(k8sClient.Status().Update(ctx, tunnel)).To(Succeed())
+
+			controllerReconciler := &PangolinResourceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			key := client.ObjectKeyFromObject(resource)
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+
+			Expect(requests).To(BeZero())
+			Expect(resource.Status.Status).To(Equal("DryRun"))
+			Expect(resource.Status.ResourceID).To(BeEmpty())
+			Expect(resource.Status.Plan).To(Equal(&tunnelv1alpha1.ResourcePlan{
+				Action:     "Create",
+				Name:       "web",
+				Protocol:   "http",
+				Subdomain:  "app",
+				DomainID:   "domain1",
+				FullDomain: "app.example.com",
+				SiteID:     "3",
+				Targets:    []string{"http://10.0.0.10:8080"},
+			}))
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, "Ready").Message).To(Equal(
+				`Dry run: would create "web" on app.example.com with targets http://10.0.0.10:8080`))
+
+			By("binding by resourceId")
+			resource.Spec.ResourceID = "42"
+			plan, err := controllerReconciler.resourcePlan(ctx, resource, org, "3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan).To(Equal(&tunnelv1alpha1.ResourcePlan{Action: "Bind", ResourceID: "42"}))
+		})
+	})
 })
@@ -3,19 +3,28 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
 	"github.com/bovf/pangolin-operator/pkg/pangolin"
@@ -23,20 +32,68 @@ import (
 
 const (
 	TunnelFinalizerName = "tunnel.pangolin.io/finalizer"
+
+	// Keys of the Newt credentials Secret
+	NewtSecretIDKey       = "newtId"
+	NewtSecretSecretKey   = "newtSecret"
+	NewtSecretEndpointKey = "endpoint"
+
+	// Pod template annotation bumped to roll the Newt client after a credential change
+	NewtRestartedAtAnnotation = "tunnel.pangolin.io/restartedAt"
+
+	// DefaultNewtImage is the Newt client image used when spec.newtClient.image is not set
+	DefaultNewtImage = "fosrl/newt:latest"
 )
 
 // PangolinTunnelReconciler reconciles a PangolinTunnel object
 type PangolinTunnelReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// APIReader reads the Newt client pods uncached, so pods are not cached
+	// cluster-wide for the few reconciles that need them. Nil uses Client.
+	APIReader client.Reader
+
+	// Class restricts this instance to objects with a matching pangolin.io/class
+	// label or annotation. Empty manages only unclassed objects.
+	Class string
+
+	// AllowCrossNamespaceOrg allows organizationRef to point to an organization in another namespace.
+	AllowCrossNamespaceOrg bool
+
+	// ErrorBackoffCap is the longest wait between retries of an object in Error.
+	// Zero uses DefaultErrorBackoffCap.
+	ErrorBackoffCap time.Duration
+
+	// ErrorRequeueInterval is the wait before the first retry of an object in
+	// Error. Zero uses DefaultErrorRequeueInterval.
+	ErrorRequeueInterval time.Duration
+
+	// ResyncInterval, when set, re-reconciles Ready objects that often to correct
+	// drift made outside the operator.
+	ResyncInterval time.Duration
+
+	// ReconcileTimeout bounds each reconcile, cancelling its Pangolin API
+	// requests when exceeded. Zero leaves reconciles unbounded.
+	ReconcileTimeout time.Duration
+
+	backoff *errorBackoff
+	clients *pangolinClientCache
+
+	// FullSync, when set, periodically enqueues every object for a full reconcile.
+	FullSync *FullSync
 }
 
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolintunnels,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolintunnels/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolintunnels/finalizers,verbs=update
 //+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolinorganizations,verbs=get;list;watch
+//+kubebuilder:rbac:groups=tunnel.pangolin.io,resources=pangolinresources,verbs=get;list;watch;update
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile implements the reconciliation logic for PangolinTunnel.
 //
@@ -70,8 +127,9 @@ type PangolinTunnelReconciler struct {
 //
 // Create New Site (neither ID set):
 //   - Creates new site with specified name and type
-//   - Uses tunnel name as site name if not specified
-//   - Defaults to "newt" type if not specified
+//   - Uses tunnel name as site name if not specified, prefixed with the
+//     organization's defaults.siteNamePrefix
+//   - Defaults to the organization's defaults.siteType, or "newt"
 //   - Status shows bindingMode: "Created"
 //
 // Idempotency:
@@ -99,8 +157,19 @@ func (r *PangolinTunnelReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
-	// Handle deletion if tunnel is being deleted
+	// Ignore objects managed by another operator instance
+	if !matchesClass(tunnel, r.Class) {
+		return ctrl.Result{}, nil
+	}
+
+	// Handle deletion if tunnel is being deleted. While paused the finalizer is
+	// kept, so the site is only deleted once resumed. An organization that
+	// cannot be read does not pause the tunnel.
 	if tunnel.DeletionTimestamp != nil {
+		org, _ := r.getOrganizationForTunnel(ctx, tunnel)
+		if msg := pausedMessage(tunnel, org); msg != "" {
+			return markPaused(ctx, r.Client, tunnel, &tunnel.Status.Conditions, msg)
+		}
 		return r.handleDeletion(ctx, tunnel)
 	}
 
@@ -114,11 +183,16 @@ func (r *PangolinTunnelReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	org, err := r.getOrganizationForTunnel(ctx, tunnel)
 	if err != nil {
 		logger.Error(err, "Failed to get referenced organization")
-		return r.updateStatus(ctx, tunnel, "Error", err.Error())
+		return r.updateTunnelError(ctx, tunnel, err)
+	}
+
+	// Skip API writes while paused directly or through the organization
+	if msg := pausedMessage(tunnel, org); msg != "" {
+		return markPaused(ctx, r.Client, tunnel, &tunnel.Status.Conditions, msg)
 	}
 
 	// Wait for organization to be ready
-	if org.Status.Status != "Ready" {
+	if !organizationUsable(org) {
 		logger.Info("Organization not ready yet, waiting", "organization", org.Name)
 		return r.updateStatus(ctx, tunnel, "Waiting", "Waiting for organization to be ready")
 	}
@@ -127,7 +201,7 @@ func (r *PangolinTunnelReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	apiClient, err := r.createPangolinClientFromOrganization(ctx, org)
 	if err != nil {
 		logger.Error(err, "Failed to create Pangolin API client")
-		return r.updateStatus(ctx, tunnel, "Error", err.Error())
+		return r.updateTunnelError(ctx, tunnel, err)
 	}
 
 	// Get organization ID from organization status
@@ -136,63 +210,74 @@ func (r *PangolinTunnelReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return r.updateStatus(ctx, tunnel, "Error", "Organization missing organization ID")
 	}
 
+	// Track what this reconcile changes in Pangolin
+	tunnel.Status.LastAction = tunnelv1alpha1.ReconcileActionNoOp
+
 	// Reconcile site with flexible binding (bind or create)
-	site, err := r.reconcileSite(ctx, *apiClient, orgID, tunnel)
+	site, err := r.reconcileSite(ctx, *apiClient, orgID, org.Spec.Defaults, tunnel)
 	if err != nil {
 		logger.Error(err, "Failed to reconcile site")
-		return r.updateStatus(ctx, tunnel, "Error", err.Error())
+		return r.updateTunnelError(ctx, tunnel, err)
 	}
 
 	// Create Newt secret if needed (for Newt tunnel authentication)
-	err = r.reconcileNewtSecret(ctx, tunnel, site)
+	err = r.reconcileNewtSecret(ctx, apiClient, tunnel, site)
 	if err != nil {
 		logger.Error(err, "Failed to reconcile Newt secret")
-		return r.updateStatus(ctx, tunnel, "Error", err.Error())
+		return r.updateTunnelError(ctx, tunnel, err)
 	}
 
 	// Create Newt deployment if needed (for in-cluster tunnel client)
 	err = r.reconcileNewtDeployment(ctx, tunnel, site)
 	if err != nil {
 		logger.Error(err, "Failed to reconcile Newt deployment")
-		return r.updateStatus(ctx, tunnel, "Error", err.Error())
+		return r.updateTunnelError(ctx, tunnel, err)
 	}
 
-	return r.updateStatus(ctx, tunnel, "Ready", "Tunnel is ready")
+	setSiteDegradedCondition(tunnel)
+	recordReconcileAction("pangolintunnel", tunnel.Status.LastAction)
+
+	// With a managed Newt client, the tunnel is only Ready once enough
+	// clients are ready. An offline site is reported by the Degraded condition.
+	status, message := "Ready", "Tunnel is ready"
+	if ready, waiting := newtReadiness(tunnel); !ready {
+		status, message = "Waiting", waiting
+	}
+	result, err := r.updateStatus(ctx, tunnel, status, message)
+	// Follow a rollout of the Newt client closely
+	if newtProgressing(tunnel) && (result.RequeueAfter == 0 || result.RequeueAfter > newtProgressPoll) {
+		result.RequeueAfter = newtProgressPoll
+	}
+	return result, err
 }
 
 // getOrganizationForTunnel retrieves the PangolinOrganization referenced by the tunnel.
 // The organization provides API credentials and organizational context.
 func (r *PangolinTunnelReconciler) getOrganizationForTunnel(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel) (*tunnelv1alpha1.PangolinOrganization, error) {
-	org := &tunnelv1alpha1.PangolinOrganization{}
-	err := r.Get(ctx, types.NamespacedName{
-		Namespace: tunnel.Namespace,
-		Name:      tunnel.Spec.OrganizationRef.Name,
-	}, org)
+	key, err := organizationKey(tunnel.Spec.OrganizationRef, tunnel.Namespace, r.AllowCrossNamespaceOrg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get organization %s: %w", tunnel.Spec.OrganizationRef.Name, err)
+		return nil, err
+	}
+
+	org := &tunnelv1alpha1.PangolinOrganization{}
+	if err := r.Get(ctx, key, org); err != nil {
+		return nil, fmt.Errorf("failed to get organization %s: %w", key, err)
 	}
 	return org, nil
 }
 
 // createPangolinClientFromOrganization creates a Pangolin API client using
 // credentials from the referenced organization.
+//
+// Site operations use spec.siteAPIKeyRef when set, so the tunnel controller can
+// run with a more privileged key than the other controllers. Otherwise the
+// organization's apiKeyRef is used.
 func (r *PangolinTunnelReconciler) createPangolinClientFromOrganization(ctx context.Context, org *tunnelv1alpha1.PangolinOrganization) (*pangolin.Client, error) {
-	// Get API key from secret referenced by organization
-	secret := &corev1.Secret{}
-	secretKey := types.NamespacedName{
-		Namespace: org.Namespace,
-		Name:      org.Spec.APIKeyRef.Name,
-	}
-	if err := r.Get(ctx, secretKey, secret); err != nil {
-		return nil, fmt.Errorf("failed to get API key secret: %w", err)
-	}
-
-	apiKeyBytes, ok := secret.Data[org.Spec.APIKeyRef.Key]
-	if !ok {
-		return nil, fmt.Errorf("API key not found in secret")
+	keyRef := org.Spec.APIKeyRef
+	if org.Spec.SiteAPIKeyRef != nil {
+		keyRef = *org.Spec.SiteAPIKeyRef
 	}
-
-	return pangolin.NewClient(org.Spec.APIEndpoint, string(apiKeyBytes)), nil
+	return organizationClient(ctx, r.Client, r.clients, org, keyRef)
 }
 
 // reconcileSite handles flexible site binding and creation with comprehensive idempotency.
@@ -217,10 +302,158 @@ func (r *PangolinTunnelReconciler) createPangolinClientFromOrganization(ctx cont
 //   - siteType: Site type (newt, wireguard, etc.)
 //   - subnet: Site's network subnet
 //   - address: Site's network address
-//   - online: Whether site is currently online
+//   - online: Whether site is currently online, refreshed on every reconcile
 //   - endpoint: Site's connection endpoint
 //   - bindingMode: "Bound" or "Created"
-func (r *PangolinTunnelReconciler) reconcileSite(ctx context.Context, apiClient pangolin.Client, orgID string, tunnel *tunnelv1alpha1.PangolinTunnel) (*pangolin.Site, error) {
+//
+// Both siteId and niceId are always populated, whichever one was used to find the site.
+// A site the API reports in another organization is an error.
+func (r *PangolinTunnelReconciler) reconcileSite(
+	ctx context.Context,
+	apiClient pangolin.Client,
+	orgID string,
+	defaults *tunnelv1alpha1.OrganizationDefaults,
+	tunnel *tunnelv1alpha1.PangolinTunnel,
+) (*pangolin.Site, error) {
+	site, err := r.resolveSite(ctx, apiClient, orgID, defaults, tunnel)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := completeSiteIdentity(ctx, apiClient, orgID, site); err != nil {
+		return nil, err
+	}
+	// A site moved to another organization must not be used through this one
+	if site.OrgID != "" && site.OrgID != orgID {
+		return nil, fmt.Errorf("site %d belongs to organization %s, not %s", site.SiteID, site.OrgID, orgID)
+	}
+	tunnel.Status.SiteID = site.SiteID
+	tunnel.Status.NiceID = site.NiceID
+	tunnel.Status.Online = site.Online
+	tunnel.Status.Subnet = site.Subnet
+	tunnel.Status.Address = site.Address
+	tunnel.Status.Endpoint = site.Endpoint
+
+	return site, nil
+}
+
+// newSiteSettings returns the name and type of a site created for tunnel.
+//
+// Unset spec fields fall back to the organization defaults: the site name is the
+// tunnel name with defaults.siteNamePrefix prepended, and the type is
+// defaults.siteType, or "newt".
+func newSiteSettings(tunnel *tunnelv1alpha1.PangolinTunnel, defaults *tunnelv1alpha1.OrganizationDefaults) (string, string) {
+	siteName := tunnel.Spec.SiteName
+	if siteName == "" {
+		siteName = tunnel.Name
+		if defaults != nil {
+			siteName = defaults.SiteNamePrefix + siteName
+		}
+	}
+
+	siteType := tunnel.Spec.SiteType
+	if siteType == "" && defaults != nil {
+		siteType = defaults.SiteType
+	}
+	if siteType == "" {
+		siteType = "newt"
+	}
+	return siteName, siteType
+}
+
+// completeSiteIdentity fills in a missing siteId or niceId on site.
+//
+// Some API responses only carry one of the identifiers. The site is re-fetched
+// by the identifier that is known, falling back to the organization's site list.
+// Only the identifiers are copied so other fields (e.g. Newt credentials from a
+// create response) are kept.
+func completeSiteIdentity(ctx context.Context, apiClient pangolin.Client, orgID string, site *pangolin.Site) error {
+	logger := log.FromContext(ctx)
+
+	if site.SiteID != 0 && site.NiceID != "" {
+		return nil
+	}
+	if site.SiteID == 0 && site.NiceID == "" {
+		return fmt.Errorf("site %q has neither siteId nor niceId", site.Name)
+	}
+
+	logger.Info("Site identity incomplete, re-fetching", "siteId", site.SiteID, "niceId", site.NiceID)
+
+	var fetched *pangolin.Site
+	var err error
+	if site.SiteID != 0 {
+		fetched, err = apiClient.GetSiteByID(ctx, site.SiteID)
+	} else {
+		fetched, err = apiClient.GetSiteByNiceID(ctx, orgID, site.NiceID)
+	}
+	if err == nil {
+		mergeSiteIdentity(site, fetched)
+	}
+
+	if site.SiteID == 0 || site.NiceID == "" {
+		sites, listErr := apiClient.ListSites(ctx, orgID)
+		if listErr != nil {
+			return fmt.Errorf("failed to complete site identity: %w", listErr)
+		}
+		for i := range sites {
+			if (site.SiteID != 0 && sites[i].SiteID == site.SiteID) || (site.NiceID != "" && sites[i].NiceID == site.NiceID) {
+				mergeSiteIdentity(site, &sites[i])
+				break
+			}
+		}
+	}
+
+	if site.SiteID == 0 || site.NiceID == "" {
+		return fmt.Errorf("failed to resolve both siteId and niceId for site (siteId=%d, niceId=%q)", site.SiteID, site.NiceID)
+	}
+	return nil
+}
+
+// mergeSiteIdentity copies identifiers missing on site from other
+func mergeSiteIdentity(site, other *pangolin.Site) {
+	if site.SiteID == 0 {
+		site.SiteID = other.SiteID
+	}
+	if site.NiceID == "" {
+		site.NiceID = other.NiceID
+	}
+}
+
+// completeSiteNetwork fills in the network details of a created site.
+//
+// The create response lacks the endpoint, subnet and address of the site, so it
+// is re-fetched by ID. Without the endpoint in the tunnel status, TCP/UDP
+// resources on the tunnel would report a proxy endpoint without host. Only
+// missing fields are copied so the Newt credentials of the create response are
+// kept. A failed lookup is logged, the next reconcile re-fetches the site anyway.
+func completeSiteNetwork(ctx context.Context, apiClient pangolin.Client, site *pangolin.Site) {
+	if site.SiteID == 0 || (site.Endpoint != "" && site.Subnet != "" && site.Address != "") {
+		return
+	}
+	fetched, err := apiClient.GetSiteByID(ctx, site.SiteID)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get the network details of the created site", "siteId", site.SiteID)
+		return
+	}
+	if site.Endpoint == "" {
+		site.Endpoint = fetched.Endpoint
+	}
+	if site.Subnet == "" {
+		site.Subnet = fetched.Subnet
+	}
+	if site.Address == "" {
+		site.Address = fetched.Address
+	}
+}
+
+// resolveSite finds, binds or creates the site for a tunnel, see reconcileSite.
+func (r *PangolinTunnelReconciler) resolveSite(
+	ctx context.Context,
+	apiClient pangolin.Client,
+	orgID string,
+	defaults *tunnelv1alpha1.OrganizationDefaults,
+	tunnel *tunnelv1alpha1.PangolinTunnel,
+) (*pangolin.Site, error) {
 	logger := log.FromContext(ctx)
 
 	// STEP 1: Check status first to avoid duplicate creations
@@ -234,6 +467,11 @@ func (r *PangolinTunnelReconciler) reconcileSite(ctx context.Context, apiClient
 			// Site exists and is valid, return it
 			return site, nil
 		}
+		if !pangolin.IsNotFound(err) {
+			// Only a site the API reports missing is recreated, an outage or a
+			// rejected API key must not lead to a duplicate site
+			return nil, fmt.Errorf("failed to verify site %d: %w", tunnel.Status.SiteID, err)
+		}
 
 		// Site doesn't exist anymore, log warning and continue to recreate
 		logger.Info("Site in status no longer exists in API, will recreate", "siteId", tunnel.Status.SiteID)
@@ -263,27 +501,20 @@ func (r *PangolinTunnelReconciler) reconcileSite(ctx context.Context, apiClient
 		tunnel.Status.NiceID = site.NiceID
 		tunnel.Status.SiteName = site.Name
 		tunnel.Status.SiteType = site.Type
-		tunnel.Status.Subnet = site.Subnet
-		tunnel.Status.Address = site.Address
-		tunnel.Status.Online = site.Online
-		tunnel.Status.Endpoint = site.Endpoint
 		tunnel.Status.BindingMode = "Bound"
+		escalateAction(&tunnel.Status.LastAction, tunnelv1alpha1.ReconcileActionAdopted)
 
 		return site, nil
 	}
 
 	// STEP 3: CREATE MODE - Create new site only if not already created
 	// Double-check by listing existing sites to avoid duplicates
+	siteName, siteType := newSiteSettings(tunnel, defaults)
 	existingSites, err := apiClient.ListSites(ctx, orgID)
 	if err != nil {
 		logger.Error(err, "Failed to list existing sites")
 	} else {
 		// Check if a site with the same name already exists
-		siteName := tunnel.Spec.SiteName
-		if siteName == "" {
-			siteName = tunnel.Name // Use tunnel name as default
-		}
-
 		for _, existingSite := range existingSites {
 			if existingSite.Name == siteName {
 				logger.Info("Found existing site with same name, using it instead of creating duplicate",
@@ -294,7 +525,9 @@ func (r *PangolinTunnelReconciler) reconcileSite(ctx context.Context, apiClient
 				tunnel.Status.NiceID = existingSite.NiceID
 				tunnel.Status.SiteName = existingSite.Name
 				tunnel.Status.SiteType = existingSite.Type
-				tunnel.Status.BindingMode = "Bound"
+				tunnel.Status.BindingMode = "Adopted"
+				escalateAction(&tunnel.Status.LastAction, tunnelv1alpha1.ReconcileActionAdopted)
+				recordAdoption(r.Recorder, tunnel, "site", strconv.Itoa(existingSite.SiteID))
 
 				return &existingSite, nil
 			}
@@ -302,16 +535,6 @@ func (r *PangolinTunnelReconciler) reconcileSite(ctx context.Context, apiClient
 	}
 
 	// STEP 4: No existing site found, create new one
-	siteName := tunnel.Spec.SiteName
-	if siteName == "" {
-		siteName = tunnel.Name
-	}
-
-	siteType := tunnel.Spec.SiteType
-	if siteType == "" {
-		siteType = "newt" // Default site type
-	}
-
 	logger.Info("Creating new site", "siteName", siteName, "siteType", siteType)
 	site, err := apiClient.CreateSite(ctx, orgID, siteName, siteType)
 	if err != nil {
@@ -328,7 +551,9 @@ func (r *PangolinTunnelReconciler) reconcileSite(ctx context.Context, apiClient
 						tunnel.Status.NiceID = existingSite.NiceID
 						tunnel.Status.SiteName = existingSite.Name
 						tunnel.Status.SiteType = existingSite.Type
-						tunnel.Status.BindingMode = "Bound"
+						tunnel.Status.BindingMode = "Adopted"
+						escalateAction(&tunnel.Status.LastAction, tunnelv1alpha1.ReconcileActionAdopted)
+						recordAdoption(r.Recorder, tunnel, "site", strconv.Itoa(existingSite.SiteID))
 						return &existingSite, nil
 					}
 				}
@@ -337,12 +562,21 @@ func (r *PangolinTunnelReconciler) reconcileSite(ctx context.Context, apiClient
 		return nil, fmt.Errorf("failed to create site: %w", err)
 	}
 
-	// Populate status with created site information
+	// Populate status with created site information, falling back to the
+	// requested settings for fields missing from the create response
+	if site.Name == "" {
+		site.Name = siteName
+	}
+	if site.Type == "" {
+		site.Type = siteType
+	}
+	completeSiteNetwork(ctx, apiClient, site)
 	tunnel.Status.SiteID = site.SiteID
 	tunnel.Status.NiceID = site.NiceID
 	tunnel.Status.SiteName = site.Name
 	tunnel.Status.SiteType = site.Type
 	tunnel.Status.BindingMode = "Created"
+	escalateAction(&tunnel.Status.LastAction, tunnelv1alpha1.ReconcileActionCreated)
 
 	return site, nil
 }
@@ -351,26 +585,124 @@ func (r *PangolinTunnelReconciler) reconcileSite(ctx context.Context, apiClient
 //
 // Newt Authentication:
 //   - Newt sites require authentication credentials for tunnel clients
-//   - Credentials are fetched from Pangolin API (site.newtId and secret material)
+//   - Credentials are taken from the site create response (site.newtId and site.newtSecretKey)
 //   - Stored in a Kubernetes Secret for use by Newt deployment
 //
-// Secret Name: <tunnel-name>-newt-credentials
+// Secret Name: <tunnel-name>-newt
 // Secret Contents:
 //   - newtId: Newt instance identifier
-//   - authToken: Authentication token for Newt client
+//   - newtSecret: Secret used by the Newt client to authenticate
+//   - endpoint: Pangolin endpoint the Newt client connects to
+//
+// The Secret is only written when its contents or owner reference change, so
+// steady-state reconciles don't churn it.
 //
-// TODO: Implement credential fetching from Pangolin API
-func (r *PangolinTunnelReconciler) reconcileNewtSecret(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel, site *pangolin.Site) error {
+// Recovery:
+//   - The Secret is owned by the tunnel, so deleting it enqueues the tunnel
+//   - The API only returns the secret on site creation, so a missing Secret
+//     triggers credential regeneration via the API
+//   - The Newt deployment is restarted to pick up regenerated credentials
+func (r *PangolinTunnelReconciler) reconcileNewtSecret(ctx context.Context, apiClient *pangolin.Client, tunnel *tunnelv1alpha1.PangolinTunnel, site *pangolin.Site) error {
 	// Only create secret for Newt sites with enabled client
 	if tunnel.Status.SiteType != "newt" || tunnel.Spec.NewtClient == nil || !tunnel.Spec.NewtClient.Enabled {
 		return nil
 	}
 
-	// TODO: Implement secret creation:
-	// 1. Fetch Newt credentials from Pangolin API
-	// 2. Create or update Secret with credentials
-	// 3. Set owner reference to tunnel for automatic cleanup
+	logger := log.FromContext(ctx)
+	secretName := fmt.Sprintf("%s-newt", tunnel.Name)
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: tunnel.Namespace, Name: secretName}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get newt secret: %w", err)
+	}
+	exists := err == nil
+
+	newtID, newtSecret := site.NewtID, site.NewtSecretKey
+	regenerated := false
+
+	if newtSecret == "" && exists && len(secret.Data[NewtSecretSecretKey]) > 0 {
+		// Site lookups never include the secret, keep the stored credentials
+		newtID, newtSecret = string(secret.Data[NewtSecretIDKey]), string(secret.Data[NewtSecretSecretKey])
+	}
+
+	if newtSecret == "" {
+		// Credentials are lost: the API never returns the secret again, so issue a new one
+		logger.Info("Newt secret missing, regenerating credentials", "secret", secretName, "siteId", site.SiteID)
+		creds, err := apiClient.RegenerateNewtCredentials(ctx, site.SiteID)
+		if err != nil {
+			return fmt.Errorf("failed to regenerate newt credentials: %w", err)
+		}
+		newtID, newtSecret = creds.NewtID, creds.Secret
+		regenerated = true
+	}
+
+	tunnel.Status.NewtID = newtID
+	tunnel.Status.NewtSecretRef = secretName
+
+	data := map[string][]byte{
+		NewtSecretIDKey:       []byte(newtID),
+		NewtSecretSecretKey:   []byte(newtSecret),
+		NewtSecretEndpointKey: []byte(apiClient.Endpoint()),
+	}
+	if exists && equality.Semantic.DeepEqual(secret.Data, data) && metav1.IsControlledBy(secret, tunnel) {
+		return nil
+	}
+
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: tunnel.Namespace,
+			},
+		}
+	}
+	secret.Data = data
+	if err := controllerutil.SetControllerReference(tunnel, secret, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on newt secret: %w", err)
+	}
+
+	if exists {
+		err = r.Update(ctx, secret)
+	} else {
+		err = r.Create(ctx, secret)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write newt secret: %w", err)
+	}
+
+	if regenerated {
+		return r.restartNewtDeployment(ctx, tunnel)
+	}
+	return nil
+}
+
+// restartNewtDeployment triggers a rollout of the Newt client Deployment by bumping
+// a pod template annotation, the same way `kubectl rollout restart` does.
+// A missing Deployment is not an error since there is nothing to restart.
+// Missing image pull secrets are reported by reconcileNewtDeployment instead, as
+// failing here would never retry the restart and leave pods on revoked
+// credentials.
+func (r *PangolinTunnelReconciler) restartNewtDeployment(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel) error {
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{
+		Namespace: tunnel.Namespace,
+		Name:      fmt.Sprintf("%s-newt-client", tunnel.Name),
+	}, deployment)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get newt deployment: %w", err)
+	}
 
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = map[string]string{}
+	}
+	deployment.Spec.Template.Annotations[NewtRestartedAtAnnotation] = time.Now().Format(time.RFC3339)
+	if err := r.Update(ctx, deployment); err != nil {
+		return fmt.Errorf("failed to restart newt deployment: %w", err)
+	}
 	return nil
 }
 
@@ -379,60 +711,363 @@ func (r *PangolinTunnelReconciler) reconcileNewtSecret(ctx context.Context, tunn
 // Newt Client Deployment:
 //   - Deploys in-cluster Newt client for tunnel connectivity
 //   - Uses credentials from reconcileNewtSecret
-//   - Configurable replica count and resources from spec.newtClient
+//   - Configurable replica count and image from spec.newtClient
 //
 // Deployment Configuration:
 //   - Name: <tunnel-name>-newt-client
-//   - Image: Newt client image from spec or default
-//   - Replicas: From spec.newtClient.replicas or default 1
-//   - Resources: From spec.newtClient.resources
+//   - Image: Newt client image from spec or DefaultNewtImage, pulled with the
+//     pull policy and pull secrets from spec; missing pull secrets are an error
+//   - Replicas: From spec.newtClient.replicas; when not set the Deployment
+//     starts with minReplicas (default 1) and is otherwise left to an autoscaler
 //   - Env: Newt credentials from secret
+//   - Resources, node selector, tolerations and affinity: From spec.newtClient,
+//     see setNewtScheduling
 //
 // Status Tracking:
-//   - readyReplicas: Number of ready Newt client replicas
-//   - Used to determine overall tunnel health
-//
-// TODO: Implement deployment creation and status tracking
+//   - readyReplicas: Number of ready Newt client replicas, see newtReadiness
+//   - NewtReady condition: Why too few replicas are ready, see setNewtReadyCondition
 func (r *PangolinTunnelReconciler) reconcileNewtDeployment(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel, site *pangolin.Site) error {
 	// Only create deployment for Newt sites with enabled client
-	if tunnel.Status.SiteType != "newt" || tunnel.Spec.NewtClient == nil || !tunnel.Spec.NewtClient.Enabled {
+	if !managesNewtClient(tunnel) {
+		tunnel.Status.ReadyReplicas = 0
+		meta.RemoveStatusCondition(&tunnel.Status.Conditions, NewtReadyCondition)
 		return nil
 	}
 
-	// TODO: Implement deployment creation:
-	// 1. Build Deployment spec with Newt client configuration
-	// 2. Create or update Deployment
-	// 3. Set owner reference to tunnel for automatic cleanup
-	// 4. Watch deployment status and update tunnel.status.readyReplicas
+	if err := r.checkImagePullSecrets(ctx, tunnel); err != nil {
+		return err
+	}
+
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{
+		Namespace: tunnel.Namespace,
+		Name:      fmt.Sprintf("%s-newt-client", tunnel.Name),
+	}, deployment)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get newt deployment: %w", err)
+	}
+	exists := err == nil
+
+	if !exists {
+		labels := map[string]string{
+			"app.kubernetes.io/name":       "newt",
+			"app.kubernetes.io/instance":   tunnel.Name,
+			"app.kubernetes.io/managed-by": "pangolin-operator",
+		}
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-newt-client", tunnel.Name),
+				Namespace: tunnel.Namespace,
+				Labels:    labels,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				},
+			},
+		}
+	}
+	before := deployment.DeepCopy()
+
+	deployment.Spec.Replicas = newtReplicas(tunnel.Spec.NewtClient, deployment.Spec.Replicas)
+	setNewtContainer(&deployment.Spec.Template.Spec, tunnel)
+	setNewtScheduling(&deployment.Spec.Template.Spec, tunnel.Spec.NewtClient)
+	if err := controllerutil.SetControllerReference(tunnel, deployment, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on newt deployment: %w", err)
+	}
+
+	switch {
+	case !exists:
+		err = r.Create(ctx, deployment)
+	case !equality.Semantic.DeepEqual(before, deployment):
+		err = r.Update(ctx, deployment)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write newt deployment: %w", err)
+	}
+
+	tunnel.Status.ReadyReplicas = deployment.Status.ReadyReplicas
+	return r.setNewtReadyCondition(ctx, tunnel, deployment)
+}
+
+// managesNewtClient reports whether the operator deploys the tunnel's Newt client
+func managesNewtClient(tunnel *tunnelv1alpha1.PangolinTunnel) bool {
+	return tunnel.Status.SiteType == "newt" && tunnel.Spec.NewtClient != nil && tunnel.Spec.NewtClient.Enabled
+}
+
+// newtMinReplicas returns the ready Newt clients required for the tunnel to be Ready
+func newtMinReplicas(spec *tunnelv1alpha1.NewtClientSpec) int32 {
+	if spec.MinReplicas != nil {
+		return *spec.MinReplicas
+	}
+	return 1
+}
+
+// newtReplicas returns the replica count of the Newt client Deployment.
+// spec.replicas wins; without it the current count is kept (e.g. set by an
+// autoscaler) but raised to minReplicas.
+func newtReplicas(spec *tunnelv1alpha1.NewtClientSpec, current *int32) *int32 {
+	if spec.Replicas != nil {
+		replicas := *spec.Replicas
+		return &replicas
+	}
+	if current != nil && *current >= newtMinReplicas(spec) {
+		return current
+	}
+	replicas := newtMinReplicas(spec)
+	return &replicas
+}
 
+// setNewtContainer sets the image, pull policy and credentials of the "newt"
+// container and the image pull secrets of the pod spec, adding the container if
+// missing. Other fields, including those defaulted by the API server, are left
+// alone.
+func setNewtContainer(podSpec *corev1.PodSpec, tunnel *tunnelv1alpha1.PangolinTunnel) {
+	image := tunnel.Spec.NewtClient.Image
+	if image == "" {
+		image = DefaultNewtImage
+	}
+
+	secretName := fmt.Sprintf("%s-newt", tunnel.Name)
+	secretEnv := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		}}
+	}
+
+	index := -1
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == "newt" {
+			index = i
+		}
+	}
+	if index < 0 {
+		podSpec.Containers = append(podSpec.Containers, corev1.Container{Name: "newt"})
+		index = len(podSpec.Containers) - 1
+	}
+
+	container := &podSpec.Containers[index]
+	container.Image = image
+	container.ImagePullPolicy = newtPullPolicy(image, tunnel.Spec.NewtClient.ImagePullPolicy)
+	podSpec.ImagePullSecrets = slices.Clone(tunnel.Spec.NewtClient.ImagePullSecrets)
+	container.Env = []corev1.EnvVar{
+		secretEnv("PANGOLIN_ENDPOINT", NewtSecretEndpointKey),
+		secretEnv("NEWT_ID", NewtSecretIDKey),
+		secretEnv("NEWT_SECRET", NewtSecretSecretKey),
+	}
+}
+
+// newtPullPolicy returns policy, or the pull policy the API server would
+// default for image, so a policy removed from the spec is reset.
+func newtPullPolicy(image string, policy corev1.PullPolicy) corev1.PullPolicy {
+	if policy != "" {
+		return policy
+	}
+	// The tag follows the last colon after the last slash, a digest pins the image
+	name, _, digested := strings.Cut(image, "@")
+	if digested {
+		return corev1.PullIfNotPresent
+	}
+	tag := ""
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		tag = name[i+1:]
+	}
+	if tag == "" || tag == "latest" {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
+// checkImagePullSecrets returns an error naming the image pull secrets of the
+// Newt client that do not exist, as the client pods could never start
+func (r *PangolinTunnelReconciler) checkImagePullSecrets(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel) error {
+	var missing []string
+	for _, ref := range tunnel.Spec.NewtClient.ImagePullSecrets {
+		err := r.Get(ctx, types.NamespacedName{Namespace: tunnel.Namespace, Name: ref.Name}, &corev1.Secret{})
+		if errors.IsNotFound(err) {
+			missing = append(missing, ref.Name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get image pull secret %s: %w", ref.Name, err)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("image pull secrets of the Newt client not found in namespace %s: %s",
+			tunnel.Namespace, strings.Join(missing, ", "))
+	}
 	return nil
 }
 
+// defaultNewtResources are the resources of the Newt client container when
+// spec.newtClient.resources is not set. Newt is a small proxy, but pods without
+// requests are the first to be evicted under node pressure.
+var defaultNewtResources = corev1.ResourceRequirements{
+	Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("10m"),
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+	},
+}
+
+// setNewtScheduling sets the resources of the "newt" container and the node
+// selector, tolerations and affinity of the pod spec from spec, replacing
+// whatever was set before so removing a field from the spec takes effect.
+// setNewtContainer must have added the container.
+func setNewtScheduling(podSpec *corev1.PodSpec, spec *tunnelv1alpha1.NewtClientSpec) {
+	spec = spec.DeepCopy()
+	resources := defaultNewtResources.DeepCopy()
+	if spec.Resources != nil {
+		resources = spec.Resources
+	}
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == "newt" {
+			podSpec.Containers[i].Resources = *resources
+		}
+	}
+
+	podSpec.NodeSelector = spec.NodeSelector
+	podSpec.Tolerations = spec.Tolerations
+	podSpec.Affinity = spec.Affinity
+}
+
+// newtReadiness reports whether a tunnel with an operator managed Newt client
+// can be Ready: at least minReplicas clients must be ready. Tunnels without a
+// managed client are always ready. The message explains what the tunnel is
+// waiting for.
+func newtReadiness(tunnel *tunnelv1alpha1.PangolinTunnel) (bool, string) {
+	if !managesNewtClient(tunnel) {
+		return true, ""
+	}
+	if minReplicas := newtMinReplicas(tunnel.Spec.NewtClient); tunnel.Status.ReadyReplicas < minReplicas {
+		return false, fmt.Sprintf("Waiting for Newt client: %d of %d required replicas ready",
+			tunnel.Status.ReadyReplicas, minReplicas)
+	}
+	return true, ""
+}
+
 // handleDeletion handles cleanup when a PangolinTunnel is being deleted.
 //
-// Cleanup Process:
-//   - Optionally delete site from Pangolin API (if created by operator)
-//   - Delete owned Newt deployment and secret (automatic via owner references)
-//   - Remove finalizer to allow tunnel deletion
-//
-// Considerations:
-//   - Sites created by operator (bindingMode: "Created") could be deleted
-//   - Sites bound by operator (bindingMode: "Bound") should not be deleted
-//   - Must be idempotent in case of failures or retries
+// Cleanup depends on the effective deletion policy:
+//   - Delete (default for bindingMode "Created"): delete the Newt Deployment and
+//     Secret the tunnel owns, then the site from the Pangolin API
+//   - Retain (default for bindingMode "Bound"): leave the site untouched, owned
+//     Kubernetes objects are garbage collected
+//   - Orphan: leave the site and remove owner references from children
 //
-// TODO: Implement optional API cleanup based on binding mode
+// The finalizer is only removed once every step succeeded. Each step tolerates
+// objects that are already gone, so a failed deletion is safely retried.
 func (r *PangolinTunnelReconciler) handleDeletion(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel) (ctrl.Result, error) {
-	// TODO: Implement cleanup logic:
-	// 1. Check if site was created (bindingMode: "Created")
-	// 2. If created, optionally delete from Pangolin API
-	// 3. Handle deletion errors gracefully
-	// 4. Ensure idempotency (don't fail if already deleted)
+	logger := log.FromContext(ctx)
+
+	policy := effectiveDeletionPolicy(tunnel.Spec.DeletionPolicy, tunnel.Status.BindingMode)
+	logger.Info("Deleting tunnel", "deletionPolicy", policy, "siteId", tunnel.Status.SiteID)
+
+	switch policy {
+	case tunnelv1alpha1.DeletionPolicyDelete:
+		if err := r.deleteNewtChildren(ctx, tunnel); err != nil {
+			logger.Error(err, "Failed to delete Newt resources")
+			return ctrl.Result{}, err
+		}
+		if err := r.deleteSite(ctx, tunnel); err != nil {
+			logger.Error(err, "Failed to delete site")
+			return ctrl.Result{}, err
+		}
+	case tunnelv1alpha1.DeletionPolicyOrphan:
+		if err := r.orphanTunnelChildren(ctx, tunnel); err != nil {
+			logger.Error(err, "Failed to orphan tunnel children")
+			return ctrl.Result{}, err
+		}
+	}
 
-	// Owned resources (Secret, Deployment) are automatically deleted by Kubernetes
+	// Remaining owned resources (Secret, Deployment) are automatically deleted by Kubernetes
+	r.backoff.Forget(client.ObjectKeyFromObject(tunnel))
 	controllerutil.RemoveFinalizer(tunnel, TunnelFinalizerName)
 	return ctrl.Result{}, r.Update(ctx, tunnel)
 }
 
+// deleteSite deletes the tunnel's site from Pangolin.
+// If the organization is gone the site can't be reached, so deletion is skipped.
+func (r *PangolinTunnelReconciler) deleteSite(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel) error {
+	logger := log.FromContext(ctx)
+
+	if tunnel.Status.SiteID == 0 {
+		return nil
+	}
+
+	org, err := r.getOrganizationForTunnel(ctx, tunnel)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("Organization not found, skipping site deletion", "siteId", tunnel.Status.SiteID)
+			return nil
+		}
+		return err
+	}
+
+	apiClient, err := r.createPangolinClientFromOrganization(ctx, org)
+	if err != nil {
+		return err
+	}
+	return apiClient.DeleteSite(ctx, tunnel.Status.SiteID)
+}
+
+// deleteNewtChildren deletes the Newt Deployment and Secret controlled by the
+// tunnel, stopping the Newt client before its site is deleted. Objects with the
+// same names that the tunnel does not control are left alone.
+func (r *PangolinTunnelReconciler) deleteNewtChildren(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel) error {
+	children := []client.Object{&appsv1.Deployment{}, &corev1.Secret{}}
+	names := []string{fmt.Sprintf("%s-newt-client", tunnel.Name), fmt.Sprintf("%s-newt", tunnel.Name)}
+	for i, child := range children {
+		err := r.Get(ctx, types.NamespacedName{Name: names[i], Namespace: tunnel.Namespace}, child)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !metav1.IsControlledBy(child, tunnel) {
+			continue
+		}
+		if err := r.Delete(ctx, child); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orphanTunnelChildren removes the tunnel's owner references from its Newt Secret,
+// Newt Deployment and any PangolinResources, so they survive the tunnel.
+func (r *PangolinTunnelReconciler) orphanTunnelChildren(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel) error {
+	children := []client.Object{&corev1.Secret{}, &appsv1.Deployment{}}
+	names := []string{fmt.Sprintf("%s-newt", tunnel.Name), fmt.Sprintf("%s-newt-client", tunnel.Name)}
+	for i, child := range children {
+		err := r.Get(ctx, types.NamespacedName{Name: names[i], Namespace: tunnel.Namespace}, child)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := removeOwnerReference(ctx, r.Client, child, tunnel); err != nil {
+			return err
+		}
+	}
+
+	resources := &tunnelv1alpha1.PangolinResourceList{}
+	if err := r.List(ctx, resources, client.InNamespace(tunnel.Namespace)); err != nil {
+		return err
+	}
+	for i := range resources.Items {
+		if err := removeOwnerReference(ctx, r.Client, &resources.Items[i], tunnel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // updateStatus updates the status of a PangolinTunnel with the given status and message.
 //
 // Status values:
@@ -441,43 +1076,103 @@ func (r *PangolinTunnelReconciler) handleDeletion(ctx context.Context, tunnel *t
 //   - "Waiting": Waiting for dependencies (organization)
 //
 // The function also updates the Ready condition with appropriate reason and message.
-// Always requeues after 1 minute for status updates.
+// Ready tunnels are requeued after 1 minute, or the shorter resync interval,
+// errors are retried with backoff.
 func (r *PangolinTunnelReconciler) updateStatus(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel, status, message string) (ctrl.Result, error) {
 	tunnel.Status.Status = status
 	tunnel.Status.ObservedGeneration = tunnel.Generation
+	tunnel.Status.LastSyncTime = syncTime(tunnel.Status.LastSyncTime, status, time.Now())
+	meta.RemoveStatusCondition(&tunnel.Status.Conditions, PausedCondition)
 
-	// Create Ready condition with proper timestamp
-	newCondition := metav1.Condition{
-		Type:               "Ready",
-		Status:             metav1.ConditionFalse,
-		Reason:             "ReconcileError",
-		Message:            message,
-		LastTransitionTime: metav1.NewTime(time.Now()),
-		ObservedGeneration: tunnel.Generation,
-	}
+	setReadyCondition(&tunnel.Status.Conditions, tunnel.Generation, status, message)
 
-	if status == "Ready" {
-		newCondition.Status = metav1.ConditionTrue
-		newCondition.Reason = "ReconcileSuccess"
+	// Ready tunnels are still polled to refresh the site's online state
+	result := ctrl.Result{RequeueAfter: r.backoff.RequeueAfter(tunnel, status)}
+	if status == "Ready" && (result.RequeueAfter == 0 || result.RequeueAfter > time.Minute) {
+		result.RequeueAfter = time.Minute
 	}
 
-	tunnel.Status.Conditions = []metav1.Condition{newCondition}
+	ctx, cancel := statusContext(ctx)
+	defer cancel()
+
+	// Skip the write when nothing changed
+	if statusUnchanged(ctx, r.Client, tunnel) {
+		return result, nil
+	}
 
 	err := r.Status().Update(ctx, tunnel)
-	return ctrl.Result{RequeueAfter: time.Minute}, err
+	return result, err
+}
+
+// updateTunnelError reports err as the Error status of tunnel. A rate limited
+// Pangolin API error makes the retry wait at least its Retry-After, see
+// errorBackoff.ObserveError.
+func (r *PangolinTunnelReconciler) updateTunnelError(ctx context.Context, tunnel *tunnelv1alpha1.PangolinTunnel, err error) (ctrl.Result, error) {
+	r.backoff.ObserveError(tunnel, err)
+	return r.updateStatus(ctx, tunnel, "Error", errorMessage(err))
+}
+
+// tunnelsForOrganization maps an organization to the tunnels referencing it.
+func (r *PangolinTunnelReconciler) tunnelsForOrganization(ctx context.Context, org client.Object) []reconcile.Request {
+	tunnels := &tunnelv1alpha1.PangolinTunnelList{}
+	if err := r.List(ctx, tunnels); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list tunnels for organization", "organization", org.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, tunnel := range tunnels.Items {
+		if matchesClass(&tunnel, r.Class) &&
+			referencesOrganization(tunnel.Spec.OrganizationRef, tunnel.Namespace, r.AllowCrossNamespaceOrg, org) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&tunnel)})
+		}
+	}
+	return requests
+}
+
+// tunnelsForPullSecret maps a Secret to the tunnels using it as an image pull
+// secret of their Newt client.
+func (r *PangolinTunnelReconciler) tunnelsForPullSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	tunnels := &tunnelv1alpha1.PangolinTunnelList{}
+	if err := r.List(ctx, tunnels, client.InNamespace(secret.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list tunnels for secret", "secret", secret.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, tunnel := range tunnels.Items {
+		if !matchesClass(&tunnel, r.Class) || tunnel.Spec.NewtClient == nil {
+			continue
+		}
+		for _, ref := range tunnel.Spec.NewtClient.ImagePullSecrets {
+			if ref.Name == secret.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&tunnel)})
+				break
+			}
+		}
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 //
 // Controller Configuration:
 //   - Watches PangolinTunnel resources for changes
-//   - Owns Secret resources (Newt credentials)
+//   - Owns Secret resources (Newt credentials); deleting the Secret re-triggers its creation
 //   - Owns Deployment resources (Newt client)
-//   - Does not watch Organizations directly (manual trigger required)
+//   - Watches Secrets and enqueues the tunnels using them as Newt client image
+//     pull secrets, so creating a missing one recovers the tunnel right away
+//   - Watches Organizations for subnet changes and enqueues the tunnels referencing them
 func (r *PangolinTunnelReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&tunnelv1alpha1.PangolinTunnel{}).
+	r.backoff = newErrorBackoff(r.ErrorRequeueInterval, r.ErrorBackoffCap, r.ResyncInterval)
+	r.clients = newPangolinClientCache()
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&tunnelv1alpha1.PangolinTunnel{}, builder.WithPredicates(classPredicate(r.Class))).
 		Owns(&corev1.Secret{}).
 		Owns(&appsv1.Deployment{}).
-		Complete(r)
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.tunnelsForPullSecret)).
+		Watches(&tunnelv1alpha1.PangolinOrganization{},
+			handler.EnqueueRequestsFromMapFunc(r.tunnelsForOrganization),
+			builder.WithPredicates(organizationSubnetChanged()))
+	return r.FullSync.watch(b, &tunnelv1alpha1.PangolinTunnelList{}, classPredicate(r.Class)).Complete(withReconcileTimeout(r, r.ReconcileTimeout))
 }
@@ -14,16 +14,30 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
 )
 
 var _ = Describe("PangolinTunnel Controller", func() {
@@ -77,4 +91,1045 @@ var _ = Describe("PangolinTunnel Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When the organization defines a site API key", func() {
+		ctx := context.Background()
+
+		It("should build the site client from siteAPIKeyRef", func() {
+			var authHeader string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				authHeader = req.Header.Get("Authorization")
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[]}}`))
+			}))
+			defer server.Close()
+
+			By("creating the default and site key secrets")
+			for name, key := range map[string]string{"default-key": "default-token", "site-key": "site-token"} {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Data:       map[string][]byte{"apiKey": []byte(key)},
+				}
+				Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, secret)
+			}
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "scoped-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "default-key"},
+						Key:                  "apiKey",
+					},
+					SiteAPIKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "site-key"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+
+			controllerReconciler := &PangolinTunnelReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			apiClient, err := controllerReconciler.createPangolinClientFromOrganization(ctx, org)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = apiClient.ListOrganizations(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authHeader).To(Equal("Bearer site-token"))
+		})
+	})
+
+	Context("When the Newt secret is deleted", func() {
+		ctx := context.Background()
+
+		It("should recreate it with regenerated credentials", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/site/7/regenerate-secret"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"newtId":"newt-1","secret":"regenerated"}}`))
+			}))
+			defer server.Close()
+
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "newt-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+					NewtClient:      &tunnelv1alpha1.NewtClientSpec{Enabled: true},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+			tunnel.Status.SiteType = "newt"
+
+			controllerReconciler := &PangolinTunnelReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			By("creating the secret from the site create response")
+			site := &pangolin.Site{SiteID: 7, NewtID: "newt-1", NewtSecretKey: "initial"}
+			Expect(controllerReconciler.reconcileNewtSecret(ctx, apiClient, tunnel, site)).To(Succeed())
+
+			secretKey := types.NamespacedName{Name: "newt-tunnel-newt", Namespace: "default"}
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			Expect(string(secret.Data[NewtSecretSecretKey])).To(Equal("initial"))
+
+			By("deleting the secret and reconciling with a site lookup that has no secret")
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			site = &pangolin.Site{SiteID: 7, NewtID: "newt-1"}
+			Expect(controllerReconciler.reconcileNewtSecret(ctx, apiClient, tunnel, site)).To(Succeed())
+
+			recreated := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretKey, recreated)).To(Succeed())
+			Expect(string(recreated.Data[NewtSecretIDKey])).To(Equal("newt-1"))
+			Expect(string(recreated.Data[NewtSecretSecretKey])).To(Equal("regenerated"))
+			Expect(recreated.OwnerReferences).To(HaveLen(1))
+		})
+	})
+
+	Context("When the Newt secret is up to date", func() {
+		ctx := context.Background()
+
+		It("should store the endpoint and not rewrite the secret", func() {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "steady-newt-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+					NewtClient:      &tunnelv1alpha1.NewtClientSpec{Enabled: true},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+			tunnel.Status.SiteType = "newt"
+
+			writes := &countingWriteClient{Client: k8sClient}
+			controllerReconciler := &PangolinTunnelReconciler{
+				Client: writes,
+				Scheme: k8sClient.Scheme(),
+			}
+			apiClient := pangolin.NewClient("https://pangolin.example.com", "token")
+
+			site := &pangolin.Site{SiteID: 8, NewtID: "newt-2", NewtSecretKey: "initial"}
+			Expect(controllerReconciler.reconcileNewtSecret(ctx, apiClient, tunnel, site)).To(Succeed())
+			Expect(writes.count).To(Equal(1))
+			Expect(tunnel.Status.NewtID).To(Equal("newt-2"))
+			Expect(tunnel.Status.NewtSecretRef).To(Equal("steady-newt-tunnel-newt"))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "steady-newt-tunnel-newt", Namespace: "default"}, secret)).To(Succeed())
+			Expect(string(secret.Data[NewtSecretEndpointKey])).To(Equal("https://pangolin.example.com"))
+
+			By("reconciling again with the same credentials and with a lookup that has no secret")
+			Expect(controllerReconciler.reconcileNewtSecret(ctx, apiClient, tunnel, site)).To(Succeed())
+			site = &pangolin.Site{SiteID: 8, NewtID: "newt-2"}
+			Expect(controllerReconciler.reconcileNewtSecret(ctx, apiClient, tunnel, site)).To(Succeed())
+			Expect(writes.count).To(Equal(1))
+		})
+	})
+
+	Context("When binding to a site that is returned with only one identifier", func() {
+		// The single-site endpoints return partial sites, only the list is complete
+		newServer := func() *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/v1/site/3":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"siteId":3,"name":"edge"}}`))
+				case "/v1/org/org1/site/brave-tiger":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"niceId":"brave-tiger","name":"edge"}}`))
+				case "/v1/org/org1/sites":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"sites":[{"siteId":3,"niceId":"brave-tiger","name":"edge"}]}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+		}
+
+		It("should populate niceId when binding by siteId", func() {
+			server := newServer()
+			defer server.Close()
+
+			siteID := 3
+			tunnel := &tunnelv1alpha1.PangolinTunnel{Spec: tunnelv1alpha1.PangolinTunnelSpec{SiteID: &siteID}}
+			site, err := (&PangolinTunnelReconciler{}).reconcileSite(context.Background(),
+				*pangolin.NewClient(server.URL, "token"), "org1", nil, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(site.NiceID).To(Equal("brave-tiger"))
+			Expect(tunnel.Status.SiteID).To(Equal(3))
+			Expect(tunnel.Status.NiceID).To(Equal("brave-tiger"))
+		})
+
+		It("should populate siteId when binding by niceId", func() {
+			server := newServer()
+			defer server.Close()
+
+			tunnel := &tunnelv1alpha1.PangolinTunnel{Spec: tunnelv1alpha1.PangolinTunnelSpec{NiceID: "brave-tiger"}}
+			_, err := (&PangolinTunnelReconciler{}).reconcileSite(context.Background(),
+				*pangolin.NewClient(server.URL, "token"), "org1", nil, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tunnel.Status.SiteID).To(Equal(3))
+			Expect(tunnel.Status.NiceID).To(Equal("brave-tiger"))
+		})
+	})
+
+	Context("When deleting a tunnel", func() {
+		ctx := context.Background()
+		var (
+			server               *httptest.Server
+			deletedSites         []string
+			controllerReconciler *PangolinTunnelReconciler
+		)
+
+		BeforeEach(func() {
+			deletedSites = nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Method == http.MethodDelete {
+					deletedSites = append(deletedSites, req.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true}`))
+			}))
+			DeferCleanup(server.Close)
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "deletion-key", Namespace: "default"},
+				Data:       map[string][]byte{"apiKey": []byte("token")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+
+			org := &tunnelv1alpha1.PangolinOrganization{
+				ObjectMeta: metav1.ObjectMeta{Name: "deletion-org", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinOrganizationSpec{
+					APIEndpoint: server.URL,
+					APIKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "deletion-key"},
+						Key:                  "apiKey",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, org)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, org)
+
+			controllerReconciler = &PangolinTunnelReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		})
+
+		// newTunnel creates a tunnel with the finalizer and the given site status
+		newTunnel := func(name, bindingMode string, policy tunnelv1alpha1.DeletionPolicy) *tunnelv1alpha1.PangolinTunnel {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  "default",
+					Finalizers: []string{TunnelFinalizerName},
+				},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "deletion-org"},
+					DeletionPolicy:  policy,
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+			tunnel.Status.SiteID = 3
+			tunnel.Status.BindingMode = bindingMode
+			return tunnel
+		}
+
+		It("should delete created sites by default", func() {
+			tunnel := newTunnel("delete-created", "Created", "")
+			_, err := controllerReconciler.handleDeletion(ctx, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedSites).To(Equal([]string{"/v1/site/3"}))
+			Expect(tunnel.Finalizers).To(BeEmpty())
+		})
+
+		It("should delete the Newt secret it created along with the site", func() {
+			tunnel := newTunnel("delete-children", "Created", "")
+
+			owned := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "delete-children-newt", Namespace: "default"}}
+			Expect(controllerutil.SetControllerReference(tunnel, owned, k8sClient.Scheme())).To(Succeed())
+			Expect(k8sClient.Create(ctx, owned)).To(Succeed())
+
+			_, err := controllerReconciler.handleDeletion(ctx, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedSites).To(Equal([]string{"/v1/site/3"}))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(owned), owned))).To(BeTrue())
+		})
+
+		It("should keep the finalizer until the site deletion succeeds", func() {
+			tunnel := newTunnel("delete-retry", "Created", "")
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			})
+
+			_, err := controllerReconciler.handleDeletion(ctx, tunnel)
+			Expect(err).To(HaveOccurred())
+			Expect(tunnel.Finalizers).To(ContainElement(TunnelFinalizerName))
+
+			By("retrying once the site is already gone")
+			server.Config.Handler = http.NotFoundHandler()
+			_, err = controllerReconciler.handleDeletion(ctx, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tunnel.Finalizers).To(BeEmpty())
+		})
+
+		It("should retain bound sites by default", func() {
+			tunnel := newTunnel("retain-bound", "Bound", "")
+			_, err := controllerReconciler.handleDeletion(ctx, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedSites).To(BeEmpty())
+			Expect(tunnel.Finalizers).To(BeEmpty())
+		})
+
+		It("should retain created sites with the Retain policy", func() {
+			tunnel := newTunnel("retain-created", "Created", tunnelv1alpha1.DeletionPolicyRetain)
+			_, err := controllerReconciler.handleDeletion(ctx, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedSites).To(BeEmpty())
+		})
+
+		It("should release child objects with the Orphan policy", func() {
+			tunnel := newTunnel("orphan-created", "Created", tunnelv1alpha1.DeletionPolicyOrphan)
+
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "orphan-created-newt", Namespace: "default"}}
+			Expect(controllerutil.SetControllerReference(tunnel, secret, k8sClient.Scheme())).To(Succeed())
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+
+			_, err := controllerReconciler.handleDeletion(ctx, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedSites).To(BeEmpty())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+			Expect(secret.OwnerReferences).To(BeEmpty())
+		})
+	})
+
+	Context("When the status has not changed between reconciles", func() {
+		It("should not write the status again", func() {
+			ctx := context.Background()
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "status-unchanged", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+
+			counter := &statusWriteCounter{Client: k8sClient}
+			controllerReconciler := &PangolinTunnelReconciler{Client: counter, Scheme: k8sClient.Scheme()}
+
+			for range 3 {
+				current := &tunnelv1alpha1.PangolinTunnel{}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(tunnel), current)).To(Succeed())
+				_, err := controllerReconciler.updateStatus(ctx, current, "Waiting", "Waiting for organization to be ready")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(counter.writes).To(Equal(1))
+
+			By("writing again once the status changes")
+			current := &tunnelv1alpha1.PangolinTunnel{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(tunnel), current)).To(Succeed())
+			_, err := controllerReconciler.updateStatus(ctx, current, "Ready", "Tunnel is ready")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counter.writes).To(Equal(2))
+		})
+	})
+
+	Context("When referencing an organization", func() {
+		ctx := context.Background()
+
+		BeforeEach(func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shared-orgs"}}
+			if err := k8sClient.Create(ctx, ns); err != nil && !errors.IsAlreadyExists(err) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			for _, namespace := range []string{"default", "shared-orgs"} {
+				org := &tunnelv1alpha1.PangolinOrganization{
+					ObjectMeta: metav1.ObjectMeta{Name: "ref-org", Namespace: namespace},
+					Spec:       tunnelv1alpha1.PangolinOrganizationSpec{APIEndpoint: "http://" + namespace},
+				}
+				Expect(k8sClient.Create(ctx, org)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, ctx, org)
+			}
+		})
+
+		tunnelWithRef := func(ref tunnelv1alpha1.LocalObjectReference) *tunnelv1alpha1.PangolinTunnel {
+			return &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "ref-tunnel", Namespace: "default"},
+				Spec:       tunnelv1alpha1.PangolinTunnelSpec{OrganizationRef: ref},
+			}
+		}
+
+		It("should resolve the organization in the tunnel's namespace by default", func() {
+			controllerReconciler := &PangolinTunnelReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			org, err := controllerReconciler.getOrganizationForTunnel(ctx,
+				tunnelWithRef(tunnelv1alpha1.LocalObjectReference{Name: "ref-org"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(org.Namespace).To(Equal("default"))
+		})
+
+		It("should resolve an organization in another namespace when allowed", func() {
+			controllerReconciler := &PangolinTunnelReconciler{
+				Client:                 k8sClient,
+				Scheme:                 k8sClient.Scheme(),
+				AllowCrossNamespaceOrg: true,
+			}
+			org, err := controllerReconciler.getOrganizationForTunnel(ctx,
+				tunnelWithRef(tunnelv1alpha1.LocalObjectReference{Name: "ref-org", Namespace: "shared-orgs"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(org.Namespace).To(Equal("shared-orgs"))
+		})
+
+		It("should reject an organization in another namespace by default", func() {
+			controllerReconciler := &PangolinTunnelReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.getOrganizationForTunnel(ctx,
+				tunnelWithRef(tunnelv1alpha1.LocalObjectReference{Name: "ref-org", Namespace: "shared-orgs"}))
+			Expect(err).To(MatchError(ContainSubstring("allow-cross-namespace-org")))
+		})
+	})
+	Context("When verifying the site in status fails", func() {
+		var (
+			server   *httptest.Server
+			status   int
+			gets     int
+			creates  int
+			tunnel   *tunnelv1alpha1.PangolinTunnel
+			resolver *PangolinTunnelReconciler
+		)
+
+		BeforeEach(func() {
+			gets, creates = 0, 0
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case req.URL.Path == "/v1/site/5":
+					gets++
+					w.WriteHeader(status)
+					_, _ = w.Write([]byte(`{"success":false,"message":"denied"}`))
+				case req.Method == http.MethodPut && req.URL.Path == "/v1/org/org1/site":
+					creates++
+					_, _ = w.Write([]byte(`{"success":true,"data":{"siteId":6,"niceId":"calm-otter","name":"edge"}}`))
+				default:
+					_, _ = w.Write([]byte(`{"success":true,"data":{"sites":[]}}`))
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			tunnel = &tunnelv1alpha1.PangolinTunnel{Spec: tunnelv1alpha1.PangolinTunnelSpec{SiteName: "edge"}}
+			tunnel.Status.SiteID = 5
+			resolver = &PangolinTunnelReconciler{}
+		})
+
+		It("should recreate a site the API reports missing", func() {
+			status = http.StatusNotFound
+			site, err := resolver.reconcileSite(context.Background(), *pangolin.NewClient(server.URL, "token"), "org1", nil, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(site.SiteID).To(Equal(6))
+			Expect(creates).To(Equal(1))
+		})
+
+		It("should not create a duplicate site when the API is unavailable", func() {
+			status = http.StatusServiceUnavailable
+			apiClient := pangolin.NewClient(server.URL, "token",
+				pangolin.WithRetryConfig(pangolin.RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+			_, err := resolver.reconcileSite(context.Background(), *apiClient, "org1", nil, tunnel)
+			Expect(err).To(HaveOccurred())
+			Expect(gets).To(Equal(2))
+			Expect(creates).To(BeZero())
+
+			var apiErr *pangolin.APIError
+			Expect(stderrors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			Expect(apiErr.Message).To(Equal("denied"))
+		})
+
+		It("should retry a transient failure before giving up on the site", func() {
+			status = http.StatusBadGateway
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/v1/site/5"))
+				gets++
+				w.Header().Set("Content-Type", "application/json")
+				if gets == 1 {
+					w.WriteHeader(status)
+					return
+				}
+				_, _ = w.Write([]byte(`{"success":true,"data":{"siteId":5,"niceId":"calm-otter","name":"edge"}}`))
+			})
+
+			apiClient := pangolin.NewClient(server.URL, "token",
+				pangolin.WithRetryConfig(pangolin.RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+			site, err := resolver.reconcileSite(context.Background(), *apiClient, "org1", nil, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(site.SiteID).To(Equal(5))
+			Expect(gets).To(Equal(2))
+		})
+
+		It("should report a rejected API key in the status message", func() {
+			status = http.StatusUnauthorized
+			_, err := resolver.reconcileSite(context.Background(), *pangolin.NewClient(server.URL, "token"), "org1", nil, tunnel)
+			Expect(pangolin.IsUnauthorized(err)).To(BeTrue())
+			Expect(creates).To(BeZero())
+			Expect(errorMessage(err)).To(ContainSubstring("check the organization's API key secret"))
+		})
+	})
+	Context("When a site with the same name already exists in Pangolin", func() {
+		It("should adopt it and report the adoption", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.Method).To(Equal(http.MethodGet))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success":true,"data":{"sites":[{"siteId":9,"niceId":"calm-otter","name":"edge"}]}}`))
+			}))
+			defer server.Close()
+
+			tunnel := &tunnelv1alpha1.PangolinTunnel{Spec: tunnelv1alpha1.PangolinTunnelSpec{SiteName: "edge"}}
+			recorder := record.NewFakeRecorder(10)
+			site, err := (&PangolinTunnelReconciler{Recorder: recorder}).reconcileSite(context.Background(),
+				*pangolin.NewClient(server.URL, "token"), "org1", nil, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(site.SiteID).To(Equal(9))
+			Expect(tunnel.Status.BindingMode).To(Equal("Adopted"))
+			Expect(tunnel.Status.LastAction).To(Equal(tunnelv1alpha1.ReconcileActionAdopted))
+			Expect(recorder.Events).To(Receive(Equal("Normal Adopted Adopted existing Pangolin site 9")))
+		})
+	})
+	Context("When the site is beyond the first page of sites", func() {
+		It("should page through the sites to find it", func() {
+			// The server caps pages at one site, below the requested limit
+			pages := []string{
+				`{"siteId":7,"niceId":"quiet-fox","name":"core"}`,
+				`{"siteId":8,"niceId":"swift-hare","name":"lab"}`,
+				`{"siteId":9,"niceId":"calm-otter","name":"edge"}`,
+			}
+			var offsets []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				offset := req.URL.Query().Get("offset")
+				offsets = append(offsets, offset)
+				var index int
+				_, _ = fmt.Sscan(offset, &index)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"success":true,"data":{"sites":[%s],"pagination":{"total":3,"limit":1,"offset":%d}}}`,
+					pages[index], index)
+			}))
+			defer server.Close()
+
+			tunnel := &tunnelv1alpha1.PangolinTunnel{Spec: tunnelv1alpha1.PangolinTunnelSpec{SiteName: "edge"}}
+			site, err := (&PangolinTunnelReconciler{Recorder: record.NewFakeRecorder(10)}).reconcileSite(context.Background(),
+				*pangolin.NewClient(server.URL, "token"), "org1", nil, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(site.SiteID).To(Equal(9))
+			Expect(offsets).To(Equal([]string{"0", "1", "2"}))
+		})
+	})
+	Context("When the Newt client runs with two replicas", func() {
+		ctx := context.Background()
+
+		It("should only be Ready once enough replicas are ready and the site is online", func() {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "ha-newt-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+					NewtClient: &tunnelv1alpha1.NewtClientSpec{
+						Enabled:     true,
+						Replicas:    ptrInt32(2),
+						MinReplicas: ptrInt32(2),
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+			tunnel.Status.SiteType = "newt"
+			tunnel.Status.NiceID = "calm-otter"
+
+			controllerReconciler := &PangolinTunnelReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			key := types.NamespacedName{Name: "ha-newt-tunnel-newt-client", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(2)))
+			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal(DefaultNewtImage))
+			Expect(metav1.IsControlledBy(deployment, tunnel)).To(BeTrue())
+
+			readyCondition := func(readyReplicas int32, online bool) *metav1.Condition {
+				deployment.Status.Replicas = 2
+				deployment.Status.ReadyReplicas = readyReplicas
+				Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+				tunnel.Status.Online = online
+
+				Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(Succeed())
+				Expect(tunnel.Status.ReadyReplicas).To(Equal(readyReplicas))
+				setSiteDegradedCondition(tunnel)
+				status, message := "Ready", "Tunnel is ready"
+				if ready, waiting := newtReadiness(tunnel); !ready {
+					status, message = "Waiting", waiting
+				}
+				_, err := controllerReconciler.updateStatus(ctx, tunnel, status, message)
+				Expect(err).NotTo(HaveOccurred())
+				return meta.FindStatusCondition(tunnel.Status.Conditions, "Ready")
+			}
+
+			cond := readyCondition(1, true)
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Message).To(Equal("Waiting for Newt client: 1 of 2 required replicas ready"))
+
+			By("staying Ready but Degraded while the site is offline")
+			cond = readyCondition(2, false)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(meta.IsStatusConditionTrue(tunnel.Status.Conditions, DegradedCondition)).To(BeTrue())
+
+			cond = readyCondition(2, true)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(meta.FindStatusCondition(tunnel.Status.Conditions, DegradedCondition)).To(BeNil())
+		})
+
+		It("should leave the replica count to an autoscaler when only minReplicas is set", func() {
+			spec := &tunnelv1alpha1.NewtClientSpec{Enabled: true, MinReplicas: ptrInt32(2)}
+			Expect(*newtReplicas(spec, nil)).To(Equal(int32(2)))
+			Expect(*newtReplicas(spec, ptrInt32(5))).To(Equal(int32(5)))
+			Expect(*newtReplicas(spec, ptrInt32(1))).To(Equal(int32(2)))
+
+			spec.Replicas = ptrInt32(3)
+			Expect(*newtReplicas(spec, ptrInt32(5))).To(Equal(int32(3)))
+		})
+	})
+
+	Context("When the Newt client sets resources and scheduling", func() {
+		ctx := context.Background()
+
+		It("should apply them to the Deployment and default the resources", func() {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "scheduled-newt-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+					NewtClient:      &tunnelv1alpha1.NewtClientSpec{Enabled: true},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+			tunnel.Status.SiteType = "newt"
+
+			controllerReconciler := &PangolinTunnelReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			deployment := &appsv1.Deployment{}
+			key := types.NamespacedName{Name: "scheduled-newt-tunnel-newt-client", Namespace: "default"}
+
+			By("requesting the default resources")
+			Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(Succeed())
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			podSpec := deployment.Spec.Template.Spec
+			Expect(podSpec.Containers[0].Resources.Requests.Memory().String()).To(Equal("64Mi"))
+			Expect(podSpec.Containers[0].Resources.Limits).To(BeEmpty())
+			Expect(podSpec.NodeSelector).To(BeEmpty())
+
+			By("applying the configured resources, node selector, tolerations and affinity")
+			tunnel.Spec.NewtClient.Resources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+			}
+			tunnel.Spec.NewtClient.NodeSelector = map[string]string{"node-role.kubernetes.io/edge": ""}
+			tunnel.Spec.NewtClient.Tolerations = []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "edge", Effect: corev1.TaintEffectNoSchedule},
+			}
+			tunnel.Spec.NewtClient.Affinity = &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+						Weight: 100,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							TopologyKey: "kubernetes.io/hostname",
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"app.kubernetes.io/instance": tunnel.Name},
+							},
+						},
+					}},
+				},
+			}
+			Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(Succeed())
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			podSpec = deployment.Spec.Template.Spec
+			Expect(podSpec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("100m"))
+			Expect(podSpec.Containers[0].Resources.Requests.Memory().IsZero()).To(BeTrue())
+			Expect(podSpec.Containers[0].Resources.Limits.Memory().String()).To(Equal("128Mi"))
+			Expect(podSpec.NodeSelector).To(HaveKey("node-role.kubernetes.io/edge"))
+			Expect(podSpec.Tolerations).To(Equal(tunnel.Spec.NewtClient.Tolerations))
+			Expect(podSpec.Affinity).To(Equal(tunnel.Spec.NewtClient.Affinity))
+
+			By("removing scheduling constraints dropped from the spec")
+			tunnel.Spec.NewtClient.NodeSelector = nil
+			tunnel.Spec.NewtClient.Tolerations = nil
+			tunnel.Spec.NewtClient.Affinity = nil
+			Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(Succeed())
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			podSpec = deployment.Spec.Template.Spec
+			Expect(podSpec.NodeSelector).To(BeEmpty())
+			Expect(podSpec.Tolerations).To(BeEmpty())
+			Expect(podSpec.Affinity).To(BeNil())
+		})
+	})
+
+	Context("When the Newt client pulls from a private registry", func() {
+		ctx := context.Background()
+
+		It("should require the pull secrets and apply them to the Deployment", func() {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "mirrored-newt-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+					NewtClient: &tunnelv1alpha1.NewtClientSpec{
+						Enabled:          true,
+						Image:            "registry.internal:5000/fosrl/newt:1.4.0",
+						ImagePullSecrets: []corev1.LocalObjectReference{{Name: "mirror-pull"}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+			tunnel.Status.SiteType = "newt"
+
+			controllerReconciler := &PangolinTunnelReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(MatchError(
+				"image pull secrets of the Newt client not found in namespace default: mirror-pull"))
+
+			By("mapping the pull secret to the tunnel once it is created")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "mirror-pull", Namespace: "default"},
+				Type:       corev1.SecretTypeDockerConfigJson,
+				Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, secret)
+			Expect(controllerReconciler.tunnelsForPullSecret(ctx, secret)).To(ConsistOf(
+				reconcile.Request{NamespacedName: client.ObjectKeyFromObject(tunnel)}))
+
+			Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(Succeed())
+			deployment := &appsv1.Deployment{}
+			key := types.NamespacedName{Name: "mirrored-newt-tunnel-newt-client", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			podSpec := deployment.Spec.Template.Spec
+			Expect(podSpec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "mirror-pull"}}))
+			Expect(podSpec.Containers[0].Image).To(Equal("registry.internal:5000/fosrl/newt:1.4.0"))
+			Expect(podSpec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+
+			By("applying an explicit pull policy")
+			tunnel.Spec.NewtClient.ImagePullPolicy = corev1.PullNever
+			Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(Succeed())
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullNever))
+
+			By("restarting on regenerated credentials even while a pull secret is missing")
+			tunnel.Spec.NewtClient.ImagePullSecrets = append(tunnel.Spec.NewtClient.ImagePullSecrets,
+				corev1.LocalObjectReference{Name: "missing-pull"})
+			Expect(controllerReconciler.restartNewtDeployment(ctx, tunnel)).To(Succeed())
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Annotations).To(HaveKey(NewtRestartedAtAnnotation))
+		})
+
+		It("should default the pull policy like the API server", func() {
+			Expect(newtPullPolicy("fosrl/newt", "")).To(Equal(corev1.PullAlways))
+			Expect(newtPullPolicy("fosrl/newt:latest", "")).To(Equal(corev1.PullAlways))
+			Expect(newtPullPolicy("registry.internal:5000/fosrl/newt", "")).To(Equal(corev1.PullAlways))
+			Expect(newtPullPolicy("fosrl/newt:1.4.0", "")).To(Equal(corev1.PullIfNotPresent))
+			Expect(newtPullPolicy("fosrl/newt@sha256:0123", "")).To(Equal(corev1.PullIfNotPresent))
+			Expect(newtPullPolicy("fosrl/newt:latest", corev1.PullNever)).To(Equal(corev1.PullNever))
+		})
+	})
+
+	Context("When the Newt client is not ready", func() {
+		ctx := context.Background()
+
+		It("should report why in the NewtReady condition", func() {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "failing-newt-tunnel", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinTunnelSpec{
+					OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+					NewtClient:      &tunnelv1alpha1.NewtClientSpec{Enabled: true},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tunnel)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, tunnel)
+			tunnel.Status.SiteType = "newt"
+
+			controllerReconciler := &PangolinTunnelReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(Succeed())
+			newtReady := func() *metav1.Condition {
+				Expect(controllerReconciler.reconcileNewtDeployment(ctx, tunnel, nil)).To(Succeed())
+				return meta.FindStatusCondition(tunnel.Status.Conditions, NewtReadyCondition)
+			}
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "failing-newt-tunnel-newt-client-abc",
+					Namespace: "default",
+					Labels: map[string]string{
+						"app.kubernetes.io/name":       "newt",
+						"app.kubernetes.io/instance":   tunnel.Name,
+						"app.kubernetes.io/managed-by": "pangolin-operator",
+					},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "newt", Image: "fosrl/newt:missing"}}},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, pod)
+
+			By("reporting an image that cannot be pulled")
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  "newt",
+				Image: "fosrl/newt:missing",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason: "ImagePullBackOff", Message: "Back-off pulling image",
+				}},
+			}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+			cond := newtReady()
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("ImagePullBackOff"))
+			Expect(cond.Message).To(Equal(
+				"Pod failing-newt-tunnel-newt-client-abc cannot pull image fosrl/newt:missing: Back-off pulling image"))
+
+			By("reporting a crash looping client")
+			pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+			pod.Status.ContainerStatuses[0].RestartCount = 4
+			pod.Status.ContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{
+				ExitCode: 1, Reason: "Error",
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+			cond = newtReady()
+			Expect(cond.Reason).To(Equal("CrashLooping"))
+			Expect(cond.Message).To(Equal(
+				"Pod failing-newt-tunnel-newt-client-abc is crash looping after 4 restarts, last exit code 1 (Error)"))
+			Expect(newtProgressing(tunnel)).To(BeFalse())
+
+			By("reporting a rollout in progress once the pods are fine")
+			pod.Status.ContainerStatuses = nil
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+			deployment := &appsv1.Deployment{}
+			key := types.NamespacedName{Name: "failing-newt-tunnel-newt-client", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			deployment.Status.Replicas = 1
+			deployment.Status.UpdatedReplicas = 1
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+			cond = newtReady()
+			Expect(cond.Reason).To(Equal("Progressing"))
+			Expect(newtProgressing(tunnel)).To(BeTrue())
+
+			By("becoming ready with enough ready replicas")
+			deployment.Status.ReadyReplicas = 1
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+			cond = newtReady()
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal("Available"))
+
+			By("removing the condition when the client is disabled")
+			tunnel.Spec.NewtClient.Enabled = false
+			Expect(newtReady()).To(BeNil())
+		})
+
+		It("should report pods rejected by a quota", func() {
+			deployment := &appsv1.Deployment{Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{{
+					Type:    appsv1.DeploymentReplicaFailure,
+					Status:  corev1.ConditionTrue,
+					Message: `pods "newt-abc" is forbidden: exceeded quota: compute`,
+				}},
+			}}
+			reason, message := newtDeploymentProblem(deployment, 1)
+			Expect(reason).To(Equal("DeploymentNotAvailable"))
+			Expect(message).To(ContainSubstring("exceeded quota"))
+		})
+	})
+
+	Context("When the organization defines site defaults", func() {
+		It("should name and type new sites from the defaults", func() {
+			var created map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/v1/org/org1/sites":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"sites":[]}}`))
+				case "/v1/org/org1/site":
+					Expect(json.NewDecoder(req.Body).Decode(&created)).To(Succeed())
+					_, _ = w.Write([]byte(`{"success":true,"data":{"siteId":5,"niceId":"calm-otter"}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			tunnel := &tunnelv1alpha1.PangolinTunnel{ObjectMeta: metav1.ObjectMeta{Name: "edge"}}
+			defaults := &tunnelv1alpha1.OrganizationDefaults{SiteType: "wireguard", SiteNamePrefix: "k8s-prod-"}
+			_, err := (&PangolinTunnelReconciler{}).reconcileSite(context.Background(),
+				*pangolin.NewClient(server.URL, "token"), "org1", defaults, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(Equal(map[string]interface{}{"name": "k8s-prod-edge", "type": "wireguard"}))
+			Expect(tunnel.Status.SiteName).To(Equal("k8s-prod-edge"))
+			Expect(tunnel.Status.SiteType).To(Equal("wireguard"))
+			Expect(tunnel.Status.BindingMode).To(Equal("Created"))
+		})
+
+		It("should prefer the tunnel's own site settings", func() {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{
+				ObjectMeta: metav1.ObjectMeta{Name: "edge"},
+				Spec:       tunnelv1alpha1.PangolinTunnelSpec{SiteName: "gateway", SiteType: "local"},
+			}
+			defaults := &tunnelv1alpha1.OrganizationDefaults{SiteType: "wireguard", SiteNamePrefix: "k8s-prod-"}
+			name, siteType := newSiteSettings(tunnel, defaults)
+			Expect(name).To(Equal("gateway"))
+			Expect(siteType).To(Equal("local"))
+
+			tunnel.Spec = tunnelv1alpha1.PangolinTunnelSpec{}
+			name, siteType = newSiteSettings(tunnel, nil)
+			Expect(name).To(Equal("edge"))
+			Expect(siteType).To(Equal("newt"))
+		})
+	})
+
+	Context("When verifying the site of a tunnel", func() {
+		var online bool
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			online = false
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"success":true,"data":{"siteId":3,"niceId":"brave-tiger","orgId":"org1","type":"newt","online":%t}}`, online)
+			}))
+			DeferCleanup(server.Close)
+		})
+
+		It("should refresh the online state and flag an offline site as degraded", func() {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{Status: tunnelv1alpha1.PangolinTunnelStatus{SiteID: 3, SiteType: "newt"}}
+			apiClient := *pangolin.NewClient(server.URL, "token")
+
+			_, err := (&PangolinTunnelReconciler{}).reconcileSite(context.Background(), apiClient, "org1", nil, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tunnel.Status.Online).To(BeFalse())
+			setSiteDegradedCondition(tunnel)
+			condition := meta.FindStatusCondition(tunnel.Status.Conditions, DegradedCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("SiteOffline"))
+			Expect(condition.Message).To(Equal("Site brave-tiger reports offline"))
+
+			online = true
+			_, err = (&PangolinTunnelReconciler{}).reconcileSite(context.Background(), apiClient, "org1", nil, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tunnel.Status.Online).To(BeTrue())
+			setSiteDegradedCondition(tunnel)
+			Expect(meta.FindStatusCondition(tunnel.Status.Conditions, DegradedCondition)).To(BeNil())
+		})
+
+		It("should reject a site that belongs to another organization", func() {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{Status: tunnelv1alpha1.PangolinTunnelStatus{SiteID: 3}}
+			_, err := (&PangolinTunnelReconciler{}).reconcileSite(context.Background(),
+				*pangolin.NewClient(server.URL, "token"), "org2", nil, tunnel)
+			Expect(err).To(MatchError("site 3 belongs to organization org1, not org2"))
+		})
+
+		It("should never flag local sites as degraded", func() {
+			tunnel := &tunnelv1alpha1.PangolinTunnel{Status: tunnelv1alpha1.PangolinTunnelStatus{SiteType: "local"}}
+			setSiteDegradedCondition(tunnel)
+			Expect(tunnel.Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	Context("When a created tunnel serves a TCP resource", func() {
+		It("should report the site endpoint as the host of the proxy endpoint", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/v1/org/org1/sites":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"sites":[]}}`))
+				case "/v1/org/org1/site":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"siteId":5,"niceId":"calm-otter","newtId":"newt1","newtSecretKey":"secret"}}`))
+				case "/v1/site/5":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"siteId":5,"niceId":"calm-otter","orgId":"org1",` +
+						`"subnet":"100.90.128.0/30","address":"100.90.128.1/30","endpoint":"203.0.113.7:51820"}}`))
+				case "/v1/resource/42":
+					_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+			apiClient := pangolin.NewClient(server.URL, "token")
+
+			tunnel := &tunnelv1alpha1.PangolinTunnel{ObjectMeta: metav1.ObjectMeta{Name: "edge"}}
+			site, err := (&PangolinTunnelReconciler{}).reconcileSite(context.Background(), *apiClient, "org1", nil, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tunnel.Status.BindingMode).To(Equal("Created"))
+			Expect(tunnel.Status.Endpoint).To(Equal("203.0.113.7:51820"))
+			Expect(tunnel.Status.Subnet).To(Equal("100.90.128.0/30"))
+			Expect(tunnel.Status.Address).To(Equal("100.90.128.1/30"))
+			Expect(site.NewtSecretKey).To(Equal("secret"))
+
+			resource := &tunnelv1alpha1.PangolinResource{
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					Protocol:    "tcp",
+					ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 25565},
+				},
+			}
+			endpoint := (&PangolinResourceReconciler{}).resolveProxyEndpoint(context.Background(),
+				apiClient, &pangolin.Resource{ID: "42"}, resource, tunnel)
+			Expect(endpoint).To(Equal("203.0.113.7:25565"))
+		})
+	})
+
 })
+
+// statusWriteCounter counts status updates made through the client
+type statusWriteCounter struct {
+	client.Client
+	writes int
+}
+
+func (c *statusWriteCounter) Status() client.SubResourceWriter {
+	return &countingStatusWriter{SubResourceWriter: c.Client.Status(), counter: c}
+}
+
+type countingStatusWriter struct {
+	client.SubResourceWriter
+	counter *statusWriteCounter
+}
+
+func (w *countingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	w.counter.writes++
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+// countingWriteClient counts creates and updates made through it
+type countingWriteClient struct {
+	client.Client
+	count int
+}
+
+func (c *countingWriteClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.count++
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *countingWriteClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.count++
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func ptrInt32(v int32) *int32 {
+	return &v
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// PausedAnnotation stops the operator from making Pangolin API calls for an object,
+// e.g. during maintenance. On an organization with spec.pauseChildren it also pauses
+// the tunnels, resources and bindings using it.
+const PausedAnnotation = "tunnel.pangolin.io/paused"
+
+// PausedCondition is set on objects whose reconciliation is paused
+const PausedCondition = "Paused"
+
+// isPaused reports whether obj carries the paused annotation.
+func isPaused(obj client.Object) bool {
+	paused, _ := strconv.ParseBool(obj.GetAnnotations()[PausedAnnotation])
+	return paused
+}
+
+// pausedMessage explains why reconciliation of obj is paused, or returns "" if it is not.
+// org may be nil if it has not been resolved yet.
+func pausedMessage(obj client.Object, org *tunnelv1alpha1.PangolinOrganization) string {
+	if isPaused(obj) {
+		return "Reconciliation paused by annotation " + PausedAnnotation
+	}
+	if org != nil && isPaused(org) && org.Spec.PauseChildren != nil && *org.Spec.PauseChildren {
+		return fmt.Sprintf("Reconciliation paused by organization %s", org.Name)
+	}
+	return ""
+}
+
+// markPaused sets the Paused condition on obj and requeues, without touching the Pangolin API.
+// conditions must point to obj's status conditions.
+func markPaused(ctx context.Context, c client.Client, obj client.Object, conditions *[]metav1.Condition, message string) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("Reconciliation paused", "reason", message)
+
+	setCondition(conditions, PausedCondition, metav1.ConditionTrue, "Paused", message, obj.GetGeneration())
+
+	result := ctrl.Result{RequeueAfter: time.Minute}
+	if statusUnchanged(ctx, c, obj) {
+		return result, nil
+	}
+	return result, c.Status().Update(ctx, obj)
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+// RateLimitLowCondition is set on organizations while the Pangolin API reports
+// fewer remaining requests than spec.rateLimitWarningThreshold
+const RateLimitLowCondition = "RateLimitLow"
+
+var (
+	// apiRateLimitRemaining and apiRateLimitLimit export the rate limit last
+	// reported by the API per organization and API key secret, as organizations
+	// and the tunnels overriding the key are limited separately.
+	apiRateLimitRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pangolin_api_rate_limit_remaining",
+			Help: "Requests remaining in the current rate limit window of the Pangolin API",
+		},
+		[]string{"namespace", "organization", "secret"},
+	)
+	apiRateLimitLimit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pangolin_api_rate_limit_limit",
+			Help: "Requests allowed per rate limit window of the Pangolin API",
+		},
+		[]string{"namespace", "organization", "secret"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRateLimitRemaining, apiRateLimitLimit)
+}
+
+// rateLimitObserver returns the observer exporting the rate limits reported to
+// the client of org using the API key in secret.
+func rateLimitObserver(org *tunnelv1alpha1.PangolinOrganization, secret string) func(pangolin.RateLimit) {
+	labels := prometheus.Labels{"namespace": org.Namespace, "organization": org.Name, "secret": secret}
+	return func(limit pangolin.RateLimit) {
+		apiRateLimitRemaining.With(labels).Set(float64(limit.Remaining))
+		if limit.Limit > 0 {
+			apiRateLimitLimit.With(labels).Set(float64(limit.Limit))
+		}
+	}
+}
+
+// deleteRateLimitMetrics drops the rate limit series of a deleted organization
+func deleteRateLimitMetrics(org *tunnelv1alpha1.PangolinOrganization) {
+	labels := prometheus.Labels{"namespace": org.Namespace, "organization": org.Name}
+	apiRateLimitRemaining.DeletePartialMatch(labels)
+	apiRateLimitLimit.DeletePartialMatch(labels)
+}
+
+// reconcileRateLimit sets the RateLimitLow condition of an organization while
+// the latest rate limit reported to its client is below the warning threshold.
+// The condition is removed without a threshold or reported rate limit.
+func reconcileRateLimit(org *tunnelv1alpha1.PangolinOrganization, apiClient *pangolin.Client) {
+	limit, ok := apiClient.RateLimit()
+	threshold := org.Spec.RateLimitWarningThreshold
+	if !ok || threshold == nil || limit.Remaining >= int(*threshold) {
+		meta.RemoveStatusCondition(&org.Status.Conditions, RateLimitLowCondition)
+		return
+	}
+
+	message := fmt.Sprintf("Pangolin API reports %d remaining requests", limit.Remaining)
+	if limit.Limit > 0 {
+		message = fmt.Sprintf("Pangolin API reports %d of %d requests remaining", limit.Remaining, limit.Limit)
+	}
+	if !limit.Reset.IsZero() {
+		message += fmt.Sprintf(", resetting at %s", limit.Reset.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	setCondition(&org.Status.Conditions, RateLimitLowCondition, metav1.ConditionTrue, "BelowThreshold",
+		message, org.Generation)
+}
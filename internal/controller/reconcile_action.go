@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// reconcileActionsTotal counts successful reconciles by the action taken on the
+// remote Pangolin object, so churn shows up as a steady rate of non-NoOp actions.
+var reconcileActionsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "pangolin_reconcile_actions_total",
+		Help: "Number of successful reconciles by the action taken on the Pangolin object",
+	},
+	[]string{"controller", "action"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileActionsTotal)
+}
+
+// actionRank orders actions by significance, a reconcile that created the
+// object and then updated its targets is reported as Created
+var actionRank = map[tunnelv1alpha1.ReconcileAction]int{
+	tunnelv1alpha1.ReconcileActionNoOp:    0,
+	tunnelv1alpha1.ReconcileActionUpdated: 1,
+	tunnelv1alpha1.ReconcileActionAdopted: 2,
+	tunnelv1alpha1.ReconcileActionCreated: 3,
+}
+
+// escalateAction records action in last unless a more significant action was
+// already recorded during this reconcile.
+func escalateAction(last *tunnelv1alpha1.ReconcileAction, action tunnelv1alpha1.ReconcileAction) {
+	if actionRank[action] >= actionRank[*last] {
+		*last = action
+	}
+}
+
+// recordReconcileAction exports the action of a successful reconcile as a metric
+func recordReconcileAction(controller string, action tunnelv1alpha1.ReconcileAction) {
+	reconcileActionsTotal.WithLabelValues(controller, string(action)).Inc()
+}
+
+// recordAdoption tells users that the operator took over an existing Pangolin
+// object found by name instead of creating it, so nobody wonders who created it.
+// It is reported as a Normal Adopted event on obj.
+func recordAdoption(recorder record.EventRecorder, obj runtime.Object, kind, id string) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(obj, corev1.EventTypeNormal, "Adopted", "Adopted existing Pangolin %s %s", kind, id)
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultReconcileTimeout is the default longest time a single reconcile may take.
+const DefaultReconcileTimeout = 5 * time.Minute
+
+// timeoutReconciler bounds every reconcile of the wrapped reconciler, so a hung
+// Pangolin API cannot pin a controller worker. The deadline cancels in-flight
+// API requests through the context; retries of a request count against it too.
+type timeoutReconciler struct {
+	reconcile.Reconciler
+	timeout time.Duration
+}
+
+// withReconcileTimeout wraps r so each reconcile is cancelled after timeout.
+// Zero leaves reconciles unbounded.
+func withReconcileTimeout(r reconcile.Reconciler, timeout time.Duration) reconcile.Reconciler {
+	if timeout <= 0 {
+		return r
+	}
+	return timeoutReconciler{Reconciler: r, timeout: timeout}
+}
+
+// Reconcile runs the wrapped reconcile under the timeout. A reconcile cut short
+// by it fails with an error saying so, and is retried with the controller's
+// rate limiting like any other error. Status is written with statusContext, so
+// the Error of a timed out reconcile is still persisted.
+func (t timeoutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	result, err := t.Reconciler.Reconcile(ctx, req)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		if err == nil {
+			err = ctx.Err()
+		}
+		return ctrl.Result{}, fmt.Errorf("reconcile of %s timed out after %s: %w", req.NamespacedName, t.timeout, err)
+	}
+	return result, err
+}
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+// pincodePattern is the PIN code format accepted by Pangolin
+var pincodePattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+// resourceAuth returns spec.httpConfig.auth of a created HTTP resource, or nil.
+// Bound resources keep their own authentication.
+func resourceAuth(resource *tunnelv1alpha1.PangolinResource) *tunnelv1alpha1.ResourceAuth {
+	if resource.Spec.BindField() != "" || resource.Spec.Protocol != "http" || resource.Spec.HTTPConfig == nil {
+		return nil
+	}
+	return resource.Spec.HTTPConfig.Auth
+}
+
+// reconcileResourceAuth applies the password and PIN code of spec.httpConfig.auth
+// to the Pangolin resource.
+//
+// The credentials are read from their Secrets on every reconcile and pushed to
+// Pangolin when the Secrets or their resource versions differ from
+// status.authSecretVersions, so rotating a Secret re-applies it. Removing a
+// reference removes that protection from the resource.
+func (r *PangolinResourceReconciler) reconcileResourceAuth(
+	ctx context.Context,
+	api *pangolin.Client,
+	resourceID string,
+	resource *tunnelv1alpha1.PangolinResource,
+) error {
+	var password, pincode, passwordVersion, pincodeVersion string
+	if auth := resourceAuth(resource); auth != nil {
+		var err error
+		if password, passwordVersion, err = r.authSecretValue(ctx, resource.Namespace, auth.PasswordSecretRef); err != nil {
+			return fmt.Errorf("failed to get password: %w", err)
+		}
+		if pincode, pincodeVersion, err = r.authSecretValue(ctx, resource.Namespace, auth.PincodeSecretRef); err != nil {
+			return fmt.Errorf("failed to get PIN code: %w", err)
+		}
+		if pincode != "" && !pincodePattern.MatchString(pincode) {
+			return fmt.Errorf("PIN code in secret %s must be 6 digits", auth.PincodeSecretRef.Name)
+		}
+	}
+
+	versions := authSecretVersions(passwordVersion, pincodeVersion)
+	if versions == resource.Status.AuthSecretVersions {
+		return nil
+	}
+
+	if password != "" || resource.Status.PasswordEnabled {
+		if err := api.SetResourcePassword(ctx, resourceID, password); err != nil {
+			return fmt.Errorf("failed to set resource password: %w", err)
+		}
+	}
+	resource.Status.PasswordEnabled = password != ""
+
+	if pincode != "" || resource.Status.PincodeEnabled {
+		if err := api.SetResourcePincode(ctx, resourceID, pincode); err != nil {
+			return fmt.Errorf("failed to set resource PIN code: %w", err)
+		}
+	}
+	resource.Status.PincodeEnabled = pincode != ""
+
+	resource.Status.AuthSecretVersions = versions
+	escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+
+	log.FromContext(ctx).Info("Applied resource authentication", "resourceID", resourceID,
+		"password", resource.Status.PasswordEnabled, "pincode", resource.Status.PincodeEnabled)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(resource, corev1.EventTypeNormal, "AuthConfigured",
+			"Authentication configured: password=%v, pincode=%v",
+			resource.Status.PasswordEnabled, resource.Status.PincodeEnabled)
+	}
+	return nil
+}
+
+// authSecretValue returns the value of a Secret key in namespace and the
+// version it was read at as <secret>/<key>@<resourceVersion>, or "" if ref is nil
+func (r *PangolinResourceReconciler) authSecretValue(
+	ctx context.Context,
+	namespace string,
+	ref *corev1.SecretKeySelector,
+) (string, string, error) {
+	if ref == nil {
+		return "", "", nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		return "", "", err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok || len(value) == 0 {
+		return "", "", fmt.Errorf("key %s not found in secret %s", ref.Key, ref.Name)
+	}
+	return string(value), fmt.Sprintf("%s/%s@%s", ref.Name, ref.Key, secret.ResourceVersion), nil
+}
+
+// authSecretVersions combines the versions of the password and PIN code
+// Secrets read by authSecretValue. No credentials combine to "".
+func authSecretVersions(passwordVersion, pincodeVersion string) string {
+	var versions []string
+	if passwordVersion != "" {
+		versions = append(versions, "password="+passwordVersion)
+	}
+	if pincodeVersion != "" {
+		versions = append(versions, "pincode="+pincodeVersion)
+	}
+	return strings.Join(versions, ",")
+}
+
+// resourcesForSecret maps a Secret to the resources in its namespace using it
+// in spec.httpConfig.auth or as the client certificate of a target, so a
+// changed password, PIN code or certificate is re-applied. Resources of the
+// organizations using it as resourceAPIKeyRef are mapped too, so a rotated key
+// is used right away.
+func (r *PangolinResourceReconciler) resourcesForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	resources := &tunnelv1alpha1.PangolinResourceList{}
+	if err := r.List(ctx, resources, client.InNamespace(secret.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list resources for secret", "secret", secret.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, resource := range resources.Items {
+		if !matchesClass(&resource, r.Class) {
+			continue
+		}
+		if usesAuthSecret(&resource, secret.GetName()) || usesClientCertSecret(&resource, secret.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&resource)})
+		}
+	}
+	return append(requests, r.resourcesForOrganizations(ctx, secret.GetNamespace(),
+		func(org *tunnelv1alpha1.PangolinOrganization) bool {
+			return org.Spec.ResourceAPIKeyRef != nil && org.Spec.ResourceAPIKeyRef.Name == secret.GetName()
+		})...)
+}
+
+// usesAuthSecret reports whether resource reads its password or PIN code from
+// the Secret name
+func usesAuthSecret(resource *tunnelv1alpha1.PangolinResource, name string) bool {
+	if resource.Spec.HTTPConfig == nil || resource.Spec.HTTPConfig.Auth == nil {
+		return false
+	}
+	auth := resource.Spec.HTTPConfig.Auth
+	return (auth.PasswordSecretRef != nil && auth.PasswordSecretRef.Name == name) ||
+		(auth.PincodeSecretRef != nil && auth.PincodeSecretRef.Name == name)
+}
+
+// usesClientCertSecret reports whether a target of resource presents the
+// client certificate of the Secret name, including the targets of spec.canary
+func usesClientCertSecret(resource *tunnelv1alpha1.PangolinResource, name string) bool {
+	targets, err := desiredTargetsForResource(resource)
+	if err != nil {
+		targets = resource.Spec.Targets
+	}
+	for _, target := range targets {
+		if target.ClientCertSecretRef != nil && target.ClientCertSecretRef.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+// RecreateAnnotation opts a PangolinResource into being recreated when Pangolin
+// cannot change its subdomain or domain in place. Recreating interrupts traffic
+// briefly, so it is never done without it.
+const RecreateAnnotation = "pangolin.io/allow-recreate"
+
+// errMigrationStarted is returned by reconcileResourceDrift once a recreate was
+// started, so the resource being replaced is persisted in status before its
+// replacement is created
+var errMigrationStarted = errors.New("resource is recreated on its new subdomain or domain")
+
+// errResourceDeleted is returned by reconcileResourceDrift when the resource in
+// status no longer exists in Pangolin and has to be created again
+var errResourceDeleted = errors.New("resource was deleted in Pangolin")
+
+// allowsRecreate reports whether the resource is annotated with RecreateAnnotation
+func allowsRecreate(resource *tunnelv1alpha1.PangolinResource) bool {
+	return resource.Annotations[RecreateAnnotation] == "true"
+}
+
+// startResourceMigration starts recreating a resource whose subdomain or domain
+// Pangolin rejected to change in place with cause.
+//
+// The current Pangolin resource and its targets are recorded in
+// status.migratingFrom and the status of the resource is reset, so the next
+// reconcile creates a new resource with the spec and configures it from
+// scratch. Only resources annotated with RecreateAnnotation and deleted by
+// their deletion policy are recreated; otherwise cause is returned explaining
+// how to proceed.
+func startResourceMigration(resource *tunnelv1alpha1.PangolinResource, cause error) error {
+	resourceID := resource.Status.ResourceID
+	if !allowsRecreate(resource) {
+		return fmt.Errorf("Pangolin cannot move resource %s to another subdomain or domain in place, "+
+			"annotate it with %s=true to recreate it, which interrupts traffic briefly: %w",
+			resourceID, RecreateAnnotation, cause)
+	}
+	policy := effectiveDeletionPolicy(resource.Spec.DeletionPolicy, resource.Status.BindingMode)
+	if policy != tunnelv1alpha1.DeletionPolicyDelete {
+		return fmt.Errorf("Pangolin cannot move resource %s to another subdomain or domain in place, "+
+			"and it is not recreated as deletionPolicy %s keeps it: %w", resourceID, policy, cause)
+	}
+
+	resource.Status.MigratingFrom = &tunnelv1alpha1.ResourceMigration{
+		ResourceID: resourceID,
+		TargetIDs:  resourceTargetIDs(resource),
+	}
+	resetResourceStatus(resource)
+	return errMigrationStarted
+}
+
+// resetResourceStatus forgets the Pangolin resource in status and everything
+// applied to it, so the next reconcile creates and configures a new one
+func resetResourceStatus(resource *tunnelv1alpha1.PangolinResource) {
+	resource.Status.ResourceID = ""
+	resource.Status.TargetID = ""
+	resource.Status.TargetIDs = nil
+	resource.Status.TargetCount = 0
+	resource.Status.AccessRuleIDs = nil
+	resource.Status.AuthSecretVersions = ""
+	resource.Status.ClientCertVersions = nil
+	resource.Status.PasswordEnabled = false
+	resource.Status.PincodeEnabled = false
+	resource.Status.SSOEnabled = false
+	resource.Status.BlockAccessEnabled = false
+	resource.Status.StickySession = false
+	resource.Status.ScheduleActive = nil
+	resource.Status.AppliedSpec = nil
+	resource.Status.RecentEvents = nil
+}
+
+// finishResourceMigration deletes the resource replaced by a recreate and its
+// targets once the new resource is configured. Deleting is idempotent, so an
+// interrupted migration is finished by the next reconcile.
+func (r *PangolinResourceReconciler) finishResourceMigration(
+	ctx context.Context,
+	api *pangolin.Client,
+	resource *tunnelv1alpha1.PangolinResource,
+) error {
+	migration := resource.Status.MigratingFrom
+	if migration == nil {
+		return nil
+	}
+	if err := deleteReplacedResource(ctx, api, resource); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Resource recreated", "from", migration.ResourceID, "to", resource.Status.ResourceID)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(resource, corev1.EventTypeNormal, "Recreated",
+			"Resource %s replaced by resource %s on its new subdomain or domain",
+			migration.ResourceID, resource.Status.ResourceID)
+	}
+	resource.Status.MigratingFrom = nil
+	return nil
+}
+
+// deleteReplacedResource deletes the resource in status.migratingFrom and its
+// targets from Pangolin, if any
+func deleteReplacedResource(ctx context.Context, api *pangolin.Client, resource *tunnelv1alpha1.PangolinResource) error {
+	migration := resource.Status.MigratingFrom
+	if migration == nil || migration.ResourceID == resource.Status.ResourceID {
+		return nil
+	}
+	for _, targetID := range migration.TargetIDs {
+		if err := api.DeleteTarget(ctx, migration.ResourceID, targetID); err != nil {
+			return fmt.Errorf("failed to delete target %s of replaced resource %s: %w", targetID, migration.ResourceID, err)
+		}
+	}
+	if err := api.DeleteResource(ctx, migration.ResourceID); err != nil {
+		return fmt.Errorf("failed to delete replaced resource %s: %w", migration.ResourceID, err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/internal/cron"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+// evaluateSchedule reports whether schedule enables a resource at now, and the
+// next time one of its windows opens or closes (zero if none ever does).
+//
+// A window is open when its end fires before its next start. Windows may
+// overlap, the resource is enabled while any of them is open.
+func evaluateSchedule(schedule *tunnelv1alpha1.ResourceSchedule, now time.Time) (active bool, next time.Time, err error) {
+	loc := time.UTC
+	if schedule.TimeZone != "" {
+		if loc, err = time.LoadLocation(schedule.TimeZone); err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid schedule time zone %q: %w", schedule.TimeZone, err)
+		}
+	}
+	now = now.In(loc)
+
+	for i, window := range schedule.Windows {
+		start, err := cron.Parse(window.Start)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid start of schedule window %d: %w", i, err)
+		}
+		end, err := cron.Parse(window.End)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid end of schedule window %d: %w", i, err)
+		}
+
+		nextStart, nextEnd := start.Next(now), end.Next(now)
+		if !nextEnd.IsZero() && (nextStart.IsZero() || nextEnd.Before(nextStart)) {
+			active = true
+		}
+		for _, boundary := range []time.Time{nextStart, nextEnd} {
+			if !boundary.IsZero() && (next.IsZero() || boundary.Before(next)) {
+				next = boundary
+			}
+		}
+	}
+	return active, next, nil
+}
+
+// resourceSuspended reports whether spec.suspend takes the resource offline.
+func resourceSuspended(resource *tunnelv1alpha1.PangolinResource) bool {
+	return resource.Spec.Suspend != nil && *resource.Spec.Suspend
+}
+
+// resourceEnabled reports whether a resource should be enabled in Pangolin:
+// spec.enabled, and with a schedule, whether it was last found active.
+func resourceEnabled(resource *tunnelv1alpha1.PangolinResource) bool {
+	if resource.Spec.Enabled != nil && !*resource.Spec.Enabled {
+		return false
+	}
+	if resource.Spec.Schedule != nil && resource.Status.ScheduleActive != nil {
+		return *resource.Status.ScheduleActive
+	}
+	return true
+}
+
+// scheduleRequeueAfter returns when to reconcile a scheduled resource again to
+// apply the next window boundary, zero without one.
+func scheduleRequeueAfter(resource *tunnelv1alpha1.PangolinResource, now time.Time) time.Duration {
+	boundary := resource.Status.NextScheduleBoundary
+	if resource.Spec.Schedule == nil || boundary == nil {
+		return 0
+	}
+	return max(boundary.Sub(now), time.Second)
+}
+
+// reconcileResourceSchedule enables or disables the Pangolin resource as
+// spec.schedule requires at now, and records the next window boundary in status.
+//
+// The enabled state is only sent when the schedule opens or closes, so manual
+// changes in Pangolin are kept until the next boundary. Removing the schedule
+// re-enables a resource the schedule disabled.
+func (r *PangolinResourceReconciler) reconcileResourceSchedule(
+	ctx context.Context,
+	api *pangolin.Client,
+	resourceID string,
+	resource *tunnelv1alpha1.PangolinResource,
+	now time.Time,
+) error {
+	schedule := resource.Spec.Schedule
+	if schedule == nil {
+		if active := resource.Status.ScheduleActive; active != nil && !*active {
+			enabled := resourceEnabled(resource)
+			if _, err := api.UpdateResource(ctx, resourceID, pangolin.ResourceUpdateSpec{Enabled: &enabled}); err != nil {
+				return fmt.Errorf("failed to re-enable resource after removing its schedule: %w", err)
+			}
+			escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+		}
+		resource.Status.ScheduleActive = nil
+		resource.Status.NextScheduleBoundary = nil
+		return nil
+	}
+
+	active, next, err := evaluateSchedule(schedule, now)
+	if err != nil {
+		return err
+	}
+	resource.Status.NextScheduleBoundary = nil
+	if !next.IsZero() {
+		resource.Status.NextScheduleBoundary = &metav1.Time{Time: next}
+	}
+
+	if previous := resource.Status.ScheduleActive; previous != nil && *previous == active {
+		return nil
+	}
+
+	enabled := active && (resource.Spec.Enabled == nil || *resource.Spec.Enabled)
+	if _, err := api.UpdateResource(ctx, resourceID, pangolin.ResourceUpdateSpec{Enabled: &enabled}); err != nil {
+		return fmt.Errorf("failed to apply schedule to resource: %w", err)
+	}
+	log.FromContext(ctx).Info("Applied resource schedule", "resourceID", resourceID, "active", active, "next", next)
+	escalateAction(&resource.Status.LastAction, tunnelv1alpha1.ReconcileActionUpdated)
+	resource.Status.ScheduleActive = &active
+
+	if r.Recorder != nil {
+		reason, state := "ScheduleClosed", "disabled"
+		if active {
+			reason, state = "ScheduleOpened", "enabled"
+		}
+		message := fmt.Sprintf("Resource %s by schedule", state)
+		if !next.IsZero() {
+			message = fmt.Sprintf("%s until %s", message, next.Format(time.RFC3339))
+		}
+		r.Recorder.Event(resource, corev1.EventTypeNormal, reason, message)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// statusWriteTimeout bounds a status write, see statusContext
+const statusWriteTimeout = 10 * time.Second
+
+// statusContext returns the context to write the status of a reconcile running
+// under ctx with. It is not cancelled with ctx, so a reconcile cut short by its
+// timeout still records the resulting Error instead of keeping its old status.
+func statusContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), statusWriteTimeout)
+}
+
+// statusUnchanged reports whether the stored copy of obj already has obj's status.
+//
+// Periodic resyncs recompute the same status over and over; skipping the write
+// when nothing changed avoids rewriting every object on every cycle. Conditions
+// must keep their LastTransitionTime for this to match.
+func statusUnchanged(ctx context.Context, c client.Reader, obj client.Object) bool {
+	current := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return false
+	}
+
+	desired, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return false
+	}
+	stored, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return false
+	}
+	return equality.Semantic.DeepEqual(desired["status"], stored["status"])
+}
+
+// lastSyncRefresh is how old status.lastSyncTime may get before a successful
+// reconcile rewrites it. Refreshing it on every reconcile would change the
+// status each time and defeat statusUnchanged.
+const lastSyncRefresh = 5 * time.Minute
+
+// syncTime returns the lastSyncTime of an object after a reconcile ending in
+// status: now if it succeeded and lastSync is older than lastSyncRefresh,
+// lastSync otherwise.
+func syncTime(lastSync *metav1.Time, status string, now time.Time) *metav1.Time {
+	if status != "Ready" && status != "Suspended" {
+		return lastSync
+	}
+	if lastSync != nil && now.Sub(lastSync.Time) < lastSyncRefresh {
+		return lastSync
+	}
+	synced := metav1.NewTime(now)
+	return &synced
+}
+
+// setCondition sets the condition of type conditionType in conditions, adding
+// it if missing. Other condition types are kept. LastTransitionTime is only
+// changed when the status flips, not when just the reason or message change.
+func setCondition(conditions *[]metav1.Condition, conditionType string, status metav1.ConditionStatus,
+	reason, message string, generation int64) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setReadyCondition sets the Ready condition from an object's status string:
+// true with reason ReconcileSuccess for "Ready", false with reason Suspended for
+// "Suspended", false with reason DryRun for "DryRun" and false with
+// ReconcileError otherwise.
+func setReadyCondition(conditions *[]metav1.Condition, generation int64, status, message string) {
+	switch status {
+	case "Ready":
+		setCondition(conditions, "Ready", metav1.ConditionTrue, "ReconcileSuccess", message, generation)
+		return
+	case "Suspended":
+		setCondition(conditions, "Ready", metav1.ConditionFalse, "Suspended", message, generation)
+		return
+	case "DryRun":
+		setCondition(conditions, "Ready", metav1.ConditionFalse, "DryRun", message, generation)
+		return
+	case "Migrating":
+		setCondition(conditions, "Ready", metav1.ConditionFalse, "Migrating", message, generation)
+		return
+	case "ServiceMissing":
+		setCondition(conditions, "Ready", metav1.ConditionFalse, "ServiceMissing", message, generation)
+		return
+	}
+	setCondition(conditions, "Ready", metav1.ConditionFalse, "ReconcileError", message, generation)
+}
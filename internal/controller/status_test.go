@@ -0,0 +1,84 @@
+/*
+Copyright (C) 2025 github.com/bovf
+
+This program is free software: it can be redistributed and/or modified under the terms of the GNU Affero General Public License as published by the Free Software Foundation, either version 3 of the License, or (at the option) any later version.
+
+This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Affero General Public License for more details.
+
+A copy of the GNU Affero General Public License should be included with this program. If not, see https://www.gnu.org/licenses/.
+
+Third‑party code bundled in this repository may be licensed under different terms (for example, Apache‑2.0 for Kubernetes libraries). Such components retain their original licenses; see the corresponding LICENSE/NOTICE files in their source directories.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Status conditions", func() {
+	Context("When setting a condition", func() {
+		It("should only change the transition time when the status flips", func() {
+			var conditions []metav1.Condition
+			setCondition(&conditions, "Ready", metav1.ConditionFalse, "ReconcileError", "failed", 1)
+			Expect(conditions).To(HaveLen(1))
+			Expect(conditions[0].LastTransitionTime.IsZero()).To(BeFalse())
+
+			earlier := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+			conditions[0].LastTransitionTime = earlier
+
+			By("keeping the transition time when only reason, message or generation change")
+			setCondition(&conditions, "Ready", metav1.ConditionFalse, "Waiting", "waiting for tunnel", 2)
+			Expect(conditions[0].LastTransitionTime).To(Equal(earlier))
+			Expect(conditions[0].Reason).To(Equal("Waiting"))
+			Expect(conditions[0].Message).To(Equal("waiting for tunnel"))
+			Expect(conditions[0].ObservedGeneration).To(Equal(int64(2)))
+
+			By("moving the transition time when the status flips")
+			setCondition(&conditions, "Ready", metav1.ConditionTrue, "ReconcileSuccess", "ready", 2)
+			Expect(conditions[0].LastTransitionTime.After(earlier.Time)).To(BeTrue())
+		})
+
+		It("should keep conditions of other types", func() {
+			conditions := []metav1.Condition{{
+				Type:               DegradedCondition,
+				Status:             metav1.ConditionTrue,
+				Reason:             "SiteOffline",
+				LastTransitionTime: metav1.Now(),
+			}}
+			setReadyCondition(&conditions, 1, "Waiting", "Waiting for organization to be ready")
+			Expect(conditions).To(HaveLen(2))
+			Expect(meta.IsStatusConditionTrue(conditions, DegradedCondition)).To(BeTrue())
+
+			ready := meta.FindStatusCondition(conditions, "Ready")
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("ReconcileError"))
+
+			setReadyCondition(&conditions, 1, "Ready", "Tunnel is ready")
+			ready = meta.FindStatusCondition(conditions, "Ready")
+			Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+			Expect(ready.Reason).To(Equal("ReconcileSuccess"))
+		})
+	})
+
+	Context("When recording the last sync time", func() {
+		It("should only refresh it after successful reconciles once it is stale", func() {
+			now := time.Now()
+			synced := syncTime(nil, "Ready", now)
+			Expect(synced).NotTo(BeNil())
+			Expect(synced.Time).To(Equal(now))
+
+			Expect(syncTime(synced, "Ready", now.Add(time.Minute))).To(BeIdenticalTo(synced))
+			Expect(syncTime(synced, "Error", now.Add(time.Hour))).To(BeIdenticalTo(synced))
+			Expect(syncTime(nil, "Waiting", now)).To(BeNil())
+
+			later := now.Add(lastSyncRefresh)
+			Expect(syncTime(synced, "Suspended", later).Time).To(Equal(later))
+		})
+	})
+})
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+// SubdomainConflictCondition is set on HTTP resources whose subdomain and domain
+// are already served by another Pangolin resource
+const SubdomainConflictCondition = "SubdomainConflict"
+
+// checkSubdomainConflict fails fast when subdomain on domainID is already served
+// by a Pangolin resource other than the one of resource, instead of letting the
+// API reject the create or update with a generic conflict.
+//
+// Before the resource is created, a Pangolin resource on the subdomain that no
+// other PangolinResource holds is not a conflict: it is adopted. Listing
+// failures are logged and leave the decision to the API.
+func (r *PangolinResourceReconciler) checkSubdomainConflict(
+	ctx context.Context,
+	api *pangolin.Client,
+	orgID string,
+	resource *tunnelv1alpha1.PangolinResource,
+	subdomain, domainID string,
+) error {
+	logger := log.FromContext(ctx)
+
+	existing, err := api.ListResources(ctx, orgID)
+	if err != nil {
+		logger.Error(err, "Failed to list resources to check for subdomain conflicts")
+		return nil
+	}
+
+	for _, other := range existing {
+		otherID := other.EffectiveID()
+		if !other.HTTP || other.Subdomain != subdomain || other.DomainID != domainID || otherID == resource.Status.ResourceID {
+			continue
+		}
+		holder, err := r.resourceHolder(ctx, otherID, resource)
+		if err != nil {
+			return err
+		}
+		if holder == "" {
+			if resource.Status.ResourceID == "" {
+				continue
+			}
+			holder = fmt.Sprintf("Pangolin resource %s (%s)", otherID, other.Name)
+		}
+
+		message := fmt.Sprintf("subdomain %q on domain %s is already used by %s", subdomain, domainID, holder)
+		setCondition(&resource.Status.Conditions, SubdomainConflictCondition, metav1.ConditionTrue, "SubdomainConflict",
+			message, resource.Generation)
+		if r.Recorder != nil {
+			r.Recorder.Event(resource, corev1.EventTypeWarning, "SubdomainConflict", message)
+		}
+		return errors.New(message)
+	}
+
+	meta.RemoveStatusCondition(&resource.Status.Conditions, SubdomainConflictCondition)
+	return nil
+}
+
+// resourceHolder names the PangolinResource other than resource that holds the
+// Pangolin resource with resourceID, as "PangolinResource namespace/name", or
+// returns "" if there is none.
+func (r *PangolinResourceReconciler) resourceHolder(
+	ctx context.Context,
+	resourceID string,
+	resource *tunnelv1alpha1.PangolinResource,
+) (string, error) {
+	resources := &tunnelv1alpha1.PangolinResourceList{}
+	if err := r.List(ctx, resources); err != nil {
+		return "", fmt.Errorf("failed to list resources: %w", err)
+	}
+	for _, other := range resources.Items {
+		if other.Status.ResourceID != resourceID || client.ObjectKeyFromObject(&other) == client.ObjectKeyFromObject(resource) {
+			continue
+		}
+		return fmt.Sprintf("PangolinResource %s/%s", other.Namespace, other.Name), nil
+	}
+	return "", nil
+}
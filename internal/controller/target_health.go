@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/pkg/pangolin"
+)
+
+// Health check settings used when the spec leaves them empty, matching the CRD defaults
+const (
+	defaultHealthCheckPath     = "/"
+	defaultHealthCheckInterval = 30
+	defaultHealthCheckTimeout  = 5
+)
+
+// targetHealthPoll is how soon a resource waiting for its targets to report
+// healthy is reconciled again
+const targetHealthPoll = 15 * time.Second
+
+// targetHealthCheck translates spec.healthCheck of a target to the health check
+// sent to Pangolin, or nil to leave it unchanged.
+func targetHealthCheck(spec tunnelv1alpha1.TargetConfig) *pangolin.TargetHealthCheck {
+	hc := spec.HealthCheck
+	if hc == nil {
+		return nil
+	}
+	if hc.Enabled != nil && !*hc.Enabled {
+		return &pangolin.TargetHealthCheck{}
+	}
+	check := &pangolin.TargetHealthCheck{
+		Enabled:  true,
+		Path:     hc.Path,
+		Interval: hc.IntervalSeconds,
+		Timeout:  hc.TimeoutSeconds,
+		Status:   hc.ExpectedStatus,
+	}
+	if check.Path == "" {
+		check.Path = defaultHealthCheckPath
+	}
+	if check.Interval == 0 {
+		check.Interval = defaultHealthCheckInterval
+	}
+	if check.Timeout == 0 {
+		check.Timeout = defaultHealthCheckTimeout
+	}
+	return check
+}
+
+// healthCheckMatches reports whether the health check of target matches spec.
+// Targets without spec.healthCheck match any health check.
+func healthCheckMatches(target pangolin.Target, spec tunnelv1alpha1.TargetConfig) bool {
+	want := targetHealthCheck(spec)
+	if want == nil {
+		return true
+	}
+	if !want.Enabled {
+		return !target.HCEnabled
+	}
+	statusMatch := (want.Status == nil) == (target.HCStatus == nil) &&
+		(want.Status == nil || *want.Status == *target.HCStatus)
+	return target.HCEnabled && target.HCPath == want.Path && target.HCInterval == want.Interval &&
+		target.HCTimeout == want.Timeout && statusMatch
+}
+
+// targetHealth returns the health Pangolin reports for the targets with a
+// health check, by target address (ip:port), or nil if there are none.
+func targetHealth(targets []pangolin.Target) map[string]string {
+	var health map[string]string
+	for _, target := range targets {
+		if !target.HCEnabled {
+			continue
+		}
+		if health == nil {
+			health = map[string]string{}
+		}
+		state := target.HCHealth
+		if state == "" {
+			state = "unknown"
+		}
+		health[fmt.Sprintf("%s:%d", target.IP, target.Port)] = state
+	}
+	return health
+}
+
+// unhealthyTargets returns the enabled targets of a resource with
+// spec.waitForHealthy whose health check does not report healthy yet, as
+// "ip:port (health)", or nil if the resource does not wait for them. It relies on
+// status.targetHealth, so it must run after the targets were reconciled.
+func unhealthyTargets(resource *tunnelv1alpha1.PangolinResource, targets []tunnelv1alpha1.TargetConfig) []string {
+	if resource.Spec.WaitForHealthy == nil || !*resource.Spec.WaitForHealthy {
+		return nil
+	}
+	var unhealthy []string
+	for _, target := range targets {
+		if !targetEnabled(resource, target) {
+			continue
+		}
+		address := fmt.Sprintf("%s:%d", target.IP, target.Port)
+		if health, checked := resource.Status.TargetHealth[address]; checked && health != "healthy" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", address, health))
+		}
+	}
+	return unhealthy
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// TargetMethodAuto is the target method that makes the operator probe the backend
+// to choose between http and https
+const TargetMethodAuto = "auto"
+
+// targetProbeTimeout bounds each connection attempt of a target probe
+const targetProbeTimeout = 3 * time.Second
+
+// targetProbe detects the method of the backend at address (host:port).
+// ok is false when the result is ambiguous, e.g. the backend is unreachable.
+type targetProbe func(ctx context.Context, address string) (method string, ok bool)
+
+// targetMethodForProtocol returns the method for targets of a resource with the
+// given protocol that do not set one.
+//
+// The organization's defaults.targetMethods entry for the protocol wins; without
+// one, http resources use http targets and tcp/udp resources use the protocol itself.
+func targetMethodForProtocol(protocol string, org *tunnelv1alpha1.PangolinOrganization) string {
+	if org != nil && org.Spec.Defaults != nil {
+		if method := org.Spec.Defaults.TargetMethods[protocol]; method != "" {
+			return method
+		}
+	}
+	switch protocol {
+	case "tcp", "udp":
+		return protocol
+	default:
+		return "http"
+	}
+}
+
+// applyDefaultTargetMethods sets the method of targets that do not set one
+func applyDefaultTargetMethods(targets []tunnelv1alpha1.TargetConfig, protocol string, org *tunnelv1alpha1.PangolinOrganization) {
+	for i := range targets {
+		if targets[i].Method == "" {
+			targets[i].Method = targetMethodForProtocol(protocol, org)
+		}
+	}
+}
+
+// resolveAutoTargetMethods replaces the auto method of targets with the method
+// detected by probe.
+//
+// Results are cached in status.probedTargetMethods, so each backend is only
+// probed once. Ambiguous results fall back to http without being cached, so the
+// backend is probed again on the next reconcile.
+func resolveAutoTargetMethods(ctx context.Context, probe targetProbe, resource *tunnelv1alpha1.PangolinResource, targets []tunnelv1alpha1.TargetConfig) {
+	logger := log.FromContext(ctx)
+
+	probed := map[string]string{}
+	for i := range targets {
+		if targets[i].Method != TargetMethodAuto {
+			continue
+		}
+		address := net.JoinHostPort(targets[i].IP, strconv.Itoa(int(targets[i].Port)))
+
+		method, cached := resource.Status.ProbedTargetMethods[address]
+		if !cached {
+			var ok bool
+			method, ok = probe(ctx, address)
+			if !ok {
+				logger.Info("Target probe was inconclusive, using http", "address", address)
+				targets[i].Method = "http"
+				continue
+			}
+			logger.Info("Probed target method", "address", address, "method", method)
+		}
+		probed[address] = method
+		targets[i].Method = method
+	}
+
+	if len(probed) == 0 {
+		probed = nil
+	}
+	resource.Status.ProbedTargetMethods = probed
+}
+
+// probeTargetMethod is the default targetProbe. A backend completing a TLS
+// handshake is https, one answering plain HTTP is http.
+func probeTargetMethod(ctx context.Context, address string) (string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, 2*targetProbeTimeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: targetProbeTimeout},
+		// Only the handshake matters, backends commonly use self-signed certificates
+		Config: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+	}
+	if conn, err := dialer.DialContext(ctx, "tcp", address); err == nil {
+		_ = conn.Close()
+		return "https", true
+	}
+
+	client := &http.Client{Timeout: targetProbeTimeout}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, "http://"+address+"/", nil)
+	if err != nil {
+		return "", false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false
+	}
+	_ = resp.Body.Close()
+	return "http", true
+}
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// tunnelKey resolves a tunnel reference made from an object in namespace.
+//
+// The tunnel defaults to the referencing object's namespace. Referencing a
+// tunnel in another namespace, e.g. a central one shared by team namespaces,
+// exposes workloads through its site and organization, so it is only allowed
+// when allowCrossNamespace is set.
+func tunnelKey(ref tunnelv1alpha1.LocalObjectReference, namespace string, allowCrossNamespace bool) (types.NamespacedName, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+	if ref.Namespace == "" || ref.Namespace == namespace {
+		return key, nil
+	}
+	if !allowCrossNamespace {
+		return key, fmt.Errorf("tunnel %s/%s is in another namespace; cross-namespace tunnel references are disabled (--allow-cross-namespace-tunnel)",
+			ref.Namespace, ref.Name)
+	}
+	key.Namespace = ref.Namespace
+	return key, nil
+}
@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string
+	}{
+		{name: "weekdays by name", expr: "0 8 * * MON-FRI"},
+		{name: "lists, ranges and steps", expr: "*/15 9-17 1,15 * *"},
+		{name: "month names and sunday as 7", expr: "0 0 * jan-jun 7"},
+		{name: "too few fields", expr: "0 8 * *", wantErr: "expected 5 fields"},
+		{name: "too many fields", expr: "0 8 * * * *", wantErr: "expected 5 fields"},
+		{name: "minute out of range", expr: "60 * * * *", wantErr: `invalid value "60" in minute field, expected 0-59`},
+		{name: "hour out of range", expr: "* 24 * * *", wantErr: `invalid value "24" in hour field, expected 0-23`},
+		{name: "day of month out of range", expr: "* * 0 * *", wantErr: `invalid value "0" in day of month field, expected 1-31`},
+		{name: "month out of range", expr: "* * * 13 *", wantErr: `invalid value "13" in month field, expected 1-12`},
+		{name: "day of week out of range", expr: "* * * * 1-8", wantErr: `invalid value "8" in day of week field, expected 0-7`},
+		{name: "unknown name", expr: "* * * foo *", wantErr: `invalid value "foo" in month field`},
+		{name: "zero step", expr: "*/0 * * * *", wantErr: `invalid step "0" in minute field`},
+		{name: "non-numeric step", expr: "*/x * * * *", wantErr: `invalid step "x" in minute field`},
+		{name: "inverted range", expr: "5-1 * * * *", wantErr: `invalid range "5-1" in minute field`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Parse(%q) error = %v", tt.expr, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want it to contain %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	local := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{name: "next minute", expr: "* * * * *", from: utc(2026, 10, 16, 9, 0), want: utc(2026, 10, 16, 9, 1)},
+		{name: "seconds are truncated", expr: "* * * * *", from: utc(2026, 10, 16, 9, 0).Add(30 * time.Second),
+			want: utc(2026, 10, 16, 9, 1)},
+		{name: "later the same day", expr: "30 17 * * *", from: utc(2026, 10, 16, 9, 0), want: utc(2026, 10, 16, 17, 30)},
+		{name: "next month", expr: "0 0 1 * *", from: utc(2026, 1, 31, 12, 0), want: utc(2026, 2, 1, 0, 0)},
+		{name: "skips months without the day", expr: "0 0 31 * *", from: utc(2026, 4, 1, 0, 0), want: utc(2026, 5, 31, 0, 0)},
+		{name: "next year", expr: "30 23 31 12 *", from: utc(2026, 12, 31, 23, 30), want: utc(2027, 12, 31, 23, 30)},
+		{name: "leap day", expr: "0 0 29 2 *", from: utc(2026, 3, 1, 0, 0), want: utc(2028, 2, 29, 0, 0)},
+		// 2026-10-16 is a Friday
+		{name: "weekdays over the weekend", expr: "0 8 * * 1-5", from: utc(2026, 10, 16, 9, 0), want: utc(2026, 10, 19, 8, 0)},
+		{name: "sunday as 7", expr: "0 0 * * 7", from: utc(2026, 10, 16, 0, 0), want: utc(2026, 10, 18, 0, 0)},
+		{name: "weekday across a month", expr: "0 0 * * MON", from: utc(2026, 10, 27, 0, 0), want: utc(2026, 11, 2, 0, 0)},
+		{name: "day of month or day of week", expr: "0 0 13 * FRI", from: utc(2026, 10, 16, 0, 0), want: utc(2026, 10, 23, 0, 0)},
+		{name: "day of week restricted by month", expr: "0 0 * 2 MON", from: utc(2026, 10, 16, 0, 0), want: utc(2027, 2, 1, 0, 0)},
+		{name: "never fires", expr: "0 0 30 2 *", from: utc(2026, 10, 16, 0, 0), want: time.Time{}},
+		// Europe/Berlin switches to CEST on 2026-03-29 and back to CET on 2026-10-25
+		{name: "into summer time", expr: "0 8 * * *", from: local(2026, 3, 28, 8, 0), want: local(2026, 3, 29, 8, 0)},
+		{name: "into winter time", expr: "0 8 * * *", from: local(2026, 10, 24, 8, 0), want: local(2026, 10, 25, 8, 0)},
+		{name: "skipped hour", expr: "30 2 * * *", from: local(2026, 3, 28, 3, 0), want: local(2026, 3, 30, 2, 30)},
+		{name: "after the skipped hour", expr: "0 3 * * *", from: local(2026, 3, 29, 1, 0), want: local(2026, 3, 29, 3, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if got := e.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextKeepsLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	e, err := Parse("0 8 * * *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// 2026-03-29 has 23 hours in Berlin, 2026-10-25 has 25
+	tests := []struct {
+		from time.Time
+		want time.Duration
+	}{
+		{from: time.Date(2026, 3, 28, 8, 0, 0, 0, berlin), want: 23 * time.Hour},
+		{from: time.Date(2026, 10, 24, 8, 0, 0, 0, berlin), want: 25 * time.Hour},
+	}
+	for _, tt := range tests {
+		got := e.Next(tt.from)
+		if got.Location() != berlin {
+			t.Errorf("Next(%v) location = %v, want %v", tt.from, got.Location(), berlin)
+		}
+		if d := got.Sub(tt.from); d != tt.want {
+			t.Errorf("Next(%v) = %v, %v later, want %v later", tt.from, got, d, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// SetupPangolinBindingWebhookWithManager registers the PangolinBinding webhooks with the manager.
+func SetupPangolinBindingWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&tunnelv1alpha1.PangolinBinding{}).
+		WithValidator(&PangolinBindingCustomValidator{Reader: mgr.GetAPIReader()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-tunnel-pangolin-io-v1alpha1-pangolinbinding,mutating=false,failurePolicy=fail,sideEffects=None,groups=tunnel.pangolin.io,resources=pangolinbindings,verbs=create;update,versions=v1alpha1,name=vpangolinbinding-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PangolinBindingCustomValidator validates PangolinBinding objects on create and update.
+//
+// spec.protocol must be allowed by the namespace's protocol policy, see
+// AllowedProtocolsAnnotation, and spec.httpConfig or spec.proxyConfig must
+// match it, so the binding does not produce a malformed PangolinResource.
+type PangolinBindingCustomValidator struct {
+	// Reader is used to read namespace policies
+	Reader client.Reader
+}
+
+var _ webhook.CustomValidator = &PangolinBindingCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *PangolinBindingCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	binding, ok := obj.(*tunnelv1alpha1.PangolinBinding)
+	if !ok {
+		return nil, fmt.Errorf("expected a PangolinBinding object but got %T", obj)
+	}
+	return nil, v.validateBinding(ctx, binding, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator. The protocol policy is only
+// checked when the protocol changes, so tightening a namespace policy does not
+// block updates (e.g. finalizer removal) of existing bindings. Likewise the
+// protocol configuration is only checked when it or the protocol changes.
+func (v *PangolinBindingCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	binding, ok := newObj.(*tunnelv1alpha1.PangolinBinding)
+	if !ok {
+		return nil, fmt.Errorf("expected a PangolinBinding object for the newObj but got %T", newObj)
+	}
+	oldBinding, ok := oldObj.(*tunnelv1alpha1.PangolinBinding)
+	if !ok {
+		return nil, fmt.Errorf("expected a PangolinBinding object for the oldObj but got %T", oldObj)
+	}
+	return nil, v.validateBinding(ctx, binding, oldBinding)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletion is always allowed.
+func (v *PangolinBindingCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateBinding checks spec.protocol against the namespace protocol policy and
+// the protocol configuration against spec.protocol. On update oldBinding is the
+// stored binding, and only the parts that changed are checked.
+func (v *PangolinBindingCustomValidator) validateBinding(ctx context.Context, binding, oldBinding *tunnelv1alpha1.PangolinBinding) error {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+	protocolChanged := oldBinding == nil || oldBinding.Spec.Protocol != binding.Spec.Protocol
+
+	if protocolChanged {
+		fieldErr, err := validateProtocolPolicy(ctx, v.Reader, binding.Namespace, binding.Spec.Protocol,
+			specPath.Child("protocol"))
+		if err != nil {
+			return err
+		}
+		if fieldErr != nil {
+			errs = append(errs, fieldErr)
+		}
+	}
+
+	if protocolChanged ||
+		!equality.Semantic.DeepEqual(oldBinding.Spec.HTTPConfig, binding.Spec.HTTPConfig) ||
+		!equality.Semantic.DeepEqual(oldBinding.Spec.ProxyConfig, binding.Spec.ProxyConfig) {
+		errs = append(errs, validateProtocolConfig(specPath, binding.Spec.Protocol,
+			binding.Spec.HTTPConfig, binding.Spec.ProxyConfig)...)
+	}
+
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(tunnelv1alpha1.GroupVersion.WithKind("PangolinBinding").GroupKind(),
+			binding.Name, errs)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright (C) 2025 github.com/bovf
+
+This program is free software: it can be redistributed and/or modified under the terms of the GNU Affero General Public License as published by the Free Software Foundation, either version 3 of the License, or (at the option) any later version.
+
+This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Affero General Public License for more details.
+
+A copy of the GNU Affero General Public License should be included with this program. If not, see https://www.gnu.org/licenses/.
+
+Third‑party code bundled in this repository may be licensed under different terms (for example, Apache‑2.0 for Kubernetes libraries). Such components retain their original licenses; see the corresponding LICENSE/NOTICE files in their source directories.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+var _ = Describe("PangolinBinding Webhook", func() {
+	validator := &PangolinBindingCustomValidator{Reader: namespaceReader{}}
+	httpConfig := &tunnelv1alpha1.HTTPConfig{Subdomain: "app"}
+	proxyConfig := &tunnelv1alpha1.ProxyConfig{ProxyPort: 5432}
+
+	newBinding := func(protocol string, http *tunnelv1alpha1.HTTPConfig, proxy *tunnelv1alpha1.ProxyConfig) *tunnelv1alpha1.PangolinBinding {
+		return &tunnelv1alpha1.PangolinBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: tunnelv1alpha1.PangolinBindingSpec{
+				Protocol:    protocol,
+				HTTPConfig:  http,
+				ProxyConfig: proxy,
+			},
+		}
+	}
+
+	DescribeTable("validating the protocol configuration",
+		func(protocol string, http *tunnelv1alpha1.HTTPConfig, proxy *tunnelv1alpha1.ProxyConfig, message string) {
+			_, err := validator.ValidateCreate(context.Background(), newBinding(protocol, http, proxy))
+			if message == "" {
+				Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("http with httpConfig", "http", httpConfig, nil, ""),
+		Entry("http without httpConfig", "http", nil, nil, "spec.httpConfig: Required value: required for protocol http"),
+		Entry("http with proxyConfig", "http", httpConfig, proxyConfig, "spec.proxyConfig: Forbidden: not allowed for protocol http"),
+		Entry("tcp with proxyConfig", "tcp", nil, proxyConfig, ""),
+		Entry("tcp without proxyConfig", "tcp", nil, nil, "spec.proxyConfig: Required value: required for protocol tcp"),
+		Entry("tcp with httpConfig", "tcp", httpConfig, proxyConfig, "spec.httpConfig: Forbidden: not allowed for protocol tcp"),
+		Entry("udp with proxyConfig", "udp", nil, proxyConfig, ""),
+		Entry("udp with httpConfig instead of proxyConfig", "udp", httpConfig, nil, "spec.proxyConfig: Required value"),
+	)
+
+	It("should not block updates of an existing malformed binding that keep its configuration", func() {
+		binding := newBinding("tcp", nil, nil)
+		updated := binding.DeepCopy()
+		updated.Finalizers = []string{"tunnel.pangolin.io/finalizer"}
+		_, err := validator.ValidateUpdate(context.Background(), binding, updated)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated.Spec.Protocol = "http"
+		_, err = validator.ValidateUpdate(context.Background(), binding, updated)
+		Expect(err).To(MatchError(ContainSubstring("spec.httpConfig: Required value")))
+	})
+})
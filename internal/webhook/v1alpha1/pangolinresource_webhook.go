@@ -0,0 +1,230 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+	"github.com/bovf/pangolin-operator/internal/cron"
+)
+
+// SetupPangolinResourceWebhookWithManager registers the PangolinResource webhooks with the manager.
+func SetupPangolinResourceWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&tunnelv1alpha1.PangolinResource{}).
+		WithValidator(&PangolinResourceCustomValidator{Reader: mgr.GetAPIReader()}).
+		WithDefaulter(&PangolinResourceCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-tunnel-pangolin-io-v1alpha1-pangolinresource,mutating=true,failurePolicy=fail,sideEffects=None,groups=tunnel.pangolin.io,resources=pangolinresources,verbs=create;update,versions=v1alpha1,name=mpangolinresource-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PangolinResourceCustomDefaulter fills in the obvious values of PangolinResource
+// objects on create and update: proxyConfig.enableProxy defaults to true.
+//
+// Target methods are not defaulted here. The controller resolves them on every
+// reconcile from the organization's defaults.targetMethods, so changing those
+// defaults still reaches targets without a method.
+type PangolinResourceCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &PangolinResourceCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter.
+func (d *PangolinResourceCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	resource, ok := obj.(*tunnelv1alpha1.PangolinResource)
+	if !ok {
+		return fmt.Errorf("expected a PangolinResource object but got %T", obj)
+	}
+
+	if resource.Spec.ProxyConfig != nil && resource.Spec.ProxyConfig.EnableProxy == nil {
+		enableProxy := true
+		resource.Spec.ProxyConfig.EnableProxy = &enableProxy
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-tunnel-pangolin-io-v1alpha1-pangolinresource,mutating=false,failurePolicy=fail,sideEffects=None,groups=tunnel.pangolin.io,resources=pangolinresources,verbs=create;update,versions=v1alpha1,name=vpangolinresource-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PangolinResourceCustomValidator validates PangolinResource objects on create and update.
+//
+// spec.protocol must be allowed by the namespace's protocol policy, see
+// AllowedProtocolsAnnotation, and match spec.httpConfig and spec.proxyConfig.
+// spec.schedule must use valid cron expressions and a known time zone. Create
+// configuration next to spec.resourceId, spec.bindByName or
+// spec.bindBySubdomain is accepted with a warning, as it is
+// ignored while the resource is bound.
+type PangolinResourceCustomValidator struct {
+	// Reader is used to read namespace policies
+	Reader client.Reader
+}
+
+var _ webhook.CustomValidator = &PangolinResourceCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *PangolinResourceCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	resource, ok := obj.(*tunnelv1alpha1.PangolinResource)
+	if !ok {
+		return nil, fmt.Errorf("expected a PangolinResource object but got %T", obj)
+	}
+	return bindConflictWarnings(resource), v.validateResource(ctx, resource, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator. The protocol policy is only
+// checked when the protocol changes, so tightening a namespace policy does not
+// block updates (e.g. finalizer removal) of existing resources. Likewise the
+// schedule is only checked when it changes.
+func (v *PangolinResourceCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	resource, ok := newObj.(*tunnelv1alpha1.PangolinResource)
+	if !ok {
+		return nil, fmt.Errorf("expected a PangolinResource object for the newObj but got %T", newObj)
+	}
+	oldResource, ok := oldObj.(*tunnelv1alpha1.PangolinResource)
+	if !ok {
+		return nil, fmt.Errorf("expected a PangolinResource object for the oldObj but got %T", oldObj)
+	}
+	return bindConflictWarnings(resource), v.validateResource(ctx, resource, oldResource)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletion is always allowed.
+func (v *PangolinResourceCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateResource checks spec.protocol against the namespace protocol policy
+// and the protocol configuration, and validates spec.schedule,
+// spec.httpConfig.accessRules and the target health checks. On update
+// oldResource is the stored resource, and only the parts that changed are
+// checked. The protocol configuration of bound resources is ignored, so it is
+// not checked for them.
+func (v *PangolinResourceCustomValidator) validateResource(ctx context.Context, resource, oldResource *tunnelv1alpha1.PangolinResource) error {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if oldResource == nil || oldResource.Spec.Protocol != resource.Spec.Protocol {
+		fieldErr, err := validateProtocolPolicy(ctx, v.Reader, resource.Namespace, resource.Spec.Protocol,
+			specPath.Child("protocol"))
+		if err != nil {
+			return err
+		}
+		if fieldErr != nil {
+			errs = append(errs, fieldErr)
+		}
+	}
+
+	if resource.Spec.BindField() == "" && (oldResource == nil ||
+		oldResource.Spec.Protocol != resource.Spec.Protocol ||
+		!equality.Semantic.DeepEqual(oldResource.Spec.HTTPConfig, resource.Spec.HTTPConfig) ||
+		!equality.Semantic.DeepEqual(oldResource.Spec.ProxyConfig, resource.Spec.ProxyConfig)) {
+		errs = append(errs, validateProtocolConfig(specPath, resource.Spec.Protocol,
+			resource.Spec.HTTPConfig, resource.Spec.ProxyConfig)...)
+	}
+
+	if oldResource == nil || !equality.Semantic.DeepEqual(oldResource.Spec.Schedule, resource.Spec.Schedule) {
+		errs = append(errs, validateSchedule(specPath.Child("schedule"), resource.Spec.Schedule)...)
+	}
+
+	if resource.Spec.HTTPConfig != nil && (oldResource == nil ||
+		!equality.Semantic.DeepEqual(oldResource.Spec.HTTPConfig, resource.Spec.HTTPConfig)) {
+		errs = append(errs, validateAccessRules(specPath.Child("httpConfig", "accessRules"),
+			resource.Spec.HTTPConfig.AccessRules)...)
+	}
+
+	if oldResource == nil || oldResource.Spec.Protocol != resource.Spec.Protocol ||
+		!equality.Semantic.DeepEqual(oldResource.Spec.Targets, resource.Spec.Targets) {
+		errs = append(errs, validateHealthChecks(specPath.Child("targets"), resource.Spec.Protocol,
+			resource.Spec.Targets)...)
+	}
+
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(tunnelv1alpha1.GroupVersion.WithKind("PangolinResource").GroupKind(),
+			resource.Name, errs)
+	}
+	return nil
+}
+
+// validateSchedule checks the cron expressions and time zone of a schedule
+func validateSchedule(path *field.Path, schedule *tunnelv1alpha1.ResourceSchedule) field.ErrorList {
+	if schedule == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if schedule.TimeZone != "" {
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			errs = append(errs, field.Invalid(path.Child("timeZone"), schedule.TimeZone, "unknown time zone"))
+		}
+	}
+	for i, window := range schedule.Windows {
+		windowPath := path.Child("windows").Index(i)
+		if _, err := cron.Parse(window.Start); err != nil {
+			errs = append(errs, field.Invalid(windowPath.Child("start"), window.Start, err.Error()))
+		}
+		if _, err := cron.Parse(window.End); err != nil {
+			errs = append(errs, field.Invalid(windowPath.Child("end"), window.End, err.Error()))
+		}
+	}
+	return errs
+}
+
+// validateAccessRules checks that the values of cidr access rules are CIDR ranges
+func validateAccessRules(path *field.Path, rules []tunnelv1alpha1.AccessRule) field.ErrorList {
+	var errs field.ErrorList
+	for i, rule := range rules {
+		if rule.Match != "cidr" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(rule.Value); err != nil {
+			errs = append(errs, field.Invalid(path.Index(i).Child("value"), rule.Value,
+				"must be a CIDR range such as 10.0.0.0/8"))
+		}
+	}
+	return errs
+}
+
+// validateHealthChecks checks that target health checks are only set on http
+// resources, as Pangolin probes over HTTP, and time out before the next check
+// is due
+func validateHealthChecks(path *field.Path, protocol string, targets []tunnelv1alpha1.TargetConfig) field.ErrorList {
+	var errs field.ErrorList
+	for i, target := range targets {
+		hc := target.HealthCheck
+		if hc != nil && (protocol == "tcp" || protocol == "udp") {
+			errs = append(errs, field.Forbidden(path.Index(i).Child("healthCheck"),
+				fmt.Sprintf("health checks probe over HTTP and are not supported on %s resources", protocol)))
+			continue
+		}
+		if hc == nil || hc.IntervalSeconds == 0 || hc.TimeoutSeconds < hc.IntervalSeconds {
+			continue
+		}
+		errs = append(errs, field.Invalid(path.Index(i).Child("healthCheck", "timeoutSeconds"), hc.TimeoutSeconds,
+			fmt.Sprintf("must be less than intervalSeconds (%d)", hc.IntervalSeconds)))
+	}
+	return errs
+}
+
+// bindConflictWarnings warns about create configuration that is ignored because
+// spec.resourceId, spec.bindByName or spec.bindBySubdomain binds the resource to
+// an existing Pangolin resource
+func bindConflictWarnings(resource *tunnelv1alpha1.PangolinResource) admission.Warnings {
+	bindField := resource.Spec.BindField()
+	if bindField == "" {
+		return nil
+	}
+	var warnings admission.Warnings
+	if resource.Spec.HTTPConfig != nil {
+		warnings = append(warnings, fmt.Sprintf("spec.httpConfig is ignored because %s binds to an existing resource", bindField))
+	}
+	if resource.Spec.ProxyConfig != nil {
+		warnings = append(warnings, fmt.Sprintf("spec.proxyConfig is ignored because %s binds to an existing resource", bindField))
+	}
+	return warnings
+}
@@ -0,0 +1,216 @@
+/*
+Copyright (C) 2025 github.com/bovf
+
+This program is free software: it can be redistributed and/or modified under the terms of the GNU Affero General Public License as published by the Free Software Foundation, either version 3 of the License, or (at the option) any later version.
+
+This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Affero General Public License for more details.
+
+A copy of the GNU Affero General Public License should be included with this program. If not, see https://www.gnu.org/licenses/.
+
+Third‑party code bundled in this repository may be licensed under different terms (for example, Apache‑2.0 for Kubernetes libraries). Such components retain their original licenses; see the corresponding LICENSE/NOTICE files in their source directories.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+var _ = Describe("PangolinResource Webhook", func() {
+	validator := &PangolinResourceCustomValidator{Reader: namespaceReader{}}
+
+	newResource := func(schedule *tunnelv1alpha1.ResourceSchedule) *tunnelv1alpha1.PangolinResource {
+		return &tunnelv1alpha1.PangolinResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "default"},
+			Spec: tunnelv1alpha1.PangolinResourceSpec{
+				Protocol:   "http",
+				HTTPConfig: &tunnelv1alpha1.HTTPConfig{Subdomain: "dev"},
+				Schedule:   schedule,
+			},
+		}
+	}
+	window := func(start, end string) []tunnelv1alpha1.ScheduleWindow {
+		return []tunnelv1alpha1.ScheduleWindow{{Start: start, End: end}}
+	}
+
+	Context("When validating a schedule", func() {
+		It("should admit valid cron expressions and time zones", func() {
+			_, err := validator.ValidateCreate(context.Background(), newResource(&tunnelv1alpha1.ResourceSchedule{
+				Windows:  window("0 8 * * MON-FRI", "30 17 * * 1-5"),
+				TimeZone: "Europe/Berlin",
+			}))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = validator.ValidateCreate(context.Background(), newResource(&tunnelv1alpha1.ResourceSchedule{
+				Windows: window("*/15 9-17 1,15 * *", "0 0 * jan-jun 7"),
+			}))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject malformed cron expressions with the offending field", func() {
+			_, err := validator.ValidateCreate(context.Background(), newResource(&tunnelv1alpha1.ResourceSchedule{
+				Windows: window("0 8 * *", "0 18 * * 1-8"),
+			}))
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.schedule.windows[0].start: Invalid value: \"0 8 * *\": expected 5 fields"))
+			Expect(err.Error()).To(ContainSubstring(`spec.schedule.windows[0].end: Invalid value: "0 18 * * 1-8": invalid value "8" in day of week field`))
+		})
+
+		It("should reject an unknown time zone", func() {
+			_, err := validator.ValidateCreate(context.Background(), newResource(&tunnelv1alpha1.ResourceSchedule{
+				Windows:  window("0 8 * * *", "0 18 * * *"),
+				TimeZone: "Mars/Olympus",
+			}))
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule.timeZone: Invalid value: \"Mars/Olympus\": unknown time zone")))
+		})
+
+		It("should only check the schedule on update when it changes", func() {
+			resource := newResource(&tunnelv1alpha1.ResourceSchedule{Windows: window("0 8 * * *", "bad")})
+			_, err := validator.ValidateUpdate(context.Background(), resource, resource.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := resource.DeepCopy()
+			updated.Spec.Schedule.Windows[0].Start = "0 9 * * *"
+			_, err = validator.ValidateUpdate(context.Background(), resource, updated)
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule.windows[0].end")))
+		})
+	})
+
+	Context("When validating the protocol configuration", func() {
+		It("should reject an http resource without httpConfig", func() {
+			resource := newResource(nil)
+			resource.Spec.HTTPConfig = nil
+			_, err := validator.ValidateCreate(context.Background(), resource)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.httpConfig: Required value: required for protocol http"))
+		})
+
+		It("should reject an http resource with a proxyConfig", func() {
+			resource := newResource(nil)
+			resource.Spec.ProxyConfig = &tunnelv1alpha1.ProxyConfig{ProxyPort: 8080}
+			_, err := validator.ValidateCreate(context.Background(), resource)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.proxyConfig: Forbidden: not allowed for protocol http"))
+		})
+
+		It("should not check bound resources", func() {
+			resource := newResource(nil)
+			resource.Spec.ResourceID = "42"
+			resource.Spec.HTTPConfig = nil
+			_, err := validator.ValidateCreate(context.Background(), resource)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When binding by resource ID", func() {
+		It("should warn about create configuration that is ignored", func() {
+			resource := newResource(nil)
+			resource.Spec.ResourceID = "42"
+			resource.Spec.HTTPConfig = &tunnelv1alpha1.HTTPConfig{Subdomain: "app"}
+
+			warnings, err := validator.ValidateCreate(context.Background(), resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("spec.httpConfig is ignored because spec.resourceId binds to an existing resource"))
+
+			resource.Spec.HTTPConfig = nil
+			warnings, err = validator.ValidateUpdate(context.Background(), resource, resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("When defaulting a resource", func() {
+		newTargetResource := func() *tunnelv1alpha1.PangolinResource {
+			return &tunnelv1alpha1.PangolinResource{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: tunnelv1alpha1.PangolinResourceSpec{
+					TunnelRef: tunnelv1alpha1.LocalObjectReference{Name: "edge"},
+					Protocol:  "tcp",
+					Targets:   []tunnelv1alpha1.TargetConfig{{IP: "10.0.0.1", Port: 80}},
+				},
+			}
+		}
+
+		It("should leave target methods to the controller", func() {
+			resource := newTargetResource()
+			Expect((&PangolinResourceCustomDefaulter{}).Default(context.Background(), resource)).To(Succeed())
+			Expect(resource.Spec.Targets[0].Method).To(BeEmpty())
+		})
+
+		It("should enable the proxy of proxy configs by default", func() {
+			resource := newTargetResource()
+			resource.Spec.ProxyConfig = &tunnelv1alpha1.ProxyConfig{ProxyPort: 5432}
+			Expect((&PangolinResourceCustomDefaulter{}).Default(context.Background(), resource)).To(Succeed())
+			Expect(resource.Spec.ProxyConfig.EnableProxy).NotTo(BeNil())
+			Expect(*resource.Spec.ProxyConfig.EnableProxy).To(BeTrue())
+
+			disabled := false
+			resource.Spec.ProxyConfig.EnableProxy = &disabled
+			Expect((&PangolinResourceCustomDefaulter{}).Default(context.Background(), resource)).To(Succeed())
+			Expect(*resource.Spec.ProxyConfig.EnableProxy).To(BeFalse())
+		})
+	})
+
+	Context("When validating access rules", func() {
+		It("should reject cidr rules whose value is not a CIDR range", func() {
+			resource := newResource(nil)
+			resource.Spec.HTTPConfig = &tunnelv1alpha1.HTTPConfig{AccessRules: []tunnelv1alpha1.AccessRule{
+				{Action: "allow", Match: "cidr", Value: "10.0.0.0/8"},
+				{Action: "deny", Match: "path", Value: "/admin/*"},
+				{Action: "deny", Match: "cidr", Value: "10.0.0.300/24"},
+			}}
+			_, err := validator.ValidateCreate(context.Background(), resource)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`spec.httpConfig.accessRules[2].value: Invalid value: "10.0.0.300/24"`))
+			Expect(err.Error()).NotTo(ContainSubstring("accessRules[0]"))
+
+			resource.Spec.HTTPConfig.AccessRules[2].Value = "2001:db8::/32"
+			_, err = validator.ValidateCreate(context.Background(), resource)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When validating target health checks", func() {
+		It("should reject a timeout that is not less than the interval", func() {
+			resource := newResource(nil)
+			resource.Spec.Targets = []tunnelv1alpha1.TargetConfig{
+				{IP: "10.0.0.1", Port: 80, HealthCheck: &tunnelv1alpha1.TargetHealthCheck{IntervalSeconds: 30, TimeoutSeconds: 5}},
+				{IP: "10.0.0.2", Port: 80, HealthCheck: &tunnelv1alpha1.TargetHealthCheck{IntervalSeconds: 10, TimeoutSeconds: 10}},
+			}
+			_, err := validator.ValidateCreate(context.Background(), resource)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.targets[1].healthCheck.timeoutSeconds: Invalid value: 10: must be less than intervalSeconds (10)"))
+			Expect(err.Error()).NotTo(ContainSubstring("targets[0]"))
+
+			resource.Spec.Targets[1].HealthCheck.TimeoutSeconds = 3
+			_, err = validator.ValidateCreate(context.Background(), resource)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject health checks on targets of tcp and udp resources", func() {
+			resource := newResource(nil)
+			resource.Spec.Targets = []tunnelv1alpha1.TargetConfig{
+				{IP: "10.0.0.1", Port: 5432, HealthCheck: &tunnelv1alpha1.TargetHealthCheck{}},
+			}
+			old := resource.DeepCopy()
+			resource.Spec.Protocol = "tcp"
+			resource.Spec.HTTPConfig = nil
+			resource.Spec.ProxyConfig = &tunnelv1alpha1.ProxyConfig{ProxyPort: 5432}
+			_, err := validator.ValidateUpdate(context.Background(), old, resource)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(
+				"spec.targets[0].healthCheck: Forbidden: health checks probe over HTTP and are not supported on tcp resources"))
+
+			resource.Spec.Targets[0].HealthCheck = nil
+			_, err = validator.ValidateUpdate(context.Background(), old, resource)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// SetupPangolinTunnelWebhookWithManager registers the PangolinTunnel webhooks with the manager.
+func SetupPangolinTunnelWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&tunnelv1alpha1.PangolinTunnel{}).
+		WithValidator(&PangolinTunnelCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-tunnel-pangolin-io-v1alpha1-pangolintunnel,mutating=false,failurePolicy=fail,sideEffects=None,groups=tunnel.pangolin.io,resources=pangolintunnels,verbs=create;update,versions=v1alpha1,name=vpangolintunnel-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PangolinTunnelCustomValidator validates PangolinTunnel objects on create and update.
+//
+// spec.config is checked against the known Newt options: unknown keys produce
+// warnings, malformed values for known keys are rejected.
+type PangolinTunnelCustomValidator struct{}
+
+var _ webhook.CustomValidator = &PangolinTunnelCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *PangolinTunnelCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tunnel, ok := obj.(*tunnelv1alpha1.PangolinTunnel)
+	if !ok {
+		return nil, fmt.Errorf("expected a PangolinTunnel object but got %T", obj)
+	}
+	return validatePangolinTunnel(tunnel)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *PangolinTunnelCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	tunnel, ok := newObj.(*tunnelv1alpha1.PangolinTunnel)
+	if !ok {
+		return nil, fmt.Errorf("expected a PangolinTunnel object for the newObj but got %T", newObj)
+	}
+	return validatePangolinTunnel(tunnel)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletion is always allowed.
+func (v *PangolinTunnelCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validatePangolinTunnel validates the tunnel spec
+func validatePangolinTunnel(tunnel *tunnelv1alpha1.PangolinTunnel) (admission.Warnings, error) {
+	allowUnknown, _ := strconv.ParseBool(tunnel.Annotations[AllowUnknownConfigAnnotation])
+
+	warnings, errs := validateConfig(field.NewPath("spec", "config"), tunnel.Spec.Config, tunnelConfigSchema, allowUnknown)
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(tunnelv1alpha1.GroupVersion.WithKind("PangolinTunnel").GroupKind(),
+			tunnel.Name, errs)
+	}
+	return warnings, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2025 github.com/bovf
+
+This program is free software: it can be redistributed and/or modified under the terms of the GNU Affero General Public License as published by the Free Software Foundation, either version 3 of the License, or (at the option) any later version.
+
+This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Affero General Public License for more details.
+
+A copy of the GNU Affero General Public License should be included with this program. If not, see https://www.gnu.org/licenses/.
+
+Third‑party code bundled in this repository may be licensed under different terms (for example, Apache‑2.0 for Kubernetes libraries). Such components retain their original licenses; see the corresponding LICENSE/NOTICE files in their source directories.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+var _ = Describe("PangolinTunnel Webhook", func() {
+	var (
+		validator PangolinTunnelCustomValidator
+		tunnel    *tunnelv1alpha1.PangolinTunnel
+	)
+
+	BeforeEach(func() {
+		validator = PangolinTunnelCustomValidator{}
+		tunnel = &tunnelv1alpha1.PangolinTunnel{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-tunnel", Namespace: "default"},
+			Spec: tunnelv1alpha1.PangolinTunnelSpec{
+				OrganizationRef: tunnelv1alpha1.LocalObjectReference{Name: "org"},
+			},
+		}
+	})
+
+	Context("When validating spec.config", func() {
+		It("should admit known keys with valid values", func() {
+			tunnel.Spec.Config = map[string]string{
+				"mtu":            "1280",
+				"dns":            "1.1.1.1",
+				"log-level":      "DEBUG",
+				"ping-interval":  "3s",
+				"accept-clients": "true",
+			}
+			warnings, err := validator.ValidateCreate(context.Background(), tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should warn on unknown keys", func() {
+			tunnel.Spec.Config = map[string]string{"mtuu": "1280"}
+			warnings, err := validator.ValidateCreate(context.Background(), tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring(`"mtuu"`))
+		})
+
+		It("should not warn on unknown keys when the escape hatch annotation is set", func() {
+			tunnel.Annotations = map[string]string{AllowUnknownConfigAnnotation: "true"}
+			tunnel.Spec.Config = map[string]string{"experimental-flag": "on"}
+			warnings, err := validator.ValidateUpdate(context.Background(), tunnel, tunnel)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should reject malformed values", func() {
+			tunnel.Spec.Config = map[string]string{
+				"mtu":       "large",
+				"log-level": "verbose",
+			}
+			_, err := validator.ValidateCreate(context.Background(), tunnel)
+			Expect(err).To(HaveOccurred())
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.config[mtu]"))
+			Expect(err.Error()).To(ContainSubstring("spec.config[log-level]"))
+		})
+
+		It("should reject out of range integers", func() {
+			tunnel.Spec.Config = map[string]string{"mtu": "100"}
+			_, err := validator.ValidateCreate(context.Background(), tunnel)
+			Expect(err).To(MatchError(ContainSubstring("must be at least 576")))
+		})
+	})
+})
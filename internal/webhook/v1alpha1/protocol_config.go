@@ -0,0 +1,32 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// validateProtocolConfig checks that the protocol specific configuration
+// matches the protocol, as the resource controller requires: http needs
+// httpConfig and no proxyConfig, tcp and udp need proxyConfig and no httpConfig.
+func validateProtocolConfig(specPath *field.Path, protocol string,
+	httpConfig *tunnelv1alpha1.HTTPConfig, proxyConfig *tunnelv1alpha1.ProxyConfig) field.ErrorList {
+	var errs field.ErrorList
+	switch protocol {
+	case "http":
+		if httpConfig == nil {
+			errs = append(errs, field.Required(specPath.Child("httpConfig"), "required for protocol http"))
+		}
+		if proxyConfig != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("proxyConfig"), "not allowed for protocol http"))
+		}
+	case "tcp", "udp":
+		if proxyConfig == nil {
+			errs = append(errs, field.Required(specPath.Child("proxyConfig"), "required for protocol "+protocol))
+		}
+		if httpConfig != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("httpConfig"), "not allowed for protocol "+protocol))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,71 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AllowedProtocolsAnnotation on a Namespace restricts the protocols that
+// PangolinResources and PangolinBindings in it may use, as a comma separated
+// list (e.g. "http,tcp"). Namespaces without the annotation allow all protocols.
+const AllowedProtocolsAnnotation = "tunnel.pangolin.io/allowed-protocols"
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+
+// allowedProtocols returns the protocols permitted in a namespace, nil if unrestricted
+func allowedProtocols(ctx context.Context, reader client.Reader, namespace string) ([]string, error) {
+	ns := &corev1.Namespace{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+
+	value, ok := ns.Annotations[AllowedProtocolsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	allowed := []string{}
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			allowed = append(allowed, p)
+		}
+	}
+	return allowed, nil
+}
+
+// validateProtocolPolicy checks protocol against the namespace's allowed protocols.
+//
+// Returns:
+//   - *field.Error: if the protocol is forbidden in the namespace
+//   - error: if the namespace could not be read
+func validateProtocolPolicy(ctx context.Context, reader client.Reader, namespace, protocol string,
+	path *field.Path) (*field.Error, error) {
+	if protocol == "" {
+		return nil, nil
+	}
+
+	allowed, err := allowedProtocols(ctx, reader, namespace)
+	if err != nil || allowed == nil {
+		return nil, err
+	}
+	if slices.Contains(allowed, strings.ToLower(protocol)) {
+		return nil, nil
+	}
+
+	permitted := "none"
+	if len(allowed) > 0 {
+		permitted = strings.Join(allowed, ", ")
+	}
+	return field.Forbidden(path, fmt.Sprintf("protocol %q is not allowed in namespace %s (allowed: %s, see the %s namespace annotation)",
+		protocol, namespace, permitted, AllowedProtocolsAnnotation)), nil
+}
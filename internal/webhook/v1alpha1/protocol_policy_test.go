@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2025 github.com/bovf
+
+This program is free software: it can be redistributed and/or modified under the terms of the GNU Affero General Public License as published by the Free Software Foundation, either version 3 of the License, or (at the option) any later version.
+
+This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Affero General Public License for more details.
+
+A copy of the GNU Affero General Public License should be included with this program. If not, see https://www.gnu.org/licenses/.
+
+Third‑party code bundled in this repository may be licensed under different terms (for example, Apache‑2.0 for Kubernetes libraries). Such components retain their original licenses; see the corresponding LICENSE/NOTICE files in their source directories.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tunnelv1alpha1 "github.com/bovf/pangolin-operator/api/v1alpha1"
+)
+
+// namespaceReader serves a fixed set of namespaces
+type namespaceReader struct {
+	client.Reader
+	namespaces map[string]*corev1.Namespace
+}
+
+func (r namespaceReader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	ns, ok := r.namespaces[key.Name]
+	if !ok {
+		return apierrors.NewNotFound(corev1.Resource("namespaces"), key.Name)
+	}
+	ns.DeepCopyInto(obj.(*corev1.Namespace))
+	return nil
+}
+
+var _ = Describe("Protocol policy", func() {
+	reader := namespaceReader{namespaces: map[string]*corev1.Namespace{
+		"web-only": {ObjectMeta: metav1.ObjectMeta{
+			Name:        "web-only",
+			Annotations: map[string]string{AllowedProtocolsAnnotation: "http, tcp"},
+		}},
+		"open": {ObjectMeta: metav1.ObjectMeta{Name: "open"}},
+	}}
+
+	newResource := func(namespace, protocol string) *tunnelv1alpha1.PangolinResource {
+		resource := &tunnelv1alpha1.PangolinResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: namespace},
+			Spec:       tunnelv1alpha1.PangolinResourceSpec{Protocol: protocol},
+		}
+		if protocol == "http" {
+			resource.Spec.HTTPConfig = &tunnelv1alpha1.HTTPConfig{Subdomain: "app"}
+		} else {
+			resource.Spec.ProxyConfig = &tunnelv1alpha1.ProxyConfig{ProxyPort: 8080}
+		}
+		return resource
+	}
+
+	Context("When validating a PangolinResource", func() {
+		validator := &PangolinResourceCustomValidator{Reader: reader}
+
+		It("should admit an allowed protocol", func() {
+			_, err := validator.ValidateCreate(context.Background(), newResource("web-only", "http"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject a forbidden protocol with a clear message", func() {
+			_, err := validator.ValidateCreate(context.Background(), newResource("web-only", "udp"))
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`spec.protocol: Forbidden: protocol "udp" is not allowed in namespace web-only (allowed: http, tcp`))
+		})
+
+		It("should admit any protocol without a policy", func() {
+			_, err := validator.ValidateCreate(context.Background(), newResource("open", "udp"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not block updates that keep the protocol", func() {
+			resource := newResource("web-only", "udp")
+			_, err := validator.ValidateUpdate(context.Background(), resource, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = validator.ValidateUpdate(context.Background(), newResource("web-only", "http"), resource)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When validating a PangolinBinding", func() {
+		validator := &PangolinBindingCustomValidator{Reader: reader}
+
+		It("should reject a forbidden protocol", func() {
+			binding := &tunnelv1alpha1.PangolinBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "web-only"},
+				Spec: tunnelv1alpha1.PangolinBindingSpec{
+					Protocol:    "udp",
+					ProxyConfig: &tunnelv1alpha1.ProxyConfig{ProxyPort: 5432},
+				},
+			}
+			_, err := validator.ValidateCreate(context.Background(), binding)
+			Expect(err).To(MatchError(ContainSubstring(`protocol "udp" is not allowed`)))
+
+			binding.Spec.Protocol = "tcp"
+			_, err = validator.ValidateCreate(context.Background(), binding)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,129 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// AllowUnknownConfigAnnotation opts a tunnel out of unknown-key warnings, for
+// experimenting with Newt options the operator does not know about yet.
+const AllowUnknownConfigAnnotation = "tunnel.pangolin.io/allow-unknown-config"
+
+// configValueType is the expected type of a config value
+type configValueType string
+
+const (
+	configString   configValueType = "string"
+	configInt      configValueType = "integer"
+	configBool     configValueType = "boolean"
+	configDuration configValueType = "duration"
+	configIP       configValueType = "IP address"
+	configEnum     configValueType = "enum"
+)
+
+// configKeySchema describes a single known config key
+type configKeySchema struct {
+	Type configValueType
+	// Min/Max bound integer values when set
+	Min, Max *int
+	// Values lists the allowed values for enum keys
+	Values []string
+}
+
+func intPtr(i int) *int { return &i }
+
+// tunnelConfigSchema lists the known PangolinTunnel spec.config keys, mirroring
+// the Newt client options.
+var tunnelConfigSchema = map[string]configKeySchema{
+	"mtu":                               {Type: configInt, Min: intPtr(576), Max: intPtr(9000)},
+	"dns":                               {Type: configIP},
+	"log-level":                         {Type: configEnum, Values: []string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL"}},
+	"ping-interval":                     {Type: configDuration},
+	"ping-timeout":                      {Type: configDuration},
+	"accept-clients":                    {Type: configBool},
+	"docker-socket":                     {Type: configString},
+	"docker-enforce-network-validation": {Type: configBool},
+	"health-file":                       {Type: configString},
+	"updown":                            {Type: configString},
+	"tls-client-cert":                   {Type: configString},
+}
+
+// validateConfigValue checks a single value against its schema
+func validateConfigValue(s configKeySchema, value string) error {
+	switch s.Type {
+	case configInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		if s.Min != nil && n < *s.Min {
+			return fmt.Errorf("must be at least %d", *s.Min)
+		}
+		if s.Max != nil && n > *s.Max {
+			return fmt.Errorf("must be at most %d", *s.Max)
+		}
+	case configBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+	case configDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("must be a duration (e.g. 3s)")
+		}
+		if d <= 0 {
+			return fmt.Errorf("must be positive")
+		}
+	case configIP:
+		if net.ParseIP(value) == nil {
+			return fmt.Errorf("must be an IP address")
+		}
+	case configEnum:
+		if !slices.Contains(s.Values, value) {
+			return fmt.Errorf("must be one of %s", strings.Join(s.Values, ", "))
+		}
+	}
+	return nil
+}
+
+// validateConfig validates a config map against schema.
+//
+// Returns:
+//   - warnings: one per unknown key, unless allowUnknown is set
+//   - errs: one per known key with a malformed value
+func validateConfig(path *field.Path, config map[string]string, schema map[string]configKeySchema,
+	allowUnknown bool) ([]string, field.ErrorList) {
+	var warnings []string
+	var errs field.ErrorList
+
+	// Sort keys so warnings and errors are reported in a stable order
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := config[key]
+		s, known := schema[key]
+		if !known {
+			if !allowUnknown {
+				warnings = append(warnings, fmt.Sprintf("%s: unknown key %q is ignored (set annotation %s=true to silence)",
+					path.String(), key, AllowUnknownConfigAnnotation))
+			}
+			continue
+		}
+		if err := validateConfigValue(s, value); err != nil {
+			errs = append(errs, field.Invalid(path.Key(key), value, fmt.Sprintf("%s: %v", s.Type, err)))
+		}
+	}
+
+	return warnings, errs
+}
@@ -3,12 +3,15 @@ package pangolin
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -17,8 +20,126 @@ import (
 // It handles authentication, request construction, and response parsing for all API operations.
 type Client struct {
 	endpoint string       // Base API endpoint URL (e.g., "https://api.pangolin.dobryops.com")
+	basePath string       // Path the API is served under, without slashes (e.g., "v1")
 	apiKey   string       // API key for authentication
 	client   *http.Client // HTTP client with configured timeout
+	retry    RetryConfig  // Retries of transient failures
+	headers  http.Header  // Additional headers sent with every request
+
+	rateLimit *rateLimitState // Latest rate limit reported by the API
+
+	fallbackKey string       // API key tried when apiKey is rejected, see WithFallbackAPIKey
+	keys        *apiKeyState // Which of the keys is in use
+}
+
+// apiKeyState tracks which API key a client uses. It is shared by pointer, as
+// requests are made on copies of the client.
+type apiKeyState struct {
+	useFallback atomic.Bool
+}
+
+// DefaultBasePath is the path the Pangolin Integration API is served under,
+// used by clients created without WithBasePath.
+const DefaultBasePath = "/v1"
+
+// RetryConfig controls how requests are retried after transient failures.
+//
+// Responses with status 429, 502, 503 or 504 are retried for every method,
+// network errors only for GET requests, which are safe to repeat.
+type RetryConfig struct {
+	// MaxAttempts is the number of attempts per request, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry. It doubles for every
+	// further retry, and up to half of it is added as jitter.
+	BaseDelay time.Duration
+}
+
+// DefaultRetryConfig is the retry configuration of clients created without WithRetryConfig.
+var DefaultRetryConfig = RetryConfig{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond}
+
+// maxRetryAfter is the longest Retry-After waited out within a request. Longer
+// waits are left to the caller, see RetryAfter.
+const maxRetryAfter = 30 * time.Second
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithRetryConfig sets how requests are retried after transient failures.
+func WithRetryConfig(cfg RetryConfig) Option {
+	return func(c *Client) {
+		c.retry = cfg
+	}
+}
+
+// WithBasePath sets the path the API is served under, relative to the endpoint,
+// e.g. "/v2" for a newer API version or "/api/v1" for a deployment mounting it
+// elsewhere. "/" serves the API from the endpoint itself.
+func WithBasePath(path string) Option {
+	return func(c *Client) {
+		c.basePath = strings.Trim(path, "/")
+	}
+}
+
+// WithHeaders sets additional headers sent with every request, e.g. the
+// credentials of an authenticating proxy in front of the API such as
+// CF-Access-Client-Id and CF-Access-Client-Secret for Cloudflare Access. They
+// cannot replace the Content-Type, User-Agent and Authorization headers.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		c.headers = http.Header{}
+		for name, value := range headers {
+			c.headers.Set(name, value)
+		}
+	}
+}
+
+// WithFallbackAPIKey sets a second API key for zero-downtime key rotation. When
+// the API rejects the key in use with 401, the request is repeated with the
+// other key, which is then used for later requests as long as it is accepted.
+func WithFallbackAPIKey(key string) Option {
+	return func(c *Client) {
+		c.fallbackKey = key
+	}
+}
+
+// UsingFallbackAPIKey reports whether the client switched to the fallback key
+// because the API key was rejected.
+func (c *Client) UsingFallbackAPIKey() bool {
+	return c.fallbackKey != "" && c.keys.useFallback.Load()
+}
+
+// WithTimeout sets the timeout of each HTTP request, including retries of it.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.client
+		hc.Timeout = d
+		c.client = &hc
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used for requests, e.g. to use a
+// custom transport or proxy. Options given after it apply to a copy of hc.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.client = hc
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the API, e.g. to
+// trust a private CA. The default transport is used otherwise unchanged.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if t, ok := c.client.Transport.(*http.Transport); ok {
+			transport = t.Clone()
+		}
+		transport.TLSClientConfig = cfg
+
+		hc := *c.client
+		hc.Transport = transport
+		c.client = &hc
+	}
 }
 
 // NewClient creates a new Pangolin API client with the specified endpoint and API key.
@@ -26,40 +147,67 @@ type Client struct {
 // Parameters:
 //   - endpoint: Base URL of the Pangolin API (e.g., "https://api.pangolin.dobryops.com")
 //   - apiKey: API key for authentication (obtained from Pangolin dashboard)
+//   - opts: Options such as WithRetryConfig, WithTimeout, WithTLSConfig, WithBasePath, WithHeaders or
+//     WithRateLimitObserver
 //
-// The client is configured with a 30-second timeout for all requests.
-func NewClient(endpoint, apiKey string) *Client {
-	return &Client{
+// Unless configured otherwise, the client talks to the API under DefaultBasePath,
+// uses a 30-second timeout for all requests and retries transient failures with
+// DefaultRetryConfig.
+func NewClient(endpoint, apiKey string, opts ...Option) *Client {
+	c := &Client{
 		endpoint: endpoint,
+		basePath: strings.Trim(DefaultBasePath, "/"),
 		apiKey:   apiKey,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry:     DefaultRetryConfig,
+		rateLimit: &rateLimitState{},
+		keys:      &apiKeyState{},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
+
+// Endpoint returns the base URL of the Pangolin API the client talks to.
+func (c *Client) Endpoint() string {
+	return c.endpoint
 }
 
 // makeRequest constructs and executes an HTTP request to the Pangolin API.
 //
-// All requests are made to /v1/<path> with proper authentication headers.
+// All requests are made to <basePath>/<path> with proper authentication headers.
 // Request bodies are automatically JSON-encoded if provided.
 //
 // Parameters:
 //   - ctx: Context for request cancellation and timeout
 //   - method: HTTP method (GET, POST, PUT, DELETE)
-//   - path: API path relative to /v1/ (e.g., "orgs", "org/my-org/sites")
+//   - path: API path relative to the base path (e.g., "orgs", "org/my-org/sites")
 //   - body: Request body to be JSON-encoded (nil for no body)
 //
 // Returns:
 //   - HTTP response (caller must close response body)
 //   - Error if request construction or execution fails
 //
+// Transient failures are retried according to the client's RetryConfig, the
+// response of the last attempt is returned. Cancelling ctx stops the retries.
+// A request rejected with 401 is repeated with the other key when a fallback
+// key is set, see WithFallbackAPIKey.
+//
 // Request Headers:
 //   - Content-Type: application/json
 //   - Authorization: Bearer <apiKey>
 //   - User-Agent: pangolin-operator/1.0
+//   - Any headers set with WithHeaders
 func (c Client) makeRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	cleanPath := strings.TrimLeft(path, "/")
-	url := fmt.Sprintf("%s/v1/%s", strings.TrimRight(c.endpoint, "/"), cleanPath)
+	base := strings.TrimRight(c.endpoint, "/")
+	if c.basePath != "" {
+		base = fmt.Sprintf("%s/%s", base, c.basePath)
+	}
+	url := fmt.Sprintf("%s/%s", base, cleanPath)
 
 	logger := log.FromContext(ctx)
 	logger.Info("connection infos", "url", url, " token", c.apiKey)
@@ -72,15 +220,148 @@ func (c Client) makeRequest(ctx context.Context, method, path string, body inter
 		reqBody = b
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(reqBody))
+	if c.fallbackKey == "" {
+		return c.send(ctx, method, url, reqBody, c.apiKey)
+	}
+
+	// With a fallback key, a key rejected with 401 (e.g. revoked during a
+	// rotation) is retried once with the other key, which stays in use if
+	// accepted
+	useFallback := c.keys.useFallback.Load()
+	resp, err := c.send(ctx, method, url, reqBody, c.key(useFallback))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	logger.Info("Pangolin API rejected the API key, trying the other key", "fallback", !useFallback)
+
+	resp, err = c.send(ctx, method, url, reqBody, c.key(!useFallback))
+	if err == nil && resp.StatusCode != http.StatusUnauthorized {
+		c.keys.useFallback.Store(!useFallback)
+	}
+	return resp, err
+}
+
+// key returns the fallback key if fallback is set, the API key otherwise
+func (c Client) key(fallback bool) string {
+	if fallback {
+		return c.fallbackKey
+	}
+	return c.apiKey
+}
+
+// send executes a request with apiKey, retrying transient failures according
+// to the client's RetryConfig. The response of the last attempt is returned.
+func (c Client) send(ctx context.Context, method, url string, reqBody []byte, apiKey string) (*http.Response, error) {
+	logger := log.FromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(reqBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		for name, values := range c.headers {
+			req.Header[name] = values
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "pangolin-operator/1.0")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+		resp, err := c.client.Do(req)
+		if resp != nil {
+			c.rateLimit.observe(resp.Header)
+		}
+		if attempt >= c.retry.MaxAttempts || !retryable(ctx, method, resp, err) {
+			return resp, err
+		}
+
+		delay, ok := c.retryDelay(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+		logger.Info("Retrying Pangolin API request", "method", method, "url", url, "attempt", attempt, "delay", delay)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// retryable reports whether a request that ended with resp or err is worth
+// another attempt, see RetryConfig.
+func retryable(ctx context.Context, method string, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return method == http.MethodGet
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns the wait before the retry following attempt: the
+// exponentially growing base delay with jitter, or the Retry-After of the
+// response if that is longer. ok is false if the Retry-After exceeds maxRetryAfter.
+func (c Client) retryDelay(attempt int, resp *http.Response) (delay time.Duration, ok bool) {
+	delay = c.retry.BaseDelay << (attempt - 1)
+	if delay > 0 {
+		delay += time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	}
+	if resp != nil {
+		retryAfter := parseRetryAfter(resp.Header)
+		if retryAfter > maxRetryAfter {
+			return 0, false
+		}
+		delay = max(delay, retryAfter)
+	}
+	return delay, true
+}
+
+// GetServerInfo retrieves the Pangolin server version and capabilities.
+//
+// Returns:
+//   - ServerInfo, or nil if the server does not expose version information
+//   - Error if request fails
+//
+// Used for:
+//   - Enabling features only supported by newer Pangolin versions
+func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
+	resp, err := c.makeRequest(ctx, "GET", "/version", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "pangolin-operator/1.0")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	defer resp.Body.Close()
 
-	return c.client.Do(req)
+	// Older Pangolin versions do not expose version information
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get server info", resp)
+	}
+
+	var result struct {
+		Success bool       `json:"success"`
+		Data    ServerInfo `json:"data"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, &APIError{Op: "get server info", StatusCode: resp.StatusCode, Message: "API request was not successful"}
+	}
+	return &result.Data, nil
 }
 
 // ListOrganizations retrieves all organizations accessible with the current API key.
@@ -101,8 +382,7 @@ func (c *Client) ListOrganizations(ctx context.Context) ([]Organization, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, fmt.Errorf("list orgs failed: status %d: %s", resp.StatusCode, string(b))
+		return nil, newAPIError("list orgs", resp)
 	}
 
 	var result struct {
@@ -111,58 +391,73 @@ func (c *Client) ListOrganizations(ctx context.Context) ([]Organization, error)
 			Orgs []Organization `json:"orgs"`
 		} `json:"data"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 	if !result.Success {
-		return nil, fmt.Errorf("API request was not successful")
+		return nil, &APIError{Op: "list orgs", StatusCode: resp.StatusCode, Message: "API request was not successful"}
 	}
 	return result.Data.Orgs, nil
 }
 
-// ListDomains retrieves all domains configured for an organization.
-//
-// Domains are used for HTTP resource exposure, allowing resources to be accessed
-// via subdomains (e.g., app.mydomain.com).
+// GetOrganization retrieves a single organization by its ID.
 //
 // Parameters:
 //   - ctx: Context for request cancellation
-//   - orgID: Organization ID to query domains for
+//   - orgID: Organization ID to fetch
 //
 // Returns:
-//   - Slice of Domain objects with ID, base domain, verification status, and configuration
-//   - Error if request fails or organization not found
-//
-// Domain Types:
-//   - Verified: Domains that have been verified and are ready for use
-//   - Unverified: Domains pending DNS verification
-//   - Failed: Domains that failed verification
-func (c *Client) ListDomains(ctx context.Context, orgID string) ([]Domain, error) {
-	path := fmt.Sprintf("/org/%s/domains?limit=1000&offset=0", orgID)
-	resp, err := c.makeRequest(ctx, "GET", path, nil)
+//   - Organization with ID, name, and subnet information
+//   - Error if the organization is not found or the request fails; servers
+//     without the endpoint answer with an error matched by IsUnsupported
+func (c *Client) GetOrganization(ctx context.Context, orgID string) (*Organization, error) {
+	resp, err := c.makeRequest(ctx, "GET", fmt.Sprintf("/org/%s", orgID), nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, fmt.Errorf("list domains failed: status %d: %s", resp.StatusCode, string(b))
+		return nil, newAPIError("get org", resp)
 	}
 
 	var result struct {
 		Success bool `json:"success"`
 		Data    struct {
-			Domains []Domain `json:"domains"`
+			Org Organization `json:"org"`
 		} `json:"data"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 	if !result.Success {
-		return nil, fmt.Errorf("API request was not successful")
+		return nil, &APIError{Op: "get org", StatusCode: resp.StatusCode, Message: "API request was not successful"}
+	}
+	if result.Data.Org.OrgID == "" {
+		result.Data.Org.OrgID = orgID
 	}
-	return result.Data.Domains, nil
+	return &result.Data.Org, nil
+}
+
+// ListDomains retrieves all domains configured for an organization.
+//
+// Domains are used for HTTP resource exposure, allowing resources to be accessed
+// via subdomains (e.g., app.mydomain.com).
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - orgID: Organization ID to query domains for
+//
+// Returns:
+//   - Slice of Domain objects with ID, base domain, verification status, and configuration
+//   - Error if request fails or organization not found
+//
+// Domain Types:
+//   - Verified: Domains that have been verified and are ready for use
+//   - Unverified: Domains pending DNS verification
+//   - Failed: Domains that failed verification
+func (c *Client) ListDomains(ctx context.Context, orgID string) ([]Domain, error) {
+	return listAll[Domain](ctx, c, "list domains", fmt.Sprintf("/org/%s/domains", orgID), "domains")
 }
 
 // ListSites retrieves all sites (tunnel endpoints) for an organization.
@@ -183,31 +478,7 @@ func (c *Client) ListDomains(ctx context.Context, orgID string) ([]Domain, error
 //   - wireguard: WireGuard VPN tunnel
 //   - other: Custom tunnel implementations
 func (c *Client) ListSites(ctx context.Context, orgID string) ([]Site, error) {
-	path := fmt.Sprintf("/org/%s/sites?limit=1000&offset=0", orgID)
-	resp, err := c.makeRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, fmt.Errorf("list sites failed: status %d: %s", resp.StatusCode, string(b))
-	}
-
-	var result struct {
-		Success bool `json:"success"`
-		Data    struct {
-			Sites []Site `json:"sites"`
-		} `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-	if !result.Success {
-		return nil, fmt.Errorf("API request was not successful")
-	}
-	return result.Data.Sites, nil
+	return listAll[Site](ctx, c, "list sites", fmt.Sprintf("/org/%s/sites", orgID), "sites")
 }
 
 // GetSiteByID retrieves a specific site by its numeric site ID.
@@ -229,19 +500,18 @@ func (c *Client) GetSiteByID(ctx context.Context, siteID int) (*Site, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, fmt.Errorf("get site by id failed: status %d: %s", resp.StatusCode, string(b))
+		return nil, newAPIError("get site by id", resp)
 	}
 
 	var result struct {
 		Success bool `json:"success"`
 		Data    Site `json:"data"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 	if !result.Success {
-		return nil, fmt.Errorf("API request was not successful")
+		return nil, &APIError{Op: "get site by id", StatusCode: resp.StatusCode, Message: "API request was not successful"}
 	}
 	return &result.Data, nil
 }
@@ -268,19 +538,18 @@ func (c *Client) GetSiteByNiceID(ctx context.Context, orgID, niceID string) (*Si
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, fmt.Errorf("get site by niceId failed: status %d: %s", resp.StatusCode, string(b))
+		return nil, newAPIError("get site by niceId", resp)
 	}
 
 	var result struct {
 		Success bool `json:"success"`
 		Data    Site `json:"data"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 	if !result.Success {
-		return nil, fmt.Errorf("API request was not successful")
+		return nil, &APIError{Op: "get site by niceId", StatusCode: resp.StatusCode, Message: "API request was not successful"}
 	}
 	return &result.Data, nil
 }
@@ -315,19 +584,76 @@ func (c *Client) CreateSite(ctx context.Context, orgID, name, siteType string) (
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, fmt.Errorf("create site failed: status %d: %s", resp.StatusCode, string(b))
+		return nil, newAPIError("create site", resp)
 	}
 
 	var result struct {
 		Success bool `json:"success"`
 		Data    Site `json:"data"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 	if !result.Success {
-		return nil, fmt.Errorf("API request was not successful")
+		return nil, &APIError{Op: "create site", StatusCode: resp.StatusCode, Message: "API request was not successful"}
+	}
+	return &result.Data, nil
+}
+
+// DeleteSite deletes a site.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - siteID: Numeric site identifier
+//
+// Returns error if deletion fails. A site that no longer exists is not an error.
+func (c *Client) DeleteSite(ctx context.Context, siteID int) error {
+	resp, err := c.makeRequest(ctx, "DELETE", fmt.Sprintf("/site/%d", siteID), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return newAPIError("delete site", resp)
+	}
+
+	return nil
+}
+
+// RegenerateNewtCredentials issues a new secret for the Newt client of a site.
+//
+// The Pangolin API only returns the Newt secret when a site is created, so this is
+// the only way to recover credentials that were lost (e.g. a deleted Secret).
+// The previous secret is invalidated and connected Newt clients must be restarted.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - siteID: Numeric site identifier
+//
+// Returns:
+//   - NewtCredentials with the Newt ID and the newly generated secret
+//   - Error if the site is not found or the request fails
+func (c *Client) RegenerateNewtCredentials(ctx context.Context, siteID int) (*NewtCredentials, error) {
+	resp, err := c.makeRequest(ctx, "POST", fmt.Sprintf("/site/%d/regenerate-secret", siteID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("regenerate newt credentials", resp)
+	}
+
+	var result struct {
+		Success bool            `json:"success"`
+		Data    NewtCredentials `json:"data"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, &APIError{Op: "regenerate newt credentials", StatusCode: resp.StatusCode, Message: "API request was not successful"}
 	}
 	return &result.Data, nil
 }
@@ -339,34 +665,53 @@ func (c *Client) CreateSite(ctx context.Context, orgID, name, siteType string) (
 //   - orgID: Organization ID to query resources for
 //
 // Returns:
-//   - Slice of Resource objects
+//   - Slice of Resource objects with their IDs normalized
 //   - Error if request fails
 func (c *Client) ListResources(ctx context.Context, orgID string) ([]Resource, error) {
-	path := fmt.Sprintf("/org/%s/resources?limit=1000&offset=0", orgID)
-	resp, err := c.makeRequest(ctx, "GET", path, nil)
+	resources, err := listAll[Resource](ctx, c, "list resources", fmt.Sprintf("/org/%s/resources", orgID), "resources")
+	if err != nil {
+		return nil, err
+	}
+	for i := range resources {
+		resources[i].ID = resources[i].EffectiveID()
+	}
+	return resources, nil
+}
+
+// GetResource retrieves a single resource by its ID.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID to fetch
+//
+// Returns:
+//   - Resource with its ID normalized
+//   - Error if the resource is not found or the request fails
+func (c *Client) GetResource(ctx context.Context, resourceID string) (*Resource, error) {
+	resp, err := c.makeRequest(ctx, "GET", fmt.Sprintf("/resource/%s", resourceID), nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, fmt.Errorf("list resources failed: status %d: %s", resp.StatusCode, string(b))
+		return nil, newAPIError("get resource", resp)
 	}
 
 	var result struct {
-		Success bool `json:"success"`
-		Data    struct {
-			Resources []Resource `json:"resources"`
-		} `json:"data"`
+		Success bool     `json:"success"`
+		Data    Resource `json:"data"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 	if !result.Success {
-		return nil, fmt.Errorf("API request was not successful")
+		return nil, &APIError{Op: "get resource", StatusCode: resp.StatusCode, Message: "API request was not successful"}
 	}
-	return result.Data.Resources, nil
+
+	// Normalize ID field (API may return either 'id' or 'resourceId')
+	result.Data.ID = result.Data.EffectiveID()
+	return &result.Data, nil
 }
 
 // FindResourceBySubdomain finds a resource by its subdomain and domain ID.
@@ -440,6 +785,7 @@ func (c *Client) FindResourceByName(ctx context.Context, orgID, name string) (*R
 //
 // HTTP Resources (spec.HTTP = true):
 //   - Requires: subdomain, domainId
+//   - Optional: proxyPort for non-standard HTTPS ports
 //   - Exposed via HTTPS at subdomain.domain
 //   - Automatically provisions SSL certificates
 //
@@ -467,6 +813,10 @@ func (c *Client) CreateResource(ctx context.Context, orgID, siteID string, spec
 		if spec.DomainID != "" {
 			data["domainId"] = spec.DomainID
 		}
+		// Non-standard HTTPS port
+		if spec.ProxyPort > 0 {
+			data["proxyPort"] = spec.ProxyPort
+		}
 	} else {
 		// Add TCP/UDP proxy fields
 		if spec.ProxyPort > 0 {
@@ -476,6 +826,9 @@ func (c *Client) CreateResource(ctx context.Context, orgID, siteID string, spec
 			data["enableProxy"] = spec.EnableProxy
 		}
 	}
+	if len(spec.Tags) > 0 {
+		data["tags"] = spec.Tags
+	}
 
 	// Always use org-level endpoint for resource creation
 	path := fmt.Sprintf("/org/%s/resource", orgID)
@@ -492,10 +845,8 @@ func (c *Client) CreateResource(ctx context.Context, orgID, siteID string, spec
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Validate content type
-	ct := resp.Header.Get("Content-Type")
-	if !strings.HasPrefix(ct, "application/json") {
-		return nil, fmt.Errorf("unexpected content-type %q status %d: %s", ct, resp.StatusCode, string(bodyBytes))
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, decodeAPIError("create resource", resp, bodyBytes)
 	}
 
 	// Parse response
@@ -506,26 +857,17 @@ func (c *Client) CreateResource(ctx context.Context, orgID, siteID string, spec
 		Message string      `json:"message,omitempty"`
 		Status  int         `json:"status,omitempty"`
 	}
-	if err := json.Unmarshal(bodyBytes, &result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w, body: %s", err, string(bodyBytes))
+	if err := decodeBody(resp, bodyBytes, &result); err != nil {
+		return nil, err
 	}
 
 	// Enhanced error handling with API message
 	if !result.Success {
-		errMsg := "API request was not successful"
-		if result.Message != "" {
-			errMsg = fmt.Sprintf("%s: %s", errMsg, result.Message)
-		}
-		if result.Status > 0 {
-			errMsg = fmt.Sprintf("%s (status: %d)", errMsg, result.Status)
-		}
-		return nil, fmt.Errorf(errMsg)
+		return nil, decodeAPIError("create resource", resp, bodyBytes)
 	}
 
 	// Normalize ID field (API may return either 'id' or 'resourceId')
-	if result.Data.ID == "" && result.Data.ResourceID != 0 {
-		result.Data.ID = strconv.Itoa(result.Data.ResourceID)
-	}
+	result.Data.ID = result.Data.EffectiveID()
 
 	return &result.Data, nil
 }
@@ -550,6 +892,8 @@ func (c *Client) CreateResource(ctx context.Context, orgID, siteID string, spec
 //   - Port: Backend port number
 //   - Method: Protocol method (http, https, tcp, udp)
 //   - Enabled: Whether target should receive traffic
+//   - Priority: Order in which targets are matched, higher first (optional)
+//   - Weight: Share of traffic relative to the resource's other targets (optional)
 //   - SiteID: Associates target with specific site for routing
 //
 // Target Matching:
@@ -564,6 +908,20 @@ func (c *Client) CreateTarget(ctx context.Context, resourceID, siteID string, sp
 		"enabled": spec.Enabled,
 	}
 
+	if spec.Priority != 0 {
+		data["priority"] = spec.Priority
+	}
+
+	if spec.Weight != nil {
+		data["weight"] = *spec.Weight
+	}
+
+	if spec.ClientCertificateID != nil {
+		data["clientCertificateId"] = *spec.ClientCertificateID
+	}
+
+	addHealthCheck(data, spec.HealthCheck, spec.IP, spec.Port, spec.Method)
+
 	// Add siteID to associate target with site
 	if siteID != "" {
 		data["siteId"] = mustParseInt(siteID)
@@ -581,10 +939,8 @@ func (c *Client) CreateTarget(ctx context.Context, resourceID, siteID string, sp
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Validate content type
-	ct := resp.Header.Get("Content-Type")
-	if !strings.HasPrefix(ct, "application/json") {
-		return nil, fmt.Errorf("unexpected content-type %q status %d: %s", ct, resp.StatusCode, string(bodyBytes))
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, decodeAPIError("create target", resp, bodyBytes)
 	}
 
 	// Parse response
@@ -595,21 +951,157 @@ func (c *Client) CreateTarget(ctx context.Context, resourceID, siteID string, sp
 		Status  int    `json:"status,omitempty"`
 	}
 
-	if err := json.Unmarshal(bodyBytes, &result); err != nil {
-		return nil, fmt.Errorf("failed to decode target response: %w, body: %s", err, string(bodyBytes))
+	if err := decodeBody(resp, bodyBytes, &result); err != nil {
+		return nil, err
 	}
 
 	if !result.Success {
-		errMsg := "API request was not successful"
-		if result.Message != "" {
-			errMsg = fmt.Sprintf("%s: %s", errMsg, result.Message)
-		}
-		return nil, fmt.Errorf(errMsg)
+		return nil, decodeAPIError("create target", resp, bodyBytes)
+	}
+
+	return &result.Data, nil
+}
+
+// UpdateTarget changes the backend address, method, priority, weight, health check or enabled state of a target.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID the target belongs to
+//   - targetID: Target ID to update
+//   - spec: Desired target state; disabling a target keeps it in Pangolin
+//
+// Returns:
+//   - Updated Target object
+//   - Error if the target is not found or the update fails
+func (c *Client) UpdateTarget(ctx context.Context, resourceID, targetID string, spec TargetUpdateSpec) (*Target, error) {
+	data := map[string]interface{}{
+		"ip":      spec.IP,
+		"port":    spec.Port,
+		"method":  spec.Method,
+		"enabled": spec.Enabled,
+	}
+	if spec.Priority != 0 {
+		data["priority"] = spec.Priority
+	}
+	if spec.Weight != nil {
+		data["weight"] = *spec.Weight
+	}
+	if spec.ClientCertificateID != nil {
+		data["clientCertificateId"] = *spec.ClientCertificateID
+	}
+	addHealthCheck(data, spec.HealthCheck, spec.IP, spec.Port, spec.Method)
+
+	resp, err := c.makeRequest(ctx, "POST", fmt.Sprintf("resource/%s/target/%s", resourceID, targetID), data)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("update target", resp)
+	}
+
+	var result struct {
+		Success bool   `json:"success"`
+		Data    Target `json:"data"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, &APIError{Op: "update target", StatusCode: resp.StatusCode, Message: "API request was not successful"}
+	}
+	return &result.Data, nil
+}
+
+// addHealthCheck adds the health check fields of a target create or update
+// request to data. The check probes the target itself, over https for https
+// targets and http otherwise. A nil health check leaves data unchanged.
+func addHealthCheck(data map[string]interface{}, hc *TargetHealthCheck, ip string, port int32, method string) {
+	if hc == nil {
+		return
+	}
+	data["hcEnabled"] = hc.Enabled
+	if !hc.Enabled {
+		return
+	}
+	scheme := "http"
+	if method == "https" {
+		scheme = "https"
+	}
+	data["hcMode"] = "http"
+	data["hcScheme"] = scheme
+	data["hcHostname"] = ip
+	data["hcPort"] = port
+	data["hcPath"] = hc.Path
+	data["hcInterval"] = hc.Interval
+	data["hcTimeout"] = hc.Timeout
+	data["hcStatus"] = hc.Status
+}
+
+// UploadClientCertificate stores a client certificate for a resource so its targets
+// can present it to backends requiring mutual TLS.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID the certificate belongs to
+//   - name: Certificate name; uploading the same name again replaces the certificate
+//   - certPEM, keyPEM: PEM encoded certificate chain and private key
+//
+// Returns:
+//   - ClientCertificate with the ID to reference from TargetCreateSpec
+//   - Error if the upload fails
+func (c *Client) UploadClientCertificate(ctx context.Context, resourceID, name, certPEM, keyPEM string) (*ClientCertificate, error) {
+	data := map[string]interface{}{
+		"name":        name,
+		"certificate": certPEM,
+		"privateKey":  keyPEM,
 	}
 
+	resp, err := c.makeRequest(ctx, "PUT", fmt.Sprintf("resource/%s/client-certificate", resourceID), data)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError("upload client certificate", resp)
+	}
+
+	var result struct {
+		Success bool              `json:"success"`
+		Data    ClientCertificate `json:"data"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, &APIError{Op: "upload client certificate", StatusCode: resp.StatusCode, Message: "API request was not successful"}
+	}
 	return &result.Data, nil
 }
 
+// DeleteResource deletes a resource and its targets.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID to delete
+//
+// Returns error if deletion fails. A resource that no longer exists is not an error.
+func (c *Client) DeleteResource(ctx context.Context, resourceID string) error {
+	resp, err := c.makeRequest(ctx, "DELETE", fmt.Sprintf("resource/%s", resourceID), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return newAPIError("delete resource", resp)
+	}
+
+	return nil
+}
+
 // DeleteTarget deletes a target from a resource.
 //
 // Parameters:
@@ -617,7 +1109,7 @@ func (c *Client) CreateTarget(ctx context.Context, resourceID, siteID string, sp
 //   - resourceID: Resource ID that owns the target
 //   - targetID: Target ID to delete
 //
-// Returns error if deletion fails.
+// Returns error if deletion fails. A target that no longer exists is not an error.
 func (c *Client) DeleteTarget(ctx context.Context, resourceID, targetID string) error {
 	path := fmt.Sprintf("resource/%s/target/%s", resourceID, targetID)
 	resp, err := c.makeRequest(ctx, "DELETE", path, nil)
@@ -626,9 +1118,8 @@ func (c *Client) DeleteTarget(ctx context.Context, resourceID, targetID string)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return fmt.Errorf("delete target failed: status %d: %s", resp.StatusCode, string(b))
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return newAPIError("delete target", resp)
 	}
 
 	return nil
@@ -667,9 +1158,8 @@ func (c *Client) ListTargets(ctx context.Context, resourceID string) ([]Target,
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	ct := resp.Header.Get("Content-Type")
-	if !strings.HasPrefix(ct, "application/json") {
-		return nil, fmt.Errorf("unexpected content-type %q status %d: %s", ct, resp.StatusCode, string(bodyBytes))
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, decodeAPIError("list targets", resp, bodyBytes)
 	}
 
 	var result struct {
@@ -680,21 +1170,62 @@ func (c *Client) ListTargets(ctx context.Context, resourceID string) ([]Target,
 		Message string `json:"message,omitempty"`
 	}
 
-	if err := json.Unmarshal(bodyBytes, &result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w, body: %s", err, string(bodyBytes))
+	if err := decodeBody(resp, bodyBytes, &result); err != nil {
+		return nil, err
 	}
 
 	if !result.Success {
-		errMsg := "API request was not successful"
-		if result.Message != "" {
-			errMsg = fmt.Sprintf("%s: %s", errMsg, result.Message)
-		}
-		return nil, fmt.Errorf(errMsg)
+		return nil, decodeAPIError("list targets", resp, bodyBytes)
 	}
 
 	return result.Data.Targets, nil
 }
 
+// ListResourceEvents retrieves the server-side events recorded for a resource.
+//
+// Events explain provisioning problems that are otherwise only visible in the
+// Pangolin dashboard, such as certificate issuance errors.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID to query events for
+//
+// Returns:
+//   - Slice of ResourceEvent objects, oldest first
+//   - Empty slice if the Pangolin instance does not expose resource events (404)
+//   - Error if request fails
+func (c *Client) ListResourceEvents(ctx context.Context, resourceID string) ([]ResourceEvent, error) {
+	path := fmt.Sprintf("resource/%s/events?limit=1000&offset=0", resourceID)
+	resp, err := c.makeRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Older Pangolin versions do not expose resource events
+	if resp.StatusCode == http.StatusNotFound {
+		return []ResourceEvent{}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("list resource events", resp)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Events []ResourceEvent `json:"events"`
+		} `json:"data"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, &APIError{Op: "list resource events", StatusCode: resp.StatusCode, Message: "API request was not successful"}
+	}
+	return result.Data.Events, nil
+}
+
 // UpdateResource updates an existing resource's configuration.
 //
 // This method is used to update resource settings that cannot be set during creation,
@@ -712,7 +1243,11 @@ func (c *Client) ListTargets(ctx context.Context, resourceID string) ([]Target,
 // Updatable Fields:
 //   - SSO: Enable/disable SSO authentication
 //   - BlockAccess: Block access until authenticated (requires SSO enabled)
-//   - Name, Subdomain, Enabled, etc.
+//   - Subdomain, DomainID: Move an HTTP resource to another hostname
+//   - ProxyPort: Change the port a TCP/UDP resource is exposed on
+//   - ApplyRules: Enable/disable the resource's access rules
+//   - Tags: Replace the tags of the resource
+//   - Enabled, StickySession
 func (c *Client) UpdateResource(ctx context.Context, resourceID string, spec ResourceUpdateSpec) (*Resource, error) {
 	data := make(map[string]interface{})
 
@@ -726,6 +1261,24 @@ func (c *Client) UpdateResource(ctx context.Context, resourceID string, spec Res
 	if spec.Enabled != nil {
 		data["enabled"] = *spec.Enabled
 	}
+	if spec.DomainID != nil {
+		data["domainId"] = *spec.DomainID
+	}
+	if spec.StickySession != nil {
+		data["stickySession"] = *spec.StickySession
+	}
+	if spec.Subdomain != nil {
+		data["subdomain"] = *spec.Subdomain
+	}
+	if spec.ProxyPort != nil {
+		data["proxyPort"] = *spec.ProxyPort
+	}
+	if spec.ApplyRules != nil {
+		data["applyRules"] = *spec.ApplyRules
+	}
+	if spec.Tags != nil {
+		data["tags"] = *spec.Tags
+	}
 
 	if len(data) == 0 {
 		return nil, fmt.Errorf("no fields to update")
@@ -743,9 +1296,8 @@ func (c *Client) UpdateResource(ctx context.Context, resourceID string, spec Res
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	ct := resp.Header.Get("Content-Type")
-	if !strings.HasPrefix(ct, "application/json") {
-		return nil, fmt.Errorf("unexpected content-type %q status %d: %s", ct, resp.StatusCode, string(bodyBytes))
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, decodeAPIError("update resource", resp, bodyBytes)
 	}
 
 	var result struct {
@@ -755,19 +1307,12 @@ func (c *Client) UpdateResource(ctx context.Context, resourceID string, spec Res
 		Message string      `json:"message,omitempty"`
 		Status  int         `json:"status,omitempty"`
 	}
-	if err := json.Unmarshal(bodyBytes, &result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w, body: %s", err, string(bodyBytes))
+	if err := decodeBody(resp, bodyBytes, &result); err != nil {
+		return nil, err
 	}
 
 	if !result.Success {
-		errMsg := "API request was not successful"
-		if result.Message != "" {
-			errMsg = fmt.Sprintf("%s: %s", errMsg, result.Message)
-		}
-		if result.Status > 0 {
-			errMsg = fmt.Errorf("%s (status: %d)", errMsg, result.Status).Error()
-		}
-		return nil, fmt.Errorf(errMsg)
+		return nil, decodeAPIError("update resource", resp, bodyBytes)
 	}
 
 	return &result.Data, nil
@@ -779,3 +1324,125 @@ func mustParseInt(s string) int {
 	v, _ := strconv.Atoi(s)
 	return v
 }
+
+// ListResourceRules retrieves the access rules of a resource.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID to query rules for
+//
+// Returns:
+//   - Slice of ResourceRule objects
+//   - Error if request fails or resource not found
+func (c *Client) ListResourceRules(ctx context.Context, resourceID string) ([]ResourceRule, error) {
+	return listAll[ResourceRule](ctx, c, "list resource rules", fmt.Sprintf("/resource/%s/rules", resourceID), "rules")
+}
+
+// CreateResourceRule adds an access rule to a resource.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID to add the rule to
+//   - rule: Rule to create, RuleID is ignored
+//
+// Returns:
+//   - Created ResourceRule with its ID
+//   - Error if creation fails
+func (c *Client) CreateResourceRule(ctx context.Context, resourceID string, rule ResourceRule) (*ResourceRule, error) {
+	data := map[string]interface{}{
+		"action":   rule.Action,
+		"match":    rule.Match,
+		"value":    rule.Value,
+		"priority": rule.Priority,
+		"enabled":  rule.Enabled,
+	}
+
+	resp, err := c.makeRequest(ctx, "PUT", fmt.Sprintf("/resource/%s/rule", resourceID), data)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError("create resource rule", resp)
+	}
+
+	var result struct {
+		Success bool         `json:"success"`
+		Data    ResourceRule `json:"data"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, &APIError{Op: "create resource rule", StatusCode: resp.StatusCode, Message: "API request was not successful"}
+	}
+	return &result.Data, nil
+}
+
+// DeleteResourceRule removes an access rule from a resource.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID the rule belongs to
+//   - ruleID: Rule ID to delete
+//
+// Returns error if deletion fails. A rule that no longer exists is not an error.
+func (c *Client) DeleteResourceRule(ctx context.Context, resourceID string, ruleID int) error {
+	resp, err := c.makeRequest(ctx, "DELETE", fmt.Sprintf("/resource/%s/rule/%d", resourceID, ruleID), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return newAPIError("delete resource rule", resp)
+	}
+	return nil
+}
+
+// SetResourcePassword sets or removes the password protecting a resource.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID to protect
+//   - password: Password to set, empty removes password protection
+//
+// Returns error if the update fails or resource not found.
+func (c *Client) SetResourcePassword(ctx context.Context, resourceID, password string) error {
+	data := map[string]interface{}{"password": nil}
+	if password != "" {
+		data["password"] = password
+	}
+	return c.setResourceAuth(ctx, "set resource password", fmt.Sprintf("/resource/%s/password", resourceID), data)
+}
+
+// SetResourcePincode sets or removes the PIN code protecting a resource.
+//
+// Parameters:
+//   - ctx: Context for request cancellation
+//   - resourceID: Resource ID to protect
+//   - pincode: 6 digit PIN code to set, empty removes PIN code protection
+//
+// Returns error if the update fails or resource not found.
+func (c *Client) SetResourcePincode(ctx context.Context, resourceID, pincode string) error {
+	data := map[string]interface{}{"pincode": nil}
+	if pincode != "" {
+		data["pincode"] = pincode
+	}
+	return c.setResourceAuth(ctx, "set resource pincode", fmt.Sprintf("/resource/%s/pincode", resourceID), data)
+}
+
+// setResourceAuth posts an authentication setting of a resource
+func (c *Client) setResourceAuth(ctx context.Context, op, path string, data map[string]interface{}) error {
+	resp, err := c.makeRequest(ctx, "POST", path, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return newAPIError(op, resp)
+	}
+	return nil
+}
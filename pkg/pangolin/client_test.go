@@ -0,0 +1,267 @@
+package pangolin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientBasePath(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{name: "default", want: "/v1/orgs"},
+		{name: "other version", opts: []Option{WithBasePath("/v2")}, want: "/v2/orgs"},
+		{name: "nested path", opts: []Option{WithBasePath("api/v1/")}, want: "/api/v1/orgs"},
+		{name: "root", opts: []Option{WithBasePath("/")}, want: "/orgs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				got = req.URL.Path
+				_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[]}}`))
+			}))
+			defer server.Close()
+
+			if _, err := NewClient(server.URL+"/", "token", tt.opts...).ListOrganizations(context.Background()); err != nil {
+				t.Fatalf("ListOrganizations() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("request path = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientNonJSONResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html><body>Login required</body></html>"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	calls := map[string]func() error{
+		"ListOrganizations": func() error { _, err := client.ListOrganizations(context.Background()); return err },
+		"ListDomains":       func() error { _, err := client.ListDomains(context.Background(), "org1"); return err },
+		"ListSites":         func() error { _, err := client.ListSites(context.Background(), "org1"); return err },
+		"GetSiteByID":       func() error { _, err := client.GetSiteByID(context.Background(), 1); return err },
+	}
+	want := `unexpected content-type "text/html" (status 200): <html><body>Login required</body></html>`
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call()
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("%s() error = %v, want it to contain %q", name, err, want)
+			}
+		})
+	}
+}
+
+func TestClientHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = req.Header
+		_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[]}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", WithHeaders(map[string]string{
+		"CF-Access-Client-Id": "client-id",
+		"Authorization":       "Basic ignored",
+	}))
+	if _, err := client.ListOrganizations(context.Background()); err != nil {
+		t.Fatalf("ListOrganizations() error = %v", err)
+	}
+	if v := got.Get("CF-Access-Client-Id"); v != "client-id" {
+		t.Errorf("CF-Access-Client-Id = %q, want %q", v, "client-id")
+	}
+	if v := got.Get("Authorization"); v != "Bearer token" {
+		t.Errorf("Authorization = %q, want %q", v, "Bearer token")
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "7")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[]}}`))
+	}))
+	defer server.Close()
+
+	var observed []RateLimit
+	client := NewClient(server.URL, "token", WithRateLimitObserver(func(limit RateLimit) {
+		observed = append(observed, limit)
+	}))
+	if _, ok := client.RateLimit(); ok {
+		t.Fatalf("RateLimit() reported a rate limit before any request")
+	}
+	if _, err := client.ListOrganizations(context.Background()); err != nil {
+		t.Fatalf("ListOrganizations() error = %v", err)
+	}
+
+	want := RateLimit{Limit: 100, Remaining: 7, Reset: time.Unix(1700000000, 0)}
+	if got, ok := client.RateLimit(); !ok || got != want {
+		t.Errorf("RateLimit() = %+v, %v, want %+v, true", got, ok, want)
+	}
+	if len(observed) != 1 || observed[0] != want {
+		t.Errorf("observed rate limits = %+v, want [%+v]", observed, want)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    RateLimit
+		wantOK  bool
+	}{
+		{name: "none", headers: map[string]string{}},
+		{name: "limit only", headers: map[string]string{"X-RateLimit-Limit": "100"}},
+		{
+			name:    "legacy headers",
+			headers: map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "42"},
+			want:    RateLimit{Limit: 100, Remaining: 42},
+			wantOK:  true,
+		},
+		{
+			name:    "draft headers with policy",
+			headers: map[string]string{"RateLimit-Limit": "100, 100;w=60", "RateLimit-Remaining": "0"},
+			want:    RateLimit{Limit: 100},
+			wantOK:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for name, value := range tt.headers {
+				h.Set(name, value)
+			}
+			got, ok := parseRateLimit(h)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseRateLimit() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestClientFallbackAPIKey(t *testing.T) {
+	accepted := "new"
+	var used []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		used = append(used, key)
+		if key != accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"success":false,"message":"Invalid API key"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"data":{"orgs":[]}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "old", WithFallbackAPIKey("new"))
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListOrganizations(context.Background()); err != nil {
+			t.Fatalf("ListOrganizations() error = %v", err)
+		}
+	}
+	if want := []string{"old", "new", "new"}; strings.Join(used, ",") != strings.Join(want, ",") {
+		t.Errorf("keys used = %v, want %v", used, want)
+	}
+	if !client.UsingFallbackAPIKey() {
+		t.Errorf("UsingFallbackAPIKey() = false after the API key was rejected")
+	}
+
+	accepted, used = "none", nil
+	if _, err := client.ListOrganizations(context.Background()); !IsUnauthorized(err) {
+		t.Errorf("ListOrganizations() error = %v, want unauthorized when both keys are rejected", err)
+	}
+	if !client.UsingFallbackAPIKey() {
+		t.Errorf("UsingFallbackAPIKey() = false, want the key in use kept when both keys are rejected")
+	}
+}
+
+func TestCreateResourceTags(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"success":true,"data":{"resourceId":42}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	_, err := client.CreateResource(context.Background(), "org1", "1", ResourceCreateSpec{
+		Name: "db", Protocol: "tcp", ProxyPort: 5432, Tags: map[string]string{"team": "data"},
+	})
+	if err != nil {
+		t.Fatalf("CreateResource() error = %v", err)
+	}
+	tags, _ := got["tags"].(map[string]interface{})
+	if tags["team"] != "data" {
+		t.Errorf("tags = %v, want team=data", got["tags"])
+	}
+}
+
+func TestUpdateTargetPriority(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"success":true,"data":{"targetId":7}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	weight := int32(10)
+	_, err := client.UpdateTarget(context.Background(), "42", "7", TargetUpdateSpec{
+		IP: "10.0.0.2", Port: 8080, Method: "http", Enabled: true, Priority: 200, Weight: &weight,
+	})
+	if err != nil {
+		t.Fatalf("UpdateTarget() error = %v", err)
+	}
+	if got["priority"] != float64(200) || got["weight"] != float64(10) {
+		t.Errorf("priority = %v, weight = %v, want 200 and 10", got["priority"], got["weight"])
+	}
+}
+
+func TestIsRouteNotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   bool
+	}{
+		{name: "missing object", status: http.StatusNotFound,
+			body: `{"success":false,"error":true,"message":"Organization with ID alpha not found","status":404}`},
+		{name: "unknown url", status: http.StatusNotFound, want: true,
+			body: `{"data":null,"success":false,"error":true,"message":"The requests url is not found - /v1/org/alpha","status":404}`},
+		{name: "no envelope", status: http.StatusNotFound, body: "404 page not found", want: true},
+		{name: "other status", status: http.StatusMethodNotAllowed, body: "Method Not Allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewClient(server.URL, "token").GetOrganization(context.Background(), "alpha")
+			if got := IsRouteNotFound(err); got != tt.want {
+				t.Errorf("IsRouteNotFound(%v) = %v, want %v", err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package pangolin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is returned when the Pangolin API answers a request with an error
+// status code or an unsuccessful response envelope.
+//
+// Callers inspect it with errors.As, or with the IsNotFound, IsRouteNotFound,
+// IsUnauthorized, IsRateLimited, IsUnsupported and IsConflict helpers.
+type APIError struct {
+	// Op is the operation that failed, e.g. "list sites"
+	Op string
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Message is the error message reported by the API, or the raw response
+	// body if it could not be decoded
+	Message string
+	// Success is the decoded "success" field of the response envelope
+	Success bool
+	// Detail is the decoded "error" field of the response envelope, if any
+	Detail interface{}
+	// RetryAfter is the wait requested by the Retry-After header of the
+	// response, zero if there was none
+	RetryAfter time.Duration
+
+	// enveloped is set when the body decoded as a Pangolin response envelope
+	enveloped bool
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s failed: status %d", e.Op, e.StatusCode)
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	return msg
+}
+
+// newAPIError builds an APIError from an error response. At most 1KiB of the
+// body is read.
+func newAPIError(op string, resp *http.Response) *APIError {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return decodeAPIError(op, resp, b)
+}
+
+// decodeAPIError builds an APIError from a response and its body, using the
+// message of the Pangolin response envelope when there is one.
+func decodeAPIError(op string, resp *http.Response, body []byte) *APIError {
+	statusCode := resp.StatusCode
+	apiErr := &APIError{Op: op, StatusCode: statusCode, RetryAfter: parseRetryAfter(resp.Header)}
+
+	var envelope struct {
+		Success bool        `json:"success"`
+		Error   interface{} `json:"error,omitempty"`
+		Message string      `json:"message,omitempty"`
+		Status  int         `json:"status,omitempty"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+
+	apiErr.enveloped = true
+	apiErr.Success = envelope.Success
+	apiErr.Detail = envelope.Error
+	apiErr.Message = envelope.Message
+	if apiErr.Message == "" {
+		if s, ok := envelope.Error.(string); ok {
+			apiErr.Message = s
+		}
+	}
+	if apiErr.Message == "" && !apiErr.Success {
+		apiErr.Message = "API request was not successful"
+	}
+	// Some endpoints answer 200 with the real status in the envelope
+	if envelope.Status >= 400 && statusCode < 400 {
+		apiErr.StatusCode = envelope.Status
+	}
+	return apiErr
+}
+
+// hasStatus reports whether err is an APIError with one of the given status codes.
+func hasStatus(err error, codes ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an APIError for a missing object.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// routeNotFoundMessage starts the message Pangolin answers requests for unknown
+// URLs with
+const routeNotFoundMessage = "The requests url is not found"
+
+// IsRouteNotFound reports whether err is an APIError for a route the Pangolin
+// server does not serve, as opposed to a missing object: a 404 whose body is
+// not a Pangolin response envelope, e.g. from a reverse proxy or an older
+// server, or that carries Pangolin's message for unknown URLs.
+func IsRouteNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		return false
+	}
+	return !apiErr.enveloped || strings.HasPrefix(apiErr.Message, routeNotFoundMessage)
+}
+
+// IsUnauthorized reports whether err is an APIError for a rejected or
+// insufficiently privileged API key.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether err is an APIError for a rate limited request.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsUnsupported reports whether err is an APIError for an endpoint or method
+// the Pangolin server does not implement.
+func IsUnsupported(err error) bool {
+	return hasStatus(err, http.StatusMethodNotAllowed, http.StatusNotImplemented)
+}
+
+// IsInvalid reports whether err is an APIError for a request the API rejected
+// as malformed or invalid, e.g. an unknown field value.
+func IsInvalid(err error) bool {
+	return hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+}
+
+// IsConflict reports whether err is an APIError for an object that already exists.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// RetryAfter returns the wait the Pangolin API requested with a rate limited
+// response, zero if err is not rate limited or no wait was requested.
+func RetryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		return 0
+	}
+	return apiErr.RetryAfter
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP
+// date. Missing, malformed and past values are zero.
+func parseRetryAfter(h http.Header) time.Duration {
+	value := strings.TrimSpace(h.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
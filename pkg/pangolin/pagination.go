@@ -0,0 +1,99 @@
+package pangolin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// listPageSize is the number of items requested per page of a list endpoint
+	listPageSize = 1000
+	// maxListPages bounds the pages fetched by a single list call, so a server
+	// with inconsistent pagination metadata cannot make it loop forever
+	maxListPages = 100
+)
+
+// pagination is the paging metadata returned by Pangolin list endpoints
+type pagination struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// listAll fetches every page of a list endpoint and returns the items found
+// under key in the response data, e.g. "sites" for /org/{orgId}/sites.
+//
+// Pages are requested until the total reported by the pagination metadata is
+// reached. Without metadata, a short or empty page ends the listing.
+func listAll[T any](ctx context.Context, c *Client, op, path, key string) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	var all []T
+	offset := 0
+	for page := 0; page < maxListPages; page++ {
+		items, meta, err := listPage[T](ctx, c, op, fmt.Sprintf("%s%slimit=%d&offset=%d", path, sep, listPageSize, offset), key)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		offset += len(items)
+
+		if len(items) == 0 {
+			return all, nil
+		}
+		if meta != nil {
+			if offset >= meta.Total {
+				return all, nil
+			}
+		} else if len(items) < listPageSize {
+			return all, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: stopped after %d pages (%d items)", op, maxListPages, len(all))
+}
+
+// listPage fetches a single page of a list endpoint. The pagination metadata
+// is nil if the response has none.
+func listPage[T any](ctx context.Context, c *Client, op, path, key string) ([]T, *pagination, error) {
+	resp, err := c.makeRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, newAPIError(op, resp)
+	}
+
+	var result struct {
+		Success bool                       `json:"success"`
+		Data    map[string]json.RawMessage `json:"data"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, nil, err
+	}
+	if !result.Success {
+		return nil, nil, &APIError{Op: op, StatusCode: resp.StatusCode, Message: "API request was not successful"}
+	}
+
+	var items []T
+	if raw, ok := result.Data[key]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+	}
+	var meta *pagination
+	if raw, ok := result.Data["pagination"]; ok && string(raw) != "null" {
+		meta = &pagination{}
+		if err := json.Unmarshal(raw, meta); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode pagination: %w", err)
+		}
+	}
+	return items, meta, nil
+}
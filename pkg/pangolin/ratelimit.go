@@ -0,0 +1,86 @@
+package pangolin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is the rate limit state reported by the API in the headers of its
+// latest response.
+type RateLimit struct {
+	// Limit is the number of requests allowed per window, 0 if not reported
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends, zero if not reported
+	Reset time.Time
+}
+
+// rateLimitState holds the latest RateLimit seen by a client. It is shared by
+// pointer, as requests are made on copies of the client.
+type rateLimitState struct {
+	mu       sync.Mutex
+	latest   RateLimit
+	seen     bool
+	observer func(RateLimit)
+}
+
+// WithRateLimitObserver sets a function called with the rate limit reported by
+// each response that carries rate limit headers, e.g. to export it as metrics.
+func WithRateLimitObserver(observer func(RateLimit)) Option {
+	return func(c *Client) {
+		c.rateLimit.observer = observer
+	}
+}
+
+// RateLimit returns the rate limit reported by the latest response carrying
+// rate limit headers, and false if no response did so far.
+func (c *Client) RateLimit() (RateLimit, bool) {
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	return c.rateLimit.latest, c.rateLimit.seen
+}
+
+// observe records the rate limit reported by the headers of a response
+func (s *rateLimitState) observe(h http.Header) {
+	limit, ok := parseRateLimit(h)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	s.latest, s.seen = limit, true
+	observer := s.observer
+	s.mu.Unlock()
+	if observer != nil {
+		observer(limit)
+	}
+}
+
+// parseRateLimit reads the X-RateLimit-* headers, or the RateLimit-* headers of
+// the IETF draft, of a response. Only a response reporting the remaining
+// requests counts as reporting a rate limit. Reset is accepted both as seconds
+// until the reset and as a Unix timestamp.
+func parseRateLimit(h http.Header) (RateLimit, bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		remaining, err := strconv.Atoi(strings.TrimSpace(h.Get(prefix + "Remaining")))
+		if err != nil {
+			continue
+		}
+		limit := RateLimit{Remaining: remaining}
+		// The IETF draft allows a quota policy after the limit, e.g. "100, 100;w=60"
+		value, _, _ := strings.Cut(h.Get(prefix+"Limit"), ",")
+		limit.Limit, _ = strconv.Atoi(strings.TrimSpace(value))
+		if reset, err := strconv.ParseInt(strings.TrimSpace(h.Get(prefix+"Reset")), 10, 64); err == nil && reset >= 0 {
+			if reset > 1_000_000_000 {
+				limit.Reset = time.Unix(reset, 0)
+			} else {
+				limit.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+			}
+		}
+		return limit, true
+	}
+	return RateLimit{}, false
+}
@@ -0,0 +1,47 @@
+package pangolin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxBodySnippet bounds how much of an unexpected response body is quoted in
+// errors
+const maxBodySnippet = 256
+
+// decodeResponse reads the body of a successful response and decodes it as JSON
+// into v, see decodeBody.
+func decodeResponse(resp *http.Response, v interface{}) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	return decodeBody(resp, body, v)
+}
+
+// decodeBody decodes the JSON body of a successful response into v. A body that
+// is not JSON, such as the HTML error page of a reverse proxy in front of
+// Pangolin, is reported with its content type, status and the start of the
+// body instead of the bare decoding error.
+func decodeBody(resp *http.Response, body []byte, v interface{}) error {
+	err := json.Unmarshal(body, v)
+	if err == nil {
+		return nil
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		return fmt.Errorf("unexpected content-type %q (status %d): %s", ct, resp.StatusCode, bodySnippet(body))
+	}
+	return fmt.Errorf("failed to decode response: %w, body: %s", err, bodySnippet(body))
+}
+
+// bodySnippet returns the start of a response body for error messages
+func bodySnippet(body []byte) string {
+	s := strings.TrimSpace(string(body))
+	if len(s) > maxBodySnippet {
+		s = s[:maxBodySnippet] + "..."
+	}
+	return s
+}
@@ -11,6 +11,12 @@ type Organization struct {
 	Subnet string `json:"subnet"`
 }
 
+// ServerInfo describes the Pangolin server version and the optional features it supports
+type ServerInfo struct {
+	Version      string   `json:"version"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
 // Domain represents a Pangolin domain
 type Domain struct {
 	DomainID      string `json:"domainId"`
@@ -65,6 +71,13 @@ type Site struct {
 	RemoteSubnets       string `json:"remoteSubnets"`
 }
 
+// NewtCredentials holds the identifier and secret a Newt client uses to
+// authenticate against a site
+type NewtCredentials struct {
+	NewtID string `json:"newtId"`
+	Secret string `json:"secret"`
+}
+
 // ResourceCreateSpec defines the specification for creating a resource
 type ResourceCreateSpec struct {
 	Name     string `json:"name"`
@@ -78,6 +91,8 @@ type ResourceCreateSpec struct {
 	// TCP/UDP-specific fields
 	ProxyPort   int32 `json:"proxyPort,omitempty"`
 	EnableProxy bool  `json:"enableProxy,omitempty"`
+	// Tags shown on the resource in the Pangolin dashboard
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // ResourceUpdateSpec defines the specification for updating a resource
@@ -86,6 +101,32 @@ type ResourceUpdateSpec struct {
 	SSO         *bool `json:"sso,omitempty"`
 	BlockAccess *bool `json:"blockAccess,omitempty"`
 	Enabled     *bool `json:"enabled,omitempty"`
+	// DomainID moves an HTTP resource to another domain
+	DomainID *string `json:"domainId,omitempty"`
+	// StickySession routes a client to the same target for the session
+	StickySession *bool `json:"stickySession,omitempty"`
+	// Subdomain of an HTTP resource
+	Subdomain *string `json:"subdomain,omitempty"`
+	// ProxyPort of a TCP/UDP resource
+	ProxyPort *int32 `json:"proxyPort,omitempty"`
+	// ApplyRules enables the evaluation of the resource's access rules
+	ApplyRules *bool `json:"applyRules,omitempty"`
+	// Tags replaces the tags of the resource, an empty map removes them. A
+	// pointer, so an empty map is sent and the struct stays comparable.
+	Tags *map[string]string `json:"tags,omitempty"`
+}
+
+// ResourceRule is an access rule of a resource. Rules are evaluated by
+// ascending priority and the first match decides.
+type ResourceRule struct {
+	RuleID int `json:"ruleId,omitempty"`
+	// Action is ACCEPT or DROP
+	Action string `json:"action"`
+	// Match is CIDR, IP or PATH
+	Match    string `json:"match"`
+	Value    string `json:"value"`
+	Priority int    `json:"priority"`
+	Enabled  bool   `json:"enabled"`
 }
 
 // TargetCreateSpec defines the specification for creating a target
@@ -97,6 +138,35 @@ type TargetCreateSpec struct {
 	Path          string `json:"path,omitempty"`
 	PathMatchType string `json:"pathMatchType,omitempty"`
 	Priority      int32  `json:"priority,omitempty"`
+	Weight        *int32 `json:"weight,omitempty"`
+	// ClientCertificateID references an uploaded certificate presented to the backend (mTLS)
+	ClientCertificateID *int `json:"clientCertificateId,omitempty"`
+	// HealthCheck is left unchanged when nil
+	HealthCheck *TargetHealthCheck `json:"-"`
+}
+
+// TargetUpdateSpec defines the desired state of an existing target
+type TargetUpdateSpec struct {
+	IP      string `json:"ip"`
+	Port    int32  `json:"port"`
+	Method  string `json:"method"`
+	Enabled bool   `json:"enabled"`
+	// Priority is left unchanged when zero
+	Priority int32  `json:"priority,omitempty"`
+	Weight   *int32 `json:"weight,omitempty"`
+	// HealthCheck is left unchanged when nil
+	HealthCheck *TargetHealthCheck `json:"-"`
+	// ClientCertificateID is left unchanged when nil
+	ClientCertificateID *int `json:"clientCertificateId,omitempty"`
+}
+
+// TargetHealthCheck defines the HTTP health check Pangolin runs against a target
+type TargetHealthCheck struct {
+	Enabled  bool
+	Path     string
+	Interval int32  // seconds between checks
+	Timeout  int32  // seconds to wait for a response
+	Status   *int32 // expected HTTP status, nil accepts any 2xx
 }
 
 // Resource represents a Pangolin resource
@@ -114,6 +184,11 @@ type Resource struct {
 	Enabled    bool   `json:"enabled"`
 	SSO        bool   `json:"sso"`
 	BlockAccess bool  `json:"blockAccess"`
+	ProxyPort   int32 `json:"proxyPort,omitempty"`
+	// ProxyAddress is the relay address the API assigned to a TCP/UDP resource, if any
+	ProxyAddress string `json:"proxyAddress,omitempty"`
+	// Tags of the resource, nil if it has none
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // EffectiveID returns a string identifier usable in URL paths.
@@ -127,6 +202,15 @@ func (r *Resource) EffectiveID() string {
 	return ""
 }
 
+// ResourceEvent represents a server-side event recorded for a resource
+// (e.g. certificate issuance failures)
+type ResourceEvent struct {
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+}
+
 // Target represents a Pangolin target
 type Target struct {
 	ID       string `json:"id,omitempty"`
@@ -137,6 +221,24 @@ type Target struct {
 	Method   string `json:"method,omitempty"`
 	Enabled  bool   `json:"enabled,omitempty"`
 	Priority int    `json:"priority,omitempty"`
+	Weight   *int32 `json:"weight,omitempty"`
+
+	ClientCertificateID *int `json:"clientCertificateId,omitempty"`
+
+	// Health check settings and the health last observed by Pangolin
+	// ("healthy", "unhealthy" or "unknown")
+	HCEnabled  bool   `json:"hcEnabled,omitempty"`
+	HCPath     string `json:"hcPath,omitempty"`
+	HCInterval int32  `json:"hcInterval,omitempty"`
+	HCTimeout  int32  `json:"hcTimeout,omitempty"`
+	HCStatus   *int32 `json:"hcStatus,omitempty"`
+	HCHealth   string `json:"hcHealth,omitempty"`
+}
+
+// ClientCertificate is a client certificate stored in Pangolin and presented to backends for mTLS
+type ClientCertificate struct {
+	CertificateID int    `json:"certificateId"`
+	Name          string `json:"name,omitempty"`
 }
 
 // EffectiveID returns the target ID as a string
@@ -0,0 +1,22 @@
+package pangolin
+
+import "testing"
+
+func TestResourceEffectiveID(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource Resource
+		want     string
+	}{
+		{name: "id set", resource: Resource{ID: "12", ResourceID: 34}, want: "12"},
+		{name: "resourceId set", resource: Resource{ResourceID: 34}, want: "34"},
+		{name: "both empty", resource: Resource{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resource.EffectiveID(); got != tt.want {
+				t.Errorf("EffectiveID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}